@@ -4,8 +4,10 @@ import (
 	"api-page/main/src/cache"
 	"api-page/main/src/configs"
 	"api-page/main/src/database"
+	"api-page/main/src/jobs"
 	"api-page/main/src/middleware"
 	"api-page/main/src/routes"
+	"context"
 	"fmt"
 	"os"
 
@@ -36,11 +38,19 @@ func main() {
 		panic(fmt.Sprintf("Could not connect to the database: %v", err))
 	}
 
-	// Open Valkey connection.
-	if err := cache.OpenValkeyConnection(); err != nil {
+	// Open cache connection.
+	if err := cache.OpenCacheConnection(); err != nil {
 		panic(fmt.Sprintf("Could not connect to the cache: %v", err))
 	}
-	defer cache.Valkey.Close()
+	defer cache.Default.Close()
+
+	// Start background jobs.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobs.StartVersionScheduleJob(ctx)
+	jobs.StartWebhookDeliveryJob(ctx)
+	jobs.StartRedirectHitJob(ctx)
+	jobs.StartContentEventListener(ctx)
 
 	// Register a public routes_util for app.
 	routes.PublicRoutes(app)
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// fetchLockPrefix prefixes the keys of the locks that guard the rebuild of a cached value.
+	fetchLockPrefix = "locks:"
+	// fetchLockPollInterval is the interval at which a waiting worker checks whether the value was rebuilt.
+	fetchLockPollInterval = 50 * time.Millisecond
+	// defaultFetchLockTTL is the lifetime of a rebuild lock when CACHE_LOCK_TTL is not set.
+	defaultFetchLockTTL = 10 * time.Second
+)
+
+// fetchGroup coalesces the rebuilds of a key within this process.
+var fetchGroup singleflight.Group
+
+// fetchEntry is a cached value with the moment until which it is fresh.
+// After that moment the value is served stale while it is rebuilt, until the key expires.
+type fetchEntry[T any] struct {
+	Value      T         `json:"value"`
+	FreshUntil time.Time `json:"freshUntil"`
+}
+
+// Fetch func to get the value of the key from the default store, or load and store it on a miss.
+// Concurrent misses of a key are coalesced in this process and guarded by a lock in the store,
+// so a single worker loads the value while the others wait for it. A stale value is served
+// immediately while one worker refreshes it in the background. A nil value from load is not stored.
+func Fetch[T any](key string, load func() (*T, error)) (*T, error) {
+	entry, err := getFetchEntry[T](key)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil {
+		if time.Now().After(entry.FreshUntil) {
+			go func() {
+				_, _, _ = fetchGroup.Do("refresh:"+key, func() (any, error) {
+					return refreshFetchEntry(key, load)
+				})
+			}()
+		}
+
+		return &entry.Value, nil
+	}
+
+	value, err, _ := fetchGroup.Do(key, func() (any, error) {
+		return rebuildFetchEntry(key, load)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*T), nil
+}
+
+// getFetchEntry gets the entry of the key, a missing or unreadable entry is returned as nil.
+func getFetchEntry[T any](key string) (*fetchEntry[T], error) {
+	value, err := Default.Get(context.Background(), key)
+	if errors.Is(err, ErrMiss) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entry := &fetchEntry[T]{}
+	if err := json.Unmarshal(value, entry); err != nil {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// setFetchEntry stores the value as fresh for the jittered expiration, and keeps it for CACHE_STALE_TTL longer.
+func setFetchEntry[T any](key string, value *T) error {
+	ttl, err := GetTTL()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(fetchEntry[T]{Value: *value, FreshUntil: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+
+	return Default.Set(context.Background(), key, data, ttl+durationFromEnv("CACHE_STALE_TTL", 0))
+}
+
+// rebuildFetchEntry loads and stores the value of a missing key while holding its lock.
+// When another worker holds the lock, it waits for that worker to store the value.
+func rebuildFetchEntry[T any](key string, load func() (*T, error)) (*T, error) {
+	ctx := context.Background()
+	lockKey := fetchLockPrefix + key
+	lockTTL := durationFromEnv("CACHE_LOCK_TTL", defaultFetchLockTTL)
+	token := uuid.NewString()
+	deadline := time.Now().Add(lockTTL)
+
+	for {
+		locked, err := Default.Lock(ctx, lockKey, token, lockTTL)
+		if err != nil {
+			return nil, err
+		} else if locked {
+			defer func() {
+				_ = Default.Unlock(ctx, lockKey, token)
+			}()
+
+			return loadFetchEntry(key, load)
+		}
+
+		time.Sleep(fetchLockPollInterval)
+
+		if entry, err := getFetchEntry[T](key); err != nil {
+			return nil, err
+		} else if entry != nil {
+			return &entry.Value, nil
+		}
+
+		if time.Now().After(deadline) {
+			return load()
+		}
+	}
+}
+
+// refreshFetchEntry reloads the stale value of a key, unless another worker already holds its lock.
+// When the value no longer exists, the stale value is deleted.
+func refreshFetchEntry[T any](key string, load func() (*T, error)) (*T, error) {
+	ctx := context.Background()
+	lockKey := fetchLockPrefix + key
+	token := uuid.NewString()
+
+	locked, err := Default.Lock(ctx, lockKey, token, durationFromEnv("CACHE_LOCK_TTL", defaultFetchLockTTL))
+	if err != nil || !locked {
+		return nil, err
+	}
+	defer func() {
+		_ = Default.Unlock(ctx, lockKey, token)
+	}()
+
+	value, err := loadFetchEntry(key, load)
+	if err == nil && value == nil {
+		_ = Delete(key)
+	}
+
+	return value, err
+}
+
+// loadFetchEntry loads the value of a key and stores it when it exists.
+func loadFetchEntry[T any](key string, load func() (*T, error)) (*T, error) {
+	value, err := load()
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	_ = setFetchEntry(key, value)
+
+	return value, nil
+}
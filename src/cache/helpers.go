@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"os"
+	"time"
+)
+
+// Get func to get the JSON value of the key from the default store.
+func Get[T any](key string) (*T, error) {
+	value, err := Default.Get(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(T)
+	if err := json.Unmarshal(value, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Set func to set the value of the key as JSON in the default store with the configured, jittered expiration.
+func Set[T any](key string, value T) error {
+	ttl, err := GetTTL()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return Default.Set(context.Background(), key, data, ttl)
+}
+
+// Exists func to check if the key exists in the default store.
+func Exists(key string) (bool, error) {
+	return Default.Exists(context.Background(), key)
+}
+
+// Delete func to delete the keys from the default store.
+func Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return Default.Delete(context.Background(), keys...)
+}
+
+// Keys func to get all keys that start with the prefix from the default store.
+func Keys(prefix string) ([]string, error) {
+	return Default.Keys(context.Background(), prefix)
+}
+
+// AddMembers func to add the members to the set of the key in the default store.
+// The set lives as long as a value can live, so it outlives the values of the keys it holds.
+func AddMembers(key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	expiration, err := GetExpiration()
+	if err != nil {
+		return err
+	}
+	ttl := expiration + durationFromEnv("CACHE_EXPIRATION_JITTER", 0) + durationFromEnv("CACHE_STALE_TTL", 0)
+
+	return Default.AddMembers(context.Background(), key, ttl, members...)
+}
+
+// Members func to get the members of the set of the key from the default store.
+func Members(key string) ([]string, error) {
+	return Default.Members(context.Background(), key)
+}
+
+// DeleteByPrefix func to delete all keys that start with the prefix from the default store.
+func DeleteByPrefix(prefix string) error {
+	keys, err := Keys(prefix)
+	if err != nil {
+		return err
+	}
+
+	return Delete(keys...)
+}
+
+// Publish func to publish the message on the channel of the default store.
+func Publish(channel string, message []byte) error {
+	return Default.Publish(context.Background(), channel, message)
+}
+
+// Subscribe func to subscribe to the channel of the default store until ctx is done.
+func Subscribe(ctx context.Context, channel string, fn func(message []byte)) error {
+	return Default.Subscribe(ctx, channel, fn)
+}
+
+// GetExpiration func to get the expiration of cached values from CACHE_EXPIRATION.
+// VALKEY_EXPIRATION is still read when CACHE_EXPIRATION is not set.
+func GetExpiration() (time.Duration, error) {
+	expiration := os.Getenv("CACHE_EXPIRATION")
+	if expiration == "" {
+		expiration = os.Getenv("VALKEY_EXPIRATION")
+	}
+
+	return time.ParseDuration(expiration)
+}
+
+// GetTTL func to get the expiration with a random jitter of up to CACHE_EXPIRATION_JITTER added,
+// so keys that are written together do not expire together.
+func GetTTL() (time.Duration, error) {
+	ttl, err := GetExpiration()
+	if err != nil {
+		return 0, err
+	}
+
+	if jitter := durationFromEnv("CACHE_EXPIRATION_JITTER", 0); jitter > 0 {
+		ttl += rand.N(jitter)
+	}
+
+	return ttl, nil
+}
+
+// durationFromEnv gets a duration from the environment, an empty or invalid value counts as the fallback.
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || duration < 0 {
+		return fallback
+	}
+
+	return duration
+}
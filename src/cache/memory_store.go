@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store that evicts the least recently used key when it is full.
+type MemoryStore struct {
+	mu          sync.Mutex
+	size        int
+	items       map[string]*list.Element
+	order       *list.List
+	subscribers map[string]map[*memorySubscriber]struct{}
+}
+
+type memoryItem struct {
+	key       string
+	value     []byte
+	members   map[string]struct{}
+	expiresAt time.Time
+}
+
+type memorySubscriber struct {
+	fn func(message []byte)
+}
+
+// NewMemoryStore func to create an in-memory store that holds at most size keys.
+func NewMemoryStore(size int) *MemoryStore {
+	return &MemoryStore{
+		size:        size,
+		items:       make(map[string]*list.Element),
+		order:       list.New(),
+		subscribers: make(map[string]map[*memorySubscriber]struct{}),
+	}
+}
+
+// Get method to get the value of the key.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element := s.lookup(key)
+	if element == nil {
+		return nil, ErrMiss
+	}
+	s.order.MoveToFront(element)
+
+	value := element.Value.(*memoryItem).value
+	return append([]byte(nil), value...), nil
+}
+
+// Set method to set the value of the key.
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, value, ttl)
+
+	return nil
+}
+
+// Exists method to check if the key exists.
+func (s *MemoryStore) Exists(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lookup(key) != nil, nil
+}
+
+// Delete method to delete the keys.
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := s.items[key]; ok {
+			s.remove(element)
+		}
+	}
+
+	return nil
+}
+
+// Keys method to get all keys that start with the prefix.
+func (s *MemoryStore) Keys(_ context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0)
+	for key := range s.items {
+		if strings.HasPrefix(key, prefix) && s.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// AddMembers method to add the members to the set of the key.
+func (s *MemoryStore) AddMembers(_ context.Context, key string, ttl time.Duration, members ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := &memoryItem{key: key, members: make(map[string]struct{}, len(members))}
+	if element := s.lookup(key); element != nil {
+		for member := range element.Value.(*memoryItem).members {
+			item.members[member] = struct{}{}
+		}
+	}
+	for _, member := range members {
+		item.members[member] = struct{}{}
+	}
+	s.setItem(item, ttl)
+
+	return nil
+}
+
+// Members method to get the members of the set of the key.
+func (s *MemoryStore) Members(_ context.Context, key string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]string, 0)
+	if element := s.lookup(key); element != nil {
+		for member := range element.Value.(*memoryItem).members {
+			members = append(members, member)
+		}
+	}
+
+	return members, nil
+}
+
+// Lock method to acquire the lock of the key.
+func (s *MemoryStore) Lock(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lookup(key) != nil {
+		return false, nil
+	}
+	s.set(key, []byte(token), ttl)
+
+	return true, nil
+}
+
+// Unlock method to release the lock of the key.
+func (s *MemoryStore) Unlock(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element := s.lookup(key); element != nil && string(element.Value.(*memoryItem).value) == token {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// Publish method to deliver the message to the subscribers of the channel in this process.
+func (s *MemoryStore) Publish(_ context.Context, channel string, message []byte) error {
+	s.mu.Lock()
+	subscribers := make([]*memorySubscriber, 0, len(s.subscribers[channel]))
+	for subscriber := range s.subscribers[channel] {
+		subscribers = append(subscribers, subscriber)
+	}
+	s.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.fn(append([]byte(nil), message...))
+	}
+
+	return nil
+}
+
+// Subscribe method to receive the messages of the channel until ctx is done.
+func (s *MemoryStore) Subscribe(ctx context.Context, channel string, fn func(message []byte)) error {
+	subscriber := &memorySubscriber{fn: fn}
+
+	s.mu.Lock()
+	if s.subscribers[channel] == nil {
+		s.subscribers[channel] = make(map[*memorySubscriber]struct{})
+	}
+	s.subscribers[channel][subscriber] = struct{}{}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	delete(s.subscribers[channel], subscriber)
+	if len(s.subscribers[channel]) == 0 {
+		delete(s.subscribers, channel)
+	}
+	s.mu.Unlock()
+
+	return ctx.Err()
+}
+
+// Ping method to verify the store, the in-memory store is always reachable.
+func (s *MemoryStore) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close method to drop all keys of the store.
+func (s *MemoryStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = make(map[string]*list.Element)
+	s.order.Init()
+}
+
+// set stores the value of the key as the most recently used and evicts the least recently used keys when the store is full.
+func (s *MemoryStore) set(key string, value []byte, ttl time.Duration) {
+	s.setItem(&memoryItem{key: key, value: append([]byte(nil), value...)}, ttl)
+}
+
+// setItem stores the item as the most recently used and evicts the least recently used keys when the store is full.
+func (s *MemoryStore) setItem(item *memoryItem, ttl time.Duration) {
+	key := item.key
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, ok := s.items[key]; ok {
+		element.Value = item
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.items[key] = s.order.PushFront(item)
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+}
+
+// lookup returns the element of the key and removes it when it is expired.
+func (s *MemoryStore) lookup(key string) *list.Element {
+	element, ok := s.items[key]
+	if !ok {
+		return nil
+	}
+
+	item := element.Value.(*memoryItem)
+	if !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		s.remove(element)
+		return nil
+	}
+
+	return element
+}
+
+// remove deletes the element from the map and the recency list.
+func (s *MemoryStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*memoryItem).key)
+}
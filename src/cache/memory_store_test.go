@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(2)
+
+	_ = store.Set(ctx, "a", []byte("1"), 0)
+	_ = store.Set(ctx, "b", []byte("2"), 0)
+
+	// Reading a makes b the least recently used key.
+	if _, err := store.Get(ctx, "a"); err != nil {
+		t.Fatalf("Get(a): %v", err)
+	}
+	_ = store.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := store.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get(b) error = %v, want %v", err, ErrMiss)
+	}
+	for _, key := range []string{"a", "c"} {
+		if exists, _ := store.Exists(ctx, key); !exists {
+			t.Errorf("key %s evicted, want it kept", key)
+		}
+	}
+}
+
+func TestMemoryStoreSetMovesKeyToFront(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(2)
+
+	_ = store.Set(ctx, "a", []byte("1"), 0)
+	_ = store.Set(ctx, "b", []byte("2"), 0)
+	_ = store.Set(ctx, "a", []byte("3"), 0)
+	_ = store.Set(ctx, "c", []byte("4"), 0)
+
+	if exists, _ := store.Exists(ctx, "b"); exists {
+		t.Fatal("key b kept, want it evicted")
+	}
+	if value, err := store.Get(ctx, "a"); err != nil || string(value) != "3" {
+		t.Fatalf("Get(a) = %q, %v, want %q", value, err, "3")
+	}
+}
+
+func TestMemoryStoreExpiresKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(10)
+
+	_ = store.Set(ctx, "pages:1", []byte("1"), time.Millisecond)
+	_ = store.Set(ctx, "pages:2", []byte("2"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "pages:1"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get(pages:1) error = %v, want %v", err, ErrMiss)
+	}
+	if exists, _ := store.Exists(ctx, "pages:1"); exists {
+		t.Fatal("expired key exists")
+	}
+
+	keys, err := store.Keys(ctx, "pages:")
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if !slices.Equal(keys, []string{"pages:2"}) {
+		t.Fatalf("Keys(pages:) = %v, want [pages:2]", keys)
+	}
+}
+
+func TestMemoryStoreLock(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(10)
+
+	if locked, _ := store.Lock(ctx, "locks:a", "first", time.Minute); !locked {
+		t.Fatal("first lock not acquired")
+	}
+	if locked, _ := store.Lock(ctx, "locks:a", "second", time.Minute); locked {
+		t.Fatal("held lock acquired again")
+	}
+
+	// Only the holder of the token releases the lock.
+	_ = store.Unlock(ctx, "locks:a", "second")
+	if locked, _ := store.Lock(ctx, "locks:a", "second", time.Minute); locked {
+		t.Fatal("lock released by another token")
+	}
+
+	_ = store.Unlock(ctx, "locks:a", "first")
+	if locked, _ := store.Lock(ctx, "locks:a", "second", time.Minute); !locked {
+		t.Fatal("lock not acquired after release")
+	}
+}
+
+func TestMemoryStoreLockExpires(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(10)
+
+	_, _ = store.Lock(ctx, "locks:a", "first", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if locked, _ := store.Lock(ctx, "locks:a", "second", time.Minute); !locked {
+		t.Fatal("expired lock not acquired")
+	}
+}
+
+func TestMemoryStoreMembers(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(10)
+
+	_ = store.AddMembers(ctx, "chains:en", 0, "pages:1:en", "pages:1:en,nl")
+	_ = store.AddMembers(ctx, "chains:en", 0, "pages:1:en", "pages:2:en")
+
+	members, err := store.Members(ctx, "chains:en")
+	if err != nil {
+		t.Fatalf("Members: %v", err)
+	}
+	slices.Sort(members)
+	if want := []string{"pages:1:en", "pages:1:en,nl", "pages:2:en"}; !slices.Equal(members, want) {
+		t.Fatalf("Members(chains:en) = %v, want %v", members, want)
+	}
+
+	_ = store.AddMembers(ctx, "chains:nl", time.Millisecond, "pages:1:nl")
+	time.Sleep(5 * time.Millisecond)
+
+	if members, _ := store.Members(ctx, "chains:nl"); len(members) != 0 {
+		t.Fatalf("Members(chains:nl) = %v, want none after expiry", members)
+	}
+}
+
+func TestMemoryStorePublishSubscribe(t *testing.T) {
+	store := NewMemoryStore(10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	received := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- store.Subscribe(ctx, "content", func(message []byte) {
+			received <- string(message)
+		})
+	}()
+
+	// Wait for the subscription, as a message without subscribers is dropped.
+	for {
+		store.mu.Lock()
+		subscribed := len(store.subscribers["content"]) == 1
+		store.mu.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_ = store.Publish(context.Background(), "other", []byte("ignored"))
+	_ = store.Publish(context.Background(), "content", []byte("page.updated"))
+
+	select {
+	case message := <-received:
+		if message != "page.updated" {
+			t.Fatalf("message = %q, want %q", message, "page.updated")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Subscribe error = %v, want %v", err, context.Canceled)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.subscribers["content"]; ok {
+		t.Fatal("subscriber kept after the context is done")
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/cache"
+)
+
+// defaultMemorySize is the number of keys kept by the in-memory store when CACHE_MEMORY_SIZE is not set.
+const defaultMemorySize = 10000
+
+// Default is the store used by the services to cache content.
+var Default Store
+
+// OpenCacheConnection Start a new cache store based on CACHE_STORE.
+func OpenCacheConnection() error {
+	switch os.Getenv("CACHE_STORE") {
+	case "", "valkey":
+		// Open connection to valkey.
+		client, err := cache.ValkeyConnection()
+		if err != nil {
+			return err
+		}
+
+		Default = NewValkeyStore(client)
+	case "memory":
+		size := defaultMemorySize
+		if value := os.Getenv("CACHE_MEMORY_SIZE"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid CACHE_MEMORY_SIZE: %s", value)
+			}
+			size = parsed
+		}
+
+		Default = NewMemoryStore(size)
+	default:
+		return fmt.Errorf("invalid CACHE_STORE: %s", os.Getenv("CACHE_STORE"))
+	}
+
+	return nil
+}
+
+// ReadinessCheck verifies that the cache store is initialized and reachable.
+func ReadinessCheck() error {
+	if Default == nil {
+		return errors.New("cache connection is not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return Default.Ping(ctx)
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned when a key does not exist in the store.
+var ErrMiss = errors.New("cache: key not found")
+
+// Store is the storage behind the cache. Values are stored as raw bytes and expire after their TTL,
+// a TTL of zero or less keeps the value until it is deleted or evicted.
+type Store interface {
+	// Get returns the value of the key or ErrMiss when it does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores the value of the key with the given TTL.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Exists reports whether the key exists.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes the keys, missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+	// Keys returns all keys that start with the prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// AddMembers adds the members to the set of the key and sets its TTL.
+	AddMembers(ctx context.Context, key string, ttl time.Duration, members ...string) error
+	// Members returns the members of the set of the key, a missing key has no members.
+	Members(ctx context.Context, key string) ([]string, error)
+	// Lock sets the key to the token with the given TTL only when the key does not exist yet,
+	// it reports whether the lock was acquired.
+	Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	// Unlock deletes the key only when it still holds the token.
+	Unlock(ctx context.Context, key, token string) error
+	// Publish sends the message to all subscribers of the channel.
+	Publish(ctx context.Context, channel string, message []byte) error
+	// Subscribe calls fn for every message on the channel until ctx is done or the subscription fails.
+	Subscribe(ctx context.Context, channel string, fn func(message []byte)) error
+	// Ping verifies that the store is reachable.
+	Ping(ctx context.Context) error
+	// Close releases the resources of the store.
+	Close()
+}
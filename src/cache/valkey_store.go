@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// valkeyScanCount is the number of keys requested per SCAN iteration.
+const valkeyScanCount = 1000
+
+// valkeyUnlockScript deletes a lock only when it is still held by the token, so an expired lock
+// that is acquired by another worker is never released by the previous holder.
+var valkeyUnlockScript = valkey.NewLuaScript(`if server.call("GET", KEYS[1]) == ARGV[1] then return server.call("DEL", KEYS[1]) end return 0`)
+
+// ValkeyStore is a Store backed by a Valkey server.
+type ValkeyStore struct {
+	client valkey.Client
+}
+
+// NewValkeyStore func to create a store on top of the Valkey client.
+func NewValkeyStore(client valkey.Client) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+// Get method to get the value of the key.
+func (s *ValkeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Do(ctx, s.client.B().Get().Key(key).Build()).AsBytes()
+	if valkey.IsValkeyNil(err) {
+		return nil, ErrMiss
+	}
+
+	return value, err
+}
+
+// Set method to set the value of the key.
+func (s *ValkeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	cmd := s.client.B().Set().Key(key).Value(valkey.BinaryString(value))
+	if ttl > 0 {
+		return s.client.Do(ctx, cmd.Ex(ttl).Build()).Error()
+	}
+
+	return s.client.Do(ctx, cmd.Build()).Error()
+}
+
+// Exists method to check if the key exists.
+func (s *ValkeyStore) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := s.client.Do(ctx, s.client.B().Exists().Key(key).Build()).AsInt64()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// Delete method to delete the keys.
+func (s *ValkeyStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return s.client.Do(ctx, s.client.B().Del().Key(keys...).Build()).Error()
+}
+
+// Keys method to get all keys that start with the prefix.
+func (s *ValkeyStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	pattern := escapeGlob(prefix) + "*"
+	keys := make([]string, 0)
+	cursor := uint64(0)
+
+	for {
+		entry, err := s.client.Do(ctx, s.client.B().Scan().Cursor(cursor).Match(pattern).Count(valkeyScanCount).Build()).AsScanEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, entry.Elements...)
+
+		cursor = entry.Cursor
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// AddMembers method to add the members to the set of the key.
+func (s *ValkeyStore) AddMembers(ctx context.Context, key string, ttl time.Duration, members ...string) error {
+	cmds := valkey.Commands{s.client.B().Sadd().Key(key).Member(members...).Build()}
+	if ttl > 0 {
+		cmds = append(cmds, s.client.B().Pexpire().Key(key).Milliseconds(ttl.Milliseconds()).Build())
+	}
+
+	for _, result := range s.client.DoMulti(ctx, cmds...) {
+		if err := result.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Members method to get the members of the set of the key.
+func (s *ValkeyStore) Members(ctx context.Context, key string) ([]string, error) {
+	return s.client.Do(ctx, s.client.B().Smembers().Key(key).Build()).AsStrSlice()
+}
+
+// Lock method to acquire the lock of the key.
+func (s *ValkeyStore) Lock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	err := s.client.Do(ctx, s.client.B().Set().Key(key).Value(token).Nx().Px(ttl).Build()).Error()
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unlock method to release the lock of the key.
+func (s *ValkeyStore) Unlock(ctx context.Context, key, token string) error {
+	return valkeyUnlockScript.Exec(ctx, s.client, []string{key}, []string{token}).Error()
+}
+
+// Publish method to publish the message on the channel.
+func (s *ValkeyStore) Publish(ctx context.Context, channel string, message []byte) error {
+	return s.client.Do(ctx, s.client.B().Publish().Channel(channel).Message(valkey.BinaryString(message)).Build()).Error()
+}
+
+// Subscribe method to receive the messages of the channel.
+func (s *ValkeyStore) Subscribe(ctx context.Context, channel string, fn func(message []byte)) error {
+	return s.client.Receive(ctx, s.client.B().Subscribe().Channel(channel).Build(), func(msg valkey.PubSubMessage) {
+		fn([]byte(msg.Message))
+	})
+}
+
+// Ping method to verify that the Valkey server is reachable.
+func (s *ValkeyStore) Ping(ctx context.Context) error {
+	result := s.client.Do(ctx, s.client.B().Arbitrary("PING").Build())
+	if result.Error() != nil {
+		return fmt.Errorf("cache ping failed: %w", result.Error())
+	}
+
+	pong, err := result.ToString()
+	if err != nil {
+		return fmt.Errorf("cache ping response invalid: %w", err)
+	}
+	if !strings.EqualFold(pong, "PONG") {
+		return fmt.Errorf("cache ping response unexpected: %s", pong)
+	}
+
+	return nil
+}
+
+// Close method to close the Valkey client.
+func (s *ValkeyStore) Close() {
+	s.client.Close()
+}
+
+// escapeGlob escapes the glob characters of a SCAN MATCH pattern.
+func escapeGlob(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// actorHeader is the header a trusted caller uses to forward the user it acts on behalf of.
+const actorHeader = "X-Forwarded-User"
+
+// defaultActor is recorded when the caller does not forward a user.
+const defaultActor = "machine"
+
+// getActor gets the actor of a request from the forwarded user header.
+func getActor(c fiber.Ctx) string {
+	if actor := strings.TrimSpace(c.Get(actorHeader)); actor != "" {
+		return actor
+	}
+
+	return defaultActor
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
 	"api-page/main/src/services"
 	"api-page/main/src/validation"
@@ -38,6 +39,8 @@ func CreateApp(c fiber.Ctx) error {
 	response := responses.App{}
 	response.SetApp(app)
 
+	recordAudit(c, enums.AUDIT_APP, app.Name, enums.AUDIT_CREATE, nil, response)
+
 	return c.JSON(response)
 }
 
@@ -84,6 +87,8 @@ func SetAppModuleTypes(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeNotFound, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_APP, request.App, enums.AUDIT_UPDATE, nil, response)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -115,5 +120,79 @@ func SetAppPluginTypes(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_APP, request.App, enums.AUDIT_UPDATE, nil, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetAppLocaleFallbacks returns the locale fallbacks of an app.
+func GetAppLocaleFallbacks(c fiber.Ctx) error {
+	appName := strings.TrimSpace(c.Query("app"))
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	appAvailable, err := services.IsAppAvailable(appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+	if !appAvailable {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.AppNotFound, "App not found.")
+	}
+
+	fallbacks, err := services.GetAppLocaleFallbacks(appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.AppLocaleFallbacks{}
+	response.SetAppLocaleFallbacks(appName, fallbacks)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetAppLocaleFallbacks parses and validates the request, checks the app exists,
+// then replaces the locale fallbacks of the app.
+func SetAppLocaleFallbacks(c fiber.Ctx) error {
+	request := &requests.SetAppLocaleFallbacks{}
+	if err := c.Bind().Body(request); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	if err := validation.Validate.Struct(request); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	request.App = strings.TrimSpace(request.App)
+	for locale, fallbacks := range request.Fallbacks {
+		request.Fallbacks[locale] = normalizeNames(fallbacks)
+	}
+
+	appAvailable, err := services.IsAppAvailable(request.App)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+	if !appAvailable {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.AppNotFound, "App not found.")
+	}
+
+	oldFallbacks, err := services.GetAppLocaleFallbacks(request.App)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	fallbacks, err := services.SetAppLocaleFallbacks(request.App, request.Fallbacks)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	before := responses.AppLocaleFallbacks{}
+	before.SetAppLocaleFallbacks(request.App, oldFallbacks)
+
+	response := responses.AppLocaleFallbacks{}
+	response.SetAppLocaleFallbacks(request.App, fallbacks)
+
+	recordAudit(c, enums.AUDIT_APP, request.App, enums.AUDIT_UPDATE, before, response)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"api-page/main/src/enums"
+	"api-page/main/src/services"
+	"fmt"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// GetAudits func for getting all audits paginated.
+func GetAudits(c fiber.Ctx) error {
+	paginationModel, err := services.GetAudits(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// localizedAuditID gets the entity ID of an entity that exists once per locale.
+func localizedAuditID(id uint, locale string) string {
+	return fmt.Sprintf("%d/%s", id, locale)
+}
+
+// recordAudit records an action of the actor of the request on an entity.
+// The action already succeeded, so a failing record is logged instead of failing the request.
+func recordAudit(c fiber.Ctx, entity enums.AuditEntity, entityID any, action enums.AuditAction, before, after any) {
+	if err := services.CreateAudit(getActor(c), entity, fmt.Sprint(entityID), action, before, after); err != nil {
+		log.Error("Audit record failed: ", err)
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetVersionCacheWarmUp func for getting the progress and errors of the last cache warm-up of a version.
+func GetVersionCacheWarmUp(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get warm-up.
+	warmUp, err := services.GetVersionCacheWarmUp(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if warmUp.VersionID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.CacheWarmUpExists, "Cache warm-up does not exist.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(warmUp)
+}
+
+// WarmVersionCache func for starting the cache warm-up of a published version.
+func WarmVersionCache(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get version.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	// Only the published version is served from the cache.
+	if !version.PublishedAt.Valid {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionNotPublished, "Version is not published.")
+	}
+
+	// Start warm-up.
+	warmUp, err := services.StartVersionCacheWarmUp(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(warmUp)
+}
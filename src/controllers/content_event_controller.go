@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"api-page/main/src/services"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// contentEventHeartbeat is the interval of the comments that keep an idle event stream open.
+const contentEventHeartbeat = 15 * time.Second
+
+// GetContentEvents func for streaming the content changes of an app as Server-Sent Events.
+func GetContentEvents(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	var versionID *uint
+	if versionIDParam := c.Query("versionId"); versionIDParam != "" {
+		id, err := util.StringToUint(versionIDParam)
+		if err != nil {
+			return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+		}
+		versionID = &id
+	}
+
+	events, unsubscribe := services.SubscribeContentEvents(appName, versionID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(contentEventHeartbeat)
+		defer heartbeat.Stop()
+
+		// Open the stream right away, so the client knows it is subscribed.
+		_, _ = w.WriteString(": connected\n\n")
+
+		for {
+			if err := w.Flush(); err != nil {
+				// The client disconnected.
+				return
+			}
+
+			select {
+			case event := <-events:
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			case <-heartbeat.C:
+				_, _ = w.WriteString(": heartbeat\n\n")
+			}
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"api-page/main/src/services"
+	stderrors "errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// getETag gets the entity tag of a revision of a resource.
+func getETag(revision uint64) string {
+	return `"` + strconv.FormatUint(revision, 10) + `"`
+}
+
+// setETag sets the entity tag of a revision of a resource on the response.
+func setETag(c fiber.Ctx, revision uint64) {
+	c.Set(fiber.HeaderETag, getETag(revision))
+}
+
+// getIfMatch checks the If-Match header of a request against the revision of a resource.
+// It reports whether the header is present and whether one of its entity tags matches the revision.
+// Weak entity tags never match, because If-Match uses the strong comparison.
+func getIfMatch(c fiber.Ctx, revision uint64) (present, matched bool) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return false, false
+	}
+
+	etag := getETag(revision)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == etag {
+			return true, true
+		}
+	}
+
+	return true, false
+}
+
+// isRevisionConflict checks if an update failed because the resource moved on from the revision in the If-Match header.
+func isRevisionConflict(err error) bool {
+	return stderrors.Is(err, services.ErrRevisionConflict)
+}
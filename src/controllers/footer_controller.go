@@ -3,6 +3,7 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
 	"api-page/main/src/models"
 	"api-page/main/src/services"
@@ -24,15 +25,39 @@ func GetPublishedFooterByVersionID(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
 	}
 
-	rows, err := services.GetFooterByVersionID(versionID, locale)
+	// Get preview token.
+	previewToken, err := getPreviewToken(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusUnauthorized, errorutil.Unauthorized, err.Error())
+	} else if previewToken != nil && (previewToken.VersionID != versionID || !previewToken.AllowsLocale(locale)) {
+		return errorutil.Response(c, fiber.StatusForbidden, errorutil.Forbidden, "Preview token does not grant access to this version and locale.")
+	}
+
+	chain, err := services.GetLocaleChainByVersionID(versionID, locale)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	var rows *[]models.FooterRow
+	if previewToken != nil {
+		rows, err = services.GetPreviewFooterByVersionIDAndLocales(versionID, chain)
+	} else {
+		rows, err = services.GetFooterByVersionIDAndLocales(versionID, chain)
+	}
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(*rows) > 0 {
+		c.Set(fiber.HeaderContentLanguage, (*rows)[0].Locale)
 	}
 
 	response := responses.PublishedFooter{}
 	response.SetFooter(rows)
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	if previewToken != nil {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	return sendPublishedJSON(c, response, services.GetFooterLastModified(rows), versionSurrogateKey(versionID))
 }
 
 // GetFooterByVersionID retrieves the Footer for a given version ID and locale, and returns it as a JSON response.
@@ -52,9 +77,16 @@ func GetFooterByVersionID(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	revision, err := services.GetFooterRevision(versionID, locale)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
 	response := responses.Footer{}
 	response.SetFooter(rows)
 
+	setETag(c, revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -84,6 +116,13 @@ func UpdateFooter(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
 	}
 
+	// Check if the module types of the columns are allowed in a footer.
+	if notAllowed, err := services.GetModuleTypesNotAllowedByModuleIDs(footerRequest.GetModuleIDs(), true); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(notAllowed) > 0 {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, map[string]string{"rows": "Module type " + notAllowed[0] + " is not allowed in a footer."})
+	}
+
 	// Get version to check if it exists.
 	version, err := services.GetVersionByID(versionID)
 	if err != nil {
@@ -98,13 +137,30 @@ func UpdateFooter(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	revision, err := services.GetFooterRevision(version.ID, locale)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
 	// Check if the footer has been modified since it was last fetched.
-	if isFooterRowsOutOfSync(oldRows, &footerRequest.Rows) {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
+	before := responses.Footer{}
+	before.SetFooter(oldRows)
+
 	// Update footer.
-	updatedFooter, err := services.UpdateFooter(version.ID, locale, oldRows, footerRequest)
+	updatedFooter, err := services.UpdateFooter(version.ID, locale, oldRows, footerRequest, revision)
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	revision, err = services.GetFooterRevision(version.ID, locale)
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
@@ -113,68 +169,9 @@ func UpdateFooter(c fiber.Ctx) error {
 	response := responses.Footer{}
 	response.SetFooter(updatedFooter)
 
-	return c.Status(fiber.StatusOK).JSON(response)
-}
-
-// collectFooterRowAndColumnVersions func for collecting the versions of rows and columns in a footer rows tree.
-func collectFooterRowAndColumnVersions(rows []models.FooterRow, rowVersions, columnVersions map[uint]int64, depth int) {
-	if depth > services.MaxRowTreeDepth {
-		return
-	}
+	recordAudit(c, enums.AUDIT_FOOTER, localizedAuditID(version.ID, locale), enums.AUDIT_UPDATE, before, response)
 
-	for i := range rows {
-		rowVersions[rows[i].ID] = rows[i].UpdatedAt.Unix()
-		for j := range rows[i].Columns {
-			columnVersions[rows[i].Columns[j].ID] = rows[i].Columns[j].UpdatedAt.Unix()
-			collectFooterRowAndColumnVersions(rows[i].Columns[j].FooterRows, rowVersions, columnVersions, depth+1)
-		}
-	}
-}
+	setETag(c, revision)
 
-// areRequestedFooterRowsOutOfSync func for checking if any of the requested rows or their columns have been modified since they were last fetched.
-func areRequestedFooterRowsOutOfSync(rows []requests.UpdateFooterRow, rowVersions, columnVersions map[uint]int64, depth int) bool {
-	if depth > services.MaxRowTreeDepth {
-		return true
-	}
-
-	for i := range rows {
-		row := rows[i]
-		if row.ID != nil && *row.ID != 0 {
-			if row.UpdatedAt == nil {
-				return true
-			}
-
-			if oldVersion, exists := rowVersions[*row.ID]; exists && row.UpdatedAt.Unix() < oldVersion {
-				return true
-			}
-		}
-
-		for j := range row.Columns {
-			col := row.Columns[j]
-			if col.ID != nil && *col.ID != 0 {
-				if col.UpdatedAt == nil {
-					return true
-				}
-
-				if oldVersion, exists := columnVersions[*col.ID]; exists && col.UpdatedAt.Unix() < oldVersion {
-					return true
-				}
-			}
-
-			if areRequestedFooterRowsOutOfSync(col.Rows, rowVersions, columnVersions, depth+1) {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
-// isFooterRowsOutOfSync checks if any of the footer rows or their columns have been modified since they were last fetched.
-func isFooterRowsOutOfSync(oldRows *[]models.FooterRow, newRows *[]requests.UpdateFooterRow) bool {
-	rowVersions := map[uint]int64{}
-	columnVersions := map[uint]int64{}
-	collectFooterRowAndColumnVersions(*oldRows, rowVersions, columnVersions, 1)
-
-	return areRequestedFooterRowsOutOfSync(*newRows, rowVersions, columnVersions, 1)
+	return c.Status(fiber.StatusOK).JSON(response)
 }
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"api-page/main/src/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// surrogateKeyHeader is the header a CDN uses to purge cached responses by key.
+const surrogateKeyHeader = "Surrogate-Key"
+
+// appSurrogateKey gets the surrogate key of the published content of an app.
+func appSurrogateKey(appName string) string {
+	return "app-" + appName
+}
+
+// versionSurrogateKey gets the surrogate key of the content of a version.
+func versionSurrogateKey(versionID uint) string {
+	return "version-" + strconv.FormatUint(uint64(versionID), 10)
+}
+
+// pageSurrogateKey gets the surrogate key of the pages of a menu item.
+func pageSurrogateKey(menuItemID uint) string {
+	return "page-" + strconv.FormatUint(uint64(menuItemID), 10)
+}
+
+// sendPublishedJSON sends published content with the headers a browser or CDN needs to cache it.
+// The strong entity tag is a hash of the body, so it only changes when the content does.
+// A request whose If-None-Match or If-Modified-Since still matches gets a 304 without body.
+func sendPublishedJSON(c fiber.Ctx, response any, lastModified time.Time, surrogateKeys ...string) error {
+	body, err := c.App().Config().JSONEncoder(response)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+	c.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	c.Set(fiber.HeaderCacheControl, services.GetHTTPCacheControl())
+	c.Set(fiber.HeaderVary, previewHeader)
+	if len(surrogateKeys) > 0 {
+		c.Set(surrogateKeyHeader, strings.Join(surrogateKeys, " "))
+	}
+
+	if isNotModified(c, etag, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// isNotModified checks the conditional headers of a request against the entity tag and last modification.
+// If-None-Match takes precedence over If-Modified-Since and uses the weak comparison.
+func isNotModified(c fiber.Ctx, etag string, lastModified time.Time) bool {
+	if header := strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch)); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			if tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/"); tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if header := c.Get(fiber.HeaderIfModifiedSince); header != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(header); err == nil {
+			return !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+
+	return false
+}
@@ -3,10 +3,10 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
-	"api-page/main/src/models"
 	"api-page/main/src/services"
-	"time"
+	"api-page/main/src/validation"
 
 	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
 	util "github.com/ArnoldPMolenaar/api-utils/utils"
@@ -69,6 +69,8 @@ func GetMenuByID(c fiber.Ctx) error {
 	response := responses.Menu{}
 	response.SetMenu(menu)
 
+	setETag(c, menu.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -134,8 +136,7 @@ func CreateMenu(c fiber.Ctx) error {
 	}
 
 	// Validate menu fields.
-	validate := util.NewValidator()
-	if err := validate.Struct(menuRequest); err != nil {
+	if err := validation.Validate.Struct(menuRequest); err != nil {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
 	}
 
@@ -171,6 +172,10 @@ func CreateMenu(c fiber.Ctx) error {
 	response := responses.Menu{}
 	response.SetMenu(menu)
 
+	recordAudit(c, enums.AUDIT_MENU, menu.ID, enums.AUDIT_CREATE, nil, response)
+
+	setETag(c, menu.Revision)
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -192,8 +197,7 @@ func UpdateMenu(c fiber.Ctx) error {
 	}
 
 	// Validate menu fields.
-	validate := util.NewValidator()
-	if err := validate.Struct(menuRequest); err != nil {
+	if err := validation.Validate.Struct(menuRequest); err != nil {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
 	}
 
@@ -206,8 +210,10 @@ func UpdateMenu(c fiber.Ctx) error {
 	}
 
 	// Check if the menu has been modified since it was last fetched.
-	if menuRequest.UpdatedAt.Unix() < oldMenu.UpdatedAt.Unix() || isMenuItemsOutOfSync(&oldMenu.MenuItemRelations, &menuRequest.Items) {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, oldMenu.Revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
 	// Check if menu name exists.
@@ -226,9 +232,14 @@ func UpdateMenu(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.MenuDepthInvalid, "Menu depth does not match the depth of the menu items.")
 	}
 
+	before := responses.Menu{}
+	before.SetMenu(oldMenu)
+
 	// Update menu.
-	updatedMenu, err := services.UpdateMenu(oldMenu, menuRequest)
-	if err != nil {
+	updatedMenu, err := services.UpdateMenu(oldMenu, menuRequest, oldMenu.Revision)
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
@@ -236,6 +247,10 @@ func UpdateMenu(c fiber.Ctx) error {
 	response := responses.Menu{}
 	response.SetMenu(updatedMenu)
 
+	recordAudit(c, enums.AUDIT_MENU, updatedMenu.ID, enums.AUDIT_UPDATE, before, response)
+
+	setETag(c, updatedMenu.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -255,11 +270,16 @@ func DeleteMenu(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusNotFound, errors.MenuExists, "Menu does not exist.")
 	}
 
+	before := responses.Menu{}
+	before.SetMenu(menu)
+
 	// Delete the Menu.
 	if err := services.DeleteMenu(menu.VersionID, menu.ID); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_MENU, menu.ID, enums.AUDIT_DELETE, before, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -283,58 +303,9 @@ func RestoreMenu(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
-}
-
-// flattenUpdateMenuItems flattens a nested slice of UpdateMenuItem into a single-level slice.
-func flattenUpdateMenuItems(items *[]requests.UpdateMenuItem) []requests.UpdateMenuItem {
-	if items == nil {
-		return nil
-	}
-	var out []requests.UpdateMenuItem
-	var stack []requests.UpdateMenuItem
-
-	// seed stack
-	stack = append(stack, (*items)...)
+	recordAudit(c, enums.AUDIT_MENU, id, enums.AUDIT_RESTORE, nil, nil)
 
-	// iterative DFS to avoid deep recursion
-	for len(stack) > 0 {
-		// pop
-		n := len(stack) - 1
-		cur := stack[n]
-		stack = stack[:n]
-
-		out = append(out, cur)
-
-		// push children if present
-		if cur.Items != nil {
-			stack = append(stack, cur.Items...)
-		}
-	}
-
-	return out
-}
-
-// isMenuItemsOutOfSync checks if any of the menu items are out of sync based on their UpdatedAt timestamps.
-func isMenuItemsOutOfSync(menuItems *[]models.MenuItemRelation, requestItems *[]requests.UpdateMenuItem) bool {
-	relations := make(map[uint]time.Time)
-	for i := range *menuItems {
-		relations[(*menuItems)[i].MenuItemChildID] = (*menuItems)[i].MenuItemChild.UpdatedAt
-	}
-
-	flattenItems := flattenUpdateMenuItems(requestItems)
-
-	for i := range flattenItems {
-		if flattenItems[i].ID != nil {
-			if updatedAt, exists := relations[*flattenItems[i].ID]; exists {
-				if flattenItems[i].UpdatedAt.Unix() < updatedAt.Unix() {
-					return true
-				}
-			}
-		}
-	}
-
-	return false
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 // isMenuDepthValid checks if the depth is not exceeded e.g. configured in the menu.
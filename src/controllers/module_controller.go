@@ -3,6 +3,7 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
 	"api-page/main/src/services"
 	"api-page/main/src/validation"
@@ -88,9 +89,36 @@ func GetModuleByID(c fiber.Ctx) error {
 	response := responses.Module{}
 	response.SetModule(module)
 
+	setETag(c, module.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// GetModuleUsages func for getting every version, page, locale, partial and footer column that uses a module.
+func GetModuleUsages(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Find the Module.
+	module, err := services.GetModuleByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if module.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleExists, "Module does not exist.")
+	}
+
+	// Get the usages.
+	usages, err := services.GetModuleUsages(module.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(usages)
+}
+
 // IsModuleNameAvailable method to check if module is available.
 func IsModuleNameAvailable(c fiber.Ctx) error {
 	app := c.Query("app")
@@ -146,6 +174,13 @@ func CreateModule(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeNotFound, "Module type not found.")
 	}
 
+	// Validate the settings against the schema of the module type.
+	if fields, err := services.ValidateModuleSettings(moduleRequest.Type, moduleRequest.Settings); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(fields) > 0 {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, fields)
+	}
+
 	// Check if module exists.
 	if available, err := services.IsModuleNameAvailable(moduleRequest.AppName, moduleRequest.Name, nil); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
@@ -163,6 +198,10 @@ func CreateModule(c fiber.Ctx) error {
 	response := responses.Module{}
 	response.SetModule(module)
 
+	recordAudit(c, enums.AUDIT_MODULE, module.ID, enums.AUDIT_CREATE, nil, response)
+
+	setETag(c, module.Revision)
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -195,6 +234,13 @@ func UpdateModule(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeNotFound, "Module type not found.")
 	}
 
+	// Validate the settings against the schema of the module type.
+	if fields, err := services.ValidateModuleSettings(moduleRequest.Type, moduleRequest.Settings); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(fields) > 0 {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, fields)
+	}
+
 	// Get old module.
 	oldModule, err := services.GetModuleByID(moduleID)
 	if err != nil {
@@ -204,8 +250,10 @@ func UpdateModule(c fiber.Ctx) error {
 	}
 
 	// Check if the module has been modified since it was last fetched.
-	if moduleRequest.UpdatedAt.Unix() < oldModule.UpdatedAt.Unix() {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, oldModule.Revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
 	// Check if module name exists.
@@ -217,9 +265,14 @@ func UpdateModule(c fiber.Ctx) error {
 		}
 	}
 
+	before := responses.Module{}
+	before.SetModule(oldModule)
+
 	// Update module.
-	updatedModule, err := services.UpdateModule(oldModule, moduleRequest)
-	if err != nil {
+	updatedModule, err := services.UpdateModule(oldModule, moduleRequest, oldModule.Revision)
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
@@ -227,10 +280,15 @@ func UpdateModule(c fiber.Ctx) error {
 	response := responses.Module{}
 	response.SetModule(updatedModule)
 
+	recordAudit(c, enums.AUDIT_MODULE, updatedModule.ID, enums.AUDIT_UPDATE, before, response)
+
+	setETag(c, updatedModule.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
-// DeleteModule func for deleting a module.
+// DeleteModule func for deleting a module. A module that is still used by page partial or footer columns
+// is only deleted with ?force=true, and then the broken usages are returned.
 func DeleteModule(c fiber.Ctx) error {
 	// Get the ID from the URL.
 	id, err := util.StringToUint(c.Params("id"))
@@ -246,11 +304,28 @@ func DeleteModule(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleExists, "Module does not exist.")
 	}
 
+	// Check if the Module is in use.
+	usages, err := services.GetModuleUsages(module.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if usages.Total > 0 && !fiber.Query[bool](c, "force") {
+		return errorutil.Response(c, fiber.StatusConflict, errors.ModuleInUse, usages)
+	}
+
+	before := responses.Module{}
+	before.SetModule(module)
+
 	// Delete the Module.
-	if err := services.DeleteModule(module.ID, module.AppName); err != nil {
+	if err := services.DeleteModule(module.ID, module.AppName, usages); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_MODULE, module.ID, enums.AUDIT_DELETE, before, nil)
+
+	if usages.Total > 0 {
+		return c.Status(fiber.StatusOK).JSON(usages)
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -274,5 +349,7 @@ func RestoreModule(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_MODULE, id, enums.AUDIT_RESTORE, nil, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
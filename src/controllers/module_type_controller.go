@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"api-page/main/src/validation"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetModuleTypes func for getting all module types paginated.
+func GetModuleTypes(c fiber.Ctx) error {
+	paginationModel, err := services.GetModuleTypes(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// GetModuleType func for getting a module type by name.
+func GetModuleType(c fiber.Ctx) error {
+	moduleType, err := services.GetModuleType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if moduleType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	response := responses.ModuleType{}
+	response.SetModuleType(moduleType)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// CreateModuleType func for creating a new module type.
+func CreateModuleType(c fiber.Ctx) error {
+	// Create a new module type struct for the request.
+	moduleTypeRequest := &requests.CreateModuleType{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(moduleTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate module type fields.
+	if err := validation.Validate.Struct(moduleTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if module type name exists.
+	if available, err := services.IsModuleTypeNameAvailable(moduleTypeRequest.Name, nil); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !available {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeAvailable, "Module type name already exist.")
+	}
+
+	// Create module type.
+	moduleType, err := services.CreateModuleType(moduleTypeRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the module type.
+	response := responses.ModuleType{}
+	response.SetModuleType(moduleType)
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, moduleType.Name, enums.AUDIT_CREATE, nil, response)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// UpdateModuleType func for updating a module type. A changed name renames the module type everywhere it is used.
+func UpdateModuleType(c fiber.Ctx) error {
+	// Create a new module type struct for the request.
+	moduleTypeRequest := &requests.UpdateModuleType{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(moduleTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate module type fields.
+	if err := validation.Validate.Struct(moduleTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get old module type.
+	oldModuleType, err := services.GetModuleType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if oldModuleType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	// Check if module type name exists.
+	if moduleTypeRequest.Name != oldModuleType.Name {
+		if available, err := services.IsModuleTypeNameAvailable(moduleTypeRequest.Name, &oldModuleType.Name); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if !available {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeAvailable, "Module type name already exist.")
+		}
+	}
+
+	before := responses.ModuleType{}
+	before.SetModuleType(oldModuleType)
+
+	// Update module type.
+	moduleType, err := services.UpdateModuleType(oldModuleType, moduleTypeRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the module type.
+	response := responses.ModuleType{}
+	response.SetModuleType(moduleType)
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, before.Name, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteModuleType func for deleting a module type.
+func DeleteModuleType(c fiber.Ctx) error {
+	// Find the module type.
+	moduleType, err := services.GetModuleType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if moduleType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	before := responses.ModuleType{}
+	before.SetModuleType(moduleType)
+
+	// Delete the module type.
+	if err := services.DeleteModuleType(moduleType.Name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, moduleType.Name, enums.AUDIT_DELETE, before, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreModuleType func for restoring a deleted module type.
+func RestoreModuleType(c fiber.Ctx) error {
+	name := c.Params("name")
+
+	// Check if module type is deleted.
+	if isDeleted, err := services.IsModuleTypeDeleted(name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !isDeleted {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeAvailable, "Module type is not deleted.")
+	}
+
+	// Restore the module type.
+	if err := services.RestoreModuleType(name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, name, enums.AUDIT_RESTORE, nil, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetModuleTypeBehavior func for setting whether published pages render the live settings of the modules
+// of a module type, or the settings that were frozen when the version was published.
+func SetModuleTypeBehavior(c fiber.Ctx) error {
+	// Create a new behavior struct for the request.
+	behaviorRequest := &requests.SetModuleTypeBehavior{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(behaviorRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate behavior fields.
+	if err := validation.Validate.Struct(behaviorRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Find the module type.
+	moduleType, err := services.GetModuleType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if moduleType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	before := responses.ModuleType{}
+	before.SetModuleType(moduleType)
+
+	// Set the behavior.
+	moduleType, err = services.SetModuleTypeBehavior(moduleType, enums.ModuleBehavior(behaviorRequest.Behavior))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.ModuleType{}
+	response.SetModuleType(moduleType)
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, moduleType.Name, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
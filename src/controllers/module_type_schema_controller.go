@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"api-page/main/src/validation"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetModuleTypeSchema func for getting the latest or a specific schema version of a module type.
+func GetModuleTypeSchema(c fiber.Ctx) error {
+	moduleType := c.Params("name")
+
+	var version *uint
+	if versionParam := c.Query("version"); versionParam != "" {
+		versionValue, err := util.StringToUint(versionParam)
+		if err != nil {
+			return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+		}
+		version = &versionValue
+	}
+
+	// Check if module type exists.
+	if notAvailable, err := services.IsModuleTypeNotAvailable(moduleType); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if notAvailable {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	// Get schema.
+	schema, err := services.GetModuleTypeSchema(moduleType, version)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if schema.Version == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeSchemaExists, "Module type schema does not exist.")
+	}
+
+	response := responses.ModuleTypeSchema{}
+	response.SetModuleTypeSchema(schema)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetModuleTypeSchema func for adding a new schema version to a module type, with the migration of the settings
+// of the previous version. New and updated modules of the type are validated against the new version.
+func SetModuleTypeSchema(c fiber.Ctx) error {
+	moduleType := c.Params("name")
+
+	// Create a new schema struct for the request.
+	schemaRequest := &requests.SetModuleTypeSchema{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(schemaRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate schema fields.
+	if err := validation.Validate.Struct(schemaRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if the schema is a valid JSON Schema.
+	if err := services.CompileModuleTypeSchema(schemaRequest.Schema); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, map[string]string{"schema": err.Error()})
+	}
+
+	// Check if every migration step is complete.
+	if fields := services.CheckModuleSettingsMigration(schemaRequest.Migration); len(fields) > 0 {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, fields)
+	}
+
+	// Check if module type exists.
+	if notAvailable, err := services.IsModuleTypeNotAvailable(moduleType); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if notAvailable {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	// Get old schema.
+	oldSchema, err := services.GetModuleTypeSchema(moduleType, nil)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	var before any
+	if oldSchema.Version != 0 {
+		beforeSchema := responses.ModuleTypeSchema{}
+		beforeSchema.SetModuleTypeSchema(oldSchema)
+		before = beforeSchema
+	}
+
+	// Create schema.
+	schema, err := services.CreateModuleTypeSchema(moduleType, schemaRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.ModuleTypeSchema{}
+	response.SetModuleTypeSchema(schema)
+
+	recordAudit(c, enums.AUDIT_MODULE_TYPE, moduleType, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// MigrateModuleSettings func for migrating the settings of the modules of a module type to the latest
+// or a specific schema version. With dryRun the result is returned without saving it.
+func MigrateModuleSettings(c fiber.Ctx) error {
+	moduleType := c.Params("name")
+
+	// Create a new migrate struct for the request.
+	migrateRequest := &requests.MigrateModuleSettings{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(migrateRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate migrate fields.
+	if err := validation.Validate.Struct(migrateRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if module type exists.
+	if notAvailable, err := services.IsModuleTypeNotAvailable(moduleType); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if notAvailable {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeNotFound, "Module type not found.")
+	}
+
+	// Get the target schema.
+	schema, err := services.GetModuleTypeSchema(moduleType, migrateRequest.ToVersion)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if schema.Version == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.ModuleTypeSchemaExists, "Module type schema does not exist.")
+	}
+
+	// Migrate the module settings.
+	response, err := services.MigrateModuleSettings(moduleType, schema.Version, migrateRequest.DryRun)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	if !migrateRequest.DryRun {
+		recordAudit(c, enums.AUDIT_MODULE_TYPE, moduleType, enums.AUDIT_MIGRATE, nil, response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -3,15 +3,19 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
 	"api-page/main/src/models"
 	"api-page/main/src/services"
+	"api-page/main/src/validation"
+	stderrors "errors"
 
 	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
 	util "github.com/ArnoldPMolenaar/api-utils/utils"
 	"github.com/gofiber/fiber/v3"
 )
 
+// GetPublishedPageByID func for getting a published page by its menu item and locale.
 func GetPublishedPageByID(c fiber.Ctx) error {
 	menuItemID, err := util.StringToUint(c.Params("menuItemId"))
 	if err != nil {
@@ -23,17 +27,131 @@ func GetPublishedPageByID(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
 	}
 
-	page, err := services.GetPublishedPage(menuItemID, locale)
+	// Get preview token.
+	previewToken, err := getPreviewToken(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusUnauthorized, errorutil.Unauthorized, err.Error())
+	}
+
+	var page *models.Page
+	if previewToken != nil {
+		// Check if the menu item belongs to the version of the preview token.
+		if versionID, err := services.GetVersionIDByMenuItemID(menuItemID); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if previewToken.VersionID != versionID || !previewToken.AllowsLocale(locale) {
+			return errorutil.Response(c, fiber.StatusForbidden, errorutil.Forbidden, "Preview token does not grant access to this version and locale.")
+		}
+
+		var chain []string
+		if chain, err = services.GetLocaleChainByMenuItemID(menuItemID, locale); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		}
+
+		page, err = services.GetPreviewPageByLocales(menuItemID, chain)
+	} else {
+		page, err = services.GetPublishedPageWithFallback(menuItemID, locale)
+	}
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	} else if page.MenuItemID == 0 {
 		return errorutil.Response(c, fiber.StatusNotFound, errors.PageExists, "Published page not found for the specified menu item and locale.")
 	}
 
+	robots := services.GetPageRobots(page)
+	c.Set("X-Robots-Tag", robots)
+	c.Set(fiber.HeaderContentLanguage, page.Locale)
+
 	response := responses.PublishedPage{}
 	response.SetPage(page)
+	response.SetRobots(robots)
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	if previewToken != nil {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	versionID, err := services.GetVersionIDByMenuItemID(menuItemID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return sendPublishedJSON(c, response, services.GetPageLastModified(page), versionSurrogateKey(versionID), pageSurrogateKey(menuItemID))
+}
+
+// GetPublishedPageByPath func for getting a published page of an app by its full path.
+func GetPublishedPageByPath(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	locale := c.Query("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	path := c.Query("path")
+	if path == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Path parameter is required.")
+	}
+
+	// Get preview token.
+	previewToken, err := getPreviewToken(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusUnauthorized, errorutil.Unauthorized, err.Error())
+	} else if previewToken != nil && (previewToken.AppName != appName || !previewToken.AllowsLocale(locale)) {
+		return errorutil.Response(c, fiber.StatusForbidden, errorutil.Forbidden, "Preview token does not grant access to this app and locale.")
+	}
+
+	var page *models.Page
+	var redirect *models.Redirect
+	if previewToken != nil {
+		var version *models.Version
+		if version, err = services.GetVersionByID(previewToken.VersionID); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if version.ID == 0 {
+			return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+		}
+
+		page, redirect, err = services.GetPreviewPageByPath(version, locale, path)
+	} else {
+		page, redirect, err = services.GetPublishedPageByPath(appName, locale, path)
+	}
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if redirect != nil {
+		response := responses.PublishedRedirect{}
+		response.SetRedirect(redirect)
+
+		return c.Status(response.StatusCode).JSON(response)
+	} else if page.MenuItemID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageExists, "Published page not found for the specified path and locale.")
+	}
+
+	robots := services.GetPageRobots(page)
+	c.Set("X-Robots-Tag", robots)
+	c.Set(fiber.HeaderContentLanguage, page.Locale)
+
+	response := responses.PublishedPage{}
+	response.SetPage(page)
+	response.SetRobots(robots)
+
+	if previewToken != nil {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	version, err := services.GetPublishedVersionByAppName(appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	lastModified := services.GetPageLastModified(page)
+
+	// The same path resolves to a page of another version once that version is published.
+	if version.PublishedAt.Time.After(lastModified) {
+		lastModified = version.PublishedAt.Time
+	}
+
+	return sendPublishedJSON(c, response, lastModified, appSurrogateKey(appName), versionSurrogateKey(version.ID), pageSurrogateKey(page.MenuItemID))
 }
 
 // GetOrCreatePageByID func for getting or creating a page.
@@ -58,6 +176,8 @@ func GetOrCreatePageByID(c fiber.Ctx) error {
 	response := responses.Page{}
 	response.SetPage(page)
 
+	setETag(c, page.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -96,6 +216,8 @@ func GetPartialByID(c fiber.Ctx) error {
 	response := responses.PagePartial{}
 	response.SetPagePartial(partial)
 
+	setETag(c, partial.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -141,7 +263,7 @@ func CreatePagePartial(c fiber.Ctx) error {
 	}
 
 	// Create partial.
-	partial, err := services.CreatePagePartial(page, partialRequest)
+	partial, err := services.CreatePagePartial(page, partialRequest, getActor(c))
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
@@ -150,6 +272,10 @@ func CreatePagePartial(c fiber.Ctx) error {
 	response := responses.PagePartial{}
 	response.SetPagePartial(partial)
 
+	recordAudit(c, enums.AUDIT_PAGE_PARTIAL, partial.ID, enums.AUDIT_CREATE, nil, response)
+
+	setETag(c, partial.Revision)
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -174,8 +300,7 @@ func UpdatePage(c fiber.Ctx) error {
 	}
 
 	// Validate page fields.
-	validate := util.NewValidator()
-	if err := validate.Struct(pageRequest); err != nil {
+	if err := validation.Validate.Struct(pageRequest); err != nil {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
 	}
 
@@ -188,13 +313,31 @@ func UpdatePage(c fiber.Ctx) error {
 	}
 
 	// Check if the page has been modified since it was last fetched.
-	if pageRequest.UpdatedAt.Unix() < oldPage.UpdatedAt.Unix() {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, oldPage.Revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
+	// Check if the slug is unique within the version and locale.
+	if pageRequest.Slug != nil {
+		if available, err := services.IsPageSlugAvailable(menuItemID, locale, *pageRequest.Slug); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if !available {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+		}
+	}
+
+	before := responses.Page{}
+	before.SetPage(oldPage)
+
 	// Update page.
-	updatedPage, err := services.UpdatePage(oldPage, pageRequest)
-	if err != nil {
+	updatedPage, err := services.UpdatePage(oldPage, pageRequest, oldPage.Revision, getActor(c))
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if isPageSlugNotAvailable(err) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
@@ -202,6 +345,10 @@ func UpdatePage(c fiber.Ctx) error {
 	response := responses.Page{}
 	response.SetPage(updatedPage)
 
+	recordAudit(c, enums.AUDIT_PAGE, localizedAuditID(menuItemID, locale), enums.AUDIT_UPDATE, before, response)
+
+	setETag(c, updatedPage.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -236,6 +383,13 @@ func UpdatePagePartial(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
 	}
 
+	// Check if the module types of the columns are allowed in a page.
+	if notAllowed, err := services.GetModuleTypesNotAllowedByModuleIDs(partialRequest.GetModuleIDs(), false); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(notAllowed) > 0 {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, map[string]string{"rows": "Module type " + notAllowed[0] + " is not allowed in a page."})
+	}
+
 	// Get page to check if it exists.
 	page, err := services.GetPage(menuItemID, locale)
 	if err != nil {
@@ -253,8 +407,10 @@ func UpdatePagePartial(c fiber.Ctx) error {
 	}
 
 	// Check if the partial has been modified since it was last fetched.
-	if partialRequest.UpdatedAt.Unix() < oldPartial.UpdatedAt.Unix() || isPartialRowsOutOfSync(&oldPartial.Rows, &partialRequest.Rows) {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, oldPartial.Revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
 	if partialRequest.Name != oldPartial.Name {
@@ -266,9 +422,14 @@ func UpdatePagePartial(c fiber.Ctx) error {
 		}
 	}
 
+	before := responses.PagePartial{}
+	before.SetPagePartial(oldPartial)
+
 	// Update partial.
-	updatedPartial, err := services.UpdatePagePartial(page.MenuItemID, page.Locale, oldPartial, partialRequest)
-	if err != nil {
+	updatedPartial, err := services.UpdatePagePartial(page.MenuItemID, page.Locale, oldPartial, partialRequest, oldPartial.Revision, getActor(c))
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
@@ -276,6 +437,10 @@ func UpdatePagePartial(c fiber.Ctx) error {
 	response := responses.PagePartial{}
 	response.SetPagePartial(updatedPartial)
 
+	recordAudit(c, enums.AUDIT_PAGE_PARTIAL, updatedPartial.ID, enums.AUDIT_UPDATE, before, response)
+
+	setETag(c, updatedPartial.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -299,11 +464,16 @@ func DeletePage(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusNotFound, errors.PageExists, "Page not found for the specified menu item and locale.")
 	}
 
+	before := responses.Page{}
+	before.SetPage(page)
+
 	// Delete the Page.
-	if err := services.DeletePage(menuItemID, locale); err != nil {
+	if err := services.DeletePage(menuItemID, locale, getActor(c)); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_PAGE, localizedAuditID(menuItemID, locale), enums.AUDIT_DELETE, before, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -347,11 +517,16 @@ func DeletePagePartial(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusNotFound, errors.PagePartialAvailable, "Partial does not exist for the specified ID.")
 	}
 
+	before := responses.PagePartial{}
+	before.SetPagePartial(oldPartial)
+
 	// Delete the Partial.
-	if err := services.DeletePagePartial(page.MenuItemID, page.Locale, partialID); err != nil {
+	if err := services.DeletePagePartial(page.MenuItemID, page.Locale, partialID, getActor(c)); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_PAGE_PARTIAL, partialID, enums.AUDIT_DELETE, before, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -375,10 +550,14 @@ func RestorePage(c fiber.Ctx) error {
 	}
 
 	// Restore the page.
-	if err := services.RestorePage(menuItemID, locale); err != nil {
+	if err := services.RestorePage(menuItemID, locale, getActor(c)); isPageSlugNotAvailable(err) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_PAGE, localizedAuditID(menuItemID, locale), enums.AUDIT_RESTORE, nil, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -415,72 +594,16 @@ func RestorePagePartial(c fiber.Ctx) error {
 	}
 
 	// Restore the partial.
-	if err := services.RestorePagePartial(menuItemID, locale, partialID); err != nil {
+	if err := services.RestorePagePartial(menuItemID, locale, partialID, getActor(c)); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
-}
-
-// collectRowAndColumnVersions func for collecting the versions of rows and columns in a page partial tree.
-func collectRowAndColumnVersions(rows []models.PagePartialRow, rowVersions, columnVersions map[uint]int64, depth int) {
-	if depth > services.MaxPagePartialTreeDepth {
-		return
-	}
-
-	for i := range rows {
-		rowVersions[rows[i].ID] = rows[i].UpdatedAt.Unix()
-		for j := range rows[i].Columns {
-			columnVersions[rows[i].Columns[j].ID] = rows[i].Columns[j].UpdatedAt.Unix()
-			collectRowAndColumnVersions(rows[i].Columns[j].PagePartialRows, rowVersions, columnVersions, depth+1)
-		}
-	}
-}
-
-// areRequestedRowsOutOfSync func for checking if any of the requested rows or their columns have been modified since they were last fetched.
-func areRequestedRowsOutOfSync(rows []requests.UpdatePagePartialRow, rowVersions, columnVersions map[uint]int64, depth int) bool {
-	if depth > services.MaxPagePartialTreeDepth {
-		return true
-	}
-
-	for i := range rows {
-		row := rows[i]
-		if row.ID != nil && *row.ID != 0 {
-			if row.UpdatedAt == nil {
-				return true
-			}
-
-			if oldVersion, exists := rowVersions[*row.ID]; exists && row.UpdatedAt.Unix() < oldVersion {
-				return true
-			}
-		}
-
-		for j := range row.Columns {
-			col := row.Columns[j]
-			if col.ID != nil && *col.ID != 0 {
-				if col.UpdatedAt == nil {
-					return true
-				}
-
-				if oldVersion, exists := columnVersions[*col.ID]; exists && col.UpdatedAt.Unix() < oldVersion {
-					return true
-				}
-			}
-
-			if areRequestedRowsOutOfSync(col.Rows, rowVersions, columnVersions, depth+1) {
-				return true
-			}
-		}
-	}
+	recordAudit(c, enums.AUDIT_PAGE_PARTIAL, partialID, enums.AUDIT_RESTORE, nil, nil)
 
-	return false
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
-// isPartialRowsOutOfSync checks if any of the partial rows or their columns have been modified since they were last fetched.
-func isPartialRowsOutOfSync(oldRows *[]models.PagePartialRow, newRows *[]requests.UpdatePagePartialRow) bool {
-	rowVersions := map[uint]int64{}
-	columnVersions := map[uint]int64{}
-	collectRowAndColumnVersions(*oldRows, rowVersions, columnVersions, 1)
-
-	return areRequestedRowsOutOfSync(*newRows, rowVersions, columnVersions, 1)
+// isPageSlugNotAvailable checks if a page could not be saved because another page of the version and locale took its slug.
+func isPageSlugNotAvailable(err error) bool {
+	return stderrors.Is(err, services.ErrPageSlugNotAvailable)
 }
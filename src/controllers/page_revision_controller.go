@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetPageRevisions func for getting the revisions of a page.
+func GetPageRevisions(c fiber.Ctx) error {
+	menuItemID, err := util.StringToUint(c.Params("menuItemId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	locale := c.Params("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	// Get revisions.
+	revisions, err := services.GetPageRevisions(menuItemID, locale)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.PageRevisionList{}
+	response.SetPageRevisionList(revisions)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetPageRevisionByID func for getting a revision of a page with its content.
+func GetPageRevisionByID(c fiber.Ctx) error {
+	menuItemID, err := util.StringToUint(c.Params("menuItemId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	locale := c.Params("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	revisionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get revision.
+	revision, err := services.GetPageRevisionByID(menuItemID, locale, revisionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if revision.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageRevisionExists, "Page revision does not exist.")
+	}
+
+	page, err := services.GetPageRevisionContent(revision)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, err.Error())
+	}
+
+	response := responses.PageRevision{}
+	response.SetPageRevision(revision, page)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetPageRevisionDiff func for getting the changes between two revisions of a page.
+func GetPageRevisionDiff(c fiber.Ctx) error {
+	menuItemID, err := util.StringToUint(c.Params("menuItemId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	locale := c.Params("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	revisionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	otherRevisionID, err := util.StringToUint(c.Params("otherId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get revisions.
+	revision, err := services.GetPageRevisionByID(menuItemID, locale, revisionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if revision.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageRevisionExists, "Page revision does not exist.")
+	}
+
+	otherRevision, err := services.GetPageRevisionByID(menuItemID, locale, otherRevisionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if otherRevision.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageRevisionExists, "Other page revision does not exist.")
+	}
+
+	// Get changes.
+	changes, err := services.DiffPageRevisions(revision, otherRevision)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, err.Error())
+	}
+
+	response := responses.PageRevisionDiff{}
+	response.SetPageRevisionDiff(revision.ID, otherRevision.ID, changes)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RestorePageRevision func for restoring a revision onto the page.
+func RestorePageRevision(c fiber.Ctx) error {
+	menuItemID, err := util.StringToUint(c.Params("menuItemId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	locale := c.Params("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	revisionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get page.
+	page, err := services.GetPage(menuItemID, locale)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if page.MenuItemID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageExists, "Page not found for the specified menu item and locale.")
+	}
+
+	// Get revision.
+	revision, err := services.GetPageRevisionByID(menuItemID, locale, revisionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if revision.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PageRevisionExists, "Page revision does not exist.")
+	}
+
+	before := responses.Page{}
+	before.SetPage(page)
+
+	// Restore revision.
+	restoredPage, err := services.RestorePageRevision(page, revision, getActor(c))
+	if isPageSlugNotAvailable(err) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+	} else if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.Page{}
+	response.SetPage(restoredPage)
+
+	recordAudit(c, enums.AUDIT_PAGE, localizedAuditID(menuItemID, locale), enums.AUDIT_RESTORE, before, response)
+
+	setETag(c, restoredPage.Revision)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
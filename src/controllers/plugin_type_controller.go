@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"api-page/main/src/validation"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetPluginTypes func for getting all plugin types paginated.
+func GetPluginTypes(c fiber.Ctx) error {
+	paginationModel, err := services.GetPluginTypes(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// GetPluginType func for getting a plugin type by name.
+func GetPluginType(c fiber.Ctx) error {
+	pluginType, err := services.GetPluginType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if pluginType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PluginTypeNotFound, "Plugin type not found.")
+	}
+
+	response := responses.PluginType{}
+	response.SetPluginType(pluginType)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// CreatePluginType func for creating a new plugin type.
+func CreatePluginType(c fiber.Ctx) error {
+	// Create a new plugin type struct for the request.
+	pluginTypeRequest := &requests.CreatePluginType{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(pluginTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate plugin type fields.
+	if err := validation.Validate.Struct(pluginTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if plugin type name exists.
+	if available, err := services.IsPluginTypeNameAvailable(pluginTypeRequest.Name, nil); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !available {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PluginTypeAvailable, "Plugin type name already exist.")
+	}
+
+	// Create plugin type.
+	pluginType, err := services.CreatePluginType(pluginTypeRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the plugin type.
+	response := responses.PluginType{}
+	response.SetPluginType(pluginType)
+
+	recordAudit(c, enums.AUDIT_PLUGIN_TYPE, pluginType.Name, enums.AUDIT_CREATE, nil, response)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// UpdatePluginType func for updating a plugin type. A changed name renames the plugin type everywhere it is used.
+func UpdatePluginType(c fiber.Ctx) error {
+	// Create a new plugin type struct for the request.
+	pluginTypeRequest := &requests.UpdatePluginType{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(pluginTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate plugin type fields.
+	if err := validation.Validate.Struct(pluginTypeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get old plugin type.
+	oldPluginType, err := services.GetPluginType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if oldPluginType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PluginTypeNotFound, "Plugin type not found.")
+	}
+
+	// Check if plugin type name exists.
+	if pluginTypeRequest.Name != oldPluginType.Name {
+		if available, err := services.IsPluginTypeNameAvailable(pluginTypeRequest.Name, &oldPluginType.Name); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if !available {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.PluginTypeAvailable, "Plugin type name already exist.")
+		}
+	}
+
+	before := responses.PluginType{}
+	before.SetPluginType(oldPluginType)
+
+	// Update plugin type.
+	pluginType, err := services.UpdatePluginType(oldPluginType, pluginTypeRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the plugin type.
+	response := responses.PluginType{}
+	response.SetPluginType(pluginType)
+
+	recordAudit(c, enums.AUDIT_PLUGIN_TYPE, before.Name, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeletePluginType func for deleting a plugin type.
+func DeletePluginType(c fiber.Ctx) error {
+	// Find the plugin type.
+	pluginType, err := services.GetPluginType(c.Params("name"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if pluginType.Name == "" {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.PluginTypeNotFound, "Plugin type not found.")
+	}
+
+	before := responses.PluginType{}
+	before.SetPluginType(pluginType)
+
+	// Delete the plugin type.
+	if err := services.DeletePluginType(pluginType.Name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_PLUGIN_TYPE, pluginType.Name, enums.AUDIT_DELETE, before, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestorePluginType func for restoring a deleted plugin type.
+func RestorePluginType(c fiber.Ctx) error {
+	name := c.Params("name")
+
+	// Check if plugin type is deleted.
+	if isDeleted, err := services.IsPluginTypeDeleted(name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !isDeleted {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PluginTypeAvailable, "Plugin type is not deleted.")
+	}
+
+	// Restore the plugin type.
+	if err := services.RestorePluginType(name); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_PLUGIN_TYPE, name, enums.AUDIT_RESTORE, nil, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"api-page/main/src/services"
+	stderrors "errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// previewHeader is the header a caller uses to pass a preview token.
+const previewHeader = "X-Preview-Token"
+
+// previewQuery is the query parameter a caller uses to pass a preview token, e.g. from a preview link.
+const previewQuery = "preview"
+
+// getPreviewToken gets the verified preview token of a request from the preview header or query parameter.
+// It returns nil without an error when the request has no preview token.
+func getPreviewToken(c fiber.Ctx) (*services.PreviewToken, error) {
+	token := strings.TrimSpace(c.Get(previewHeader))
+	if token == "" {
+		token = strings.TrimSpace(c.Query(previewQuery))
+	}
+	if token == "" {
+		return nil, nil
+	}
+
+	previewToken, err := services.ParsePreviewToken(token)
+	if stderrors.Is(err, services.ErrPreviewTokenSecretNotConfigured) {
+		// The configuration is not disclosed to the caller.
+		log.Error("Preview token verification failed: ", err)
+		return nil, services.ErrPreviewTokenInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Preview content must never be stored by a shared cache.
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	return previewToken, nil
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"api-page/main/src/validation"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetRedirects func for getting all redirects paginated.
+func GetRedirects(c fiber.Ctx) error {
+	paginationModel, err := services.GetRedirects(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// GetRedirectByID func for getting a redirect by ID.
+func GetRedirectByID(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get redirect.
+	redirect, err := services.GetRedirectByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if redirect.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.RedirectExists, "Redirect does not exist.")
+	}
+
+	response := responses.Redirect{}
+	response.SetRedirect(redirect)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetPublishedRedirect func for resolving the redirect of a path against the published version of an app.
+func GetPublishedRedirect(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	locale := c.Query("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	path := c.Query("path")
+	if path == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Path parameter is required.")
+	}
+
+	redirect, err := services.GetPublishedRedirect(appName, locale, path)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if redirect == nil {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.RedirectExists, "No redirect found for the specified path and locale.")
+	}
+
+	response := responses.PublishedRedirect{}
+	response.SetRedirect(redirect)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// CreateRedirect func for creating a redirect.
+func CreateRedirect(c fiber.Ctx) error {
+	// Create a new redirect struct for the request.
+	redirectRequest := &requests.CreateRedirect{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(redirectRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate redirect fields.
+	if err := validation.Validate.Struct(redirectRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if app exists.
+	appAvailable, err := services.IsAppAvailable(redirectRequest.AppName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !appAvailable {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.AppNotFound, "App not found.")
+	}
+
+	// Check if the from path is available.
+	if available, err := services.IsRedirectAvailable(redirectRequest.AppName, redirectRequest.Locale, redirectRequest.FromPath, nil); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !available {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.RedirectAvailable, "Redirect from path already exist.")
+	}
+
+	// Create redirect.
+	redirect, err := services.CreateRedirect(redirectRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the redirect.
+	response := responses.Redirect{}
+	response.SetRedirect(redirect)
+
+	recordAudit(c, enums.AUDIT_REDIRECT, redirect.ID, enums.AUDIT_CREATE, nil, response)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// UpdateRedirect func for updating a redirect.
+func UpdateRedirect(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Create a new redirect struct for the request.
+	redirectRequest := &requests.UpdateRedirect{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(redirectRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate redirect fields.
+	if err := validation.Validate.Struct(redirectRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get old redirect.
+	oldRedirect, err := services.GetRedirectByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if oldRedirect.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.RedirectExists, "Redirect does not exist.")
+	}
+
+	// Check if the redirect has been modified since it was last fetched.
+	if redirectRequest.UpdatedAt.Unix() < oldRedirect.UpdatedAt.Unix() {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	}
+
+	// Check if the from path is available.
+	if available, err := services.IsRedirectAvailable(oldRedirect.AppName, redirectRequest.Locale, redirectRequest.FromPath, &oldRedirect.ID); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !available {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.RedirectAvailable, "Redirect from path already exist.")
+	}
+
+	before := responses.Redirect{}
+	before.SetRedirect(oldRedirect)
+
+	// Update redirect.
+	redirect, err := services.UpdateRedirect(oldRedirect, redirectRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the redirect.
+	response := responses.Redirect{}
+	response.SetRedirect(redirect)
+
+	recordAudit(c, enums.AUDIT_REDIRECT, redirect.ID, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteRedirect func for deleting a redirect.
+func DeleteRedirect(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Find the redirect.
+	redirect, err := services.GetRedirectByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if redirect.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.RedirectExists, "Redirect does not exist.")
+	}
+
+	before := responses.Redirect{}
+	before.SetRedirect(redirect)
+
+	// Delete the redirect.
+	if err := services.DeleteRedirect(redirect.ID); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_REDIRECT, redirect.ID, enums.AUDIT_DELETE, before, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreRedirect func for restoring a deleted redirect.
+func RestoreRedirect(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Check if redirect is deleted.
+	if isDeleted, err := services.IsRedirectDeleted(id); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !isDeleted {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.RedirectAvailable, "Redirect is not deleted.")
+	}
+
+	// Restore the redirect.
+	if err := services.RestoreRedirect(id); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_REDIRECT, id, enums.AUDIT_RESTORE, nil, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
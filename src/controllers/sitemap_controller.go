@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"encoding/xml"
+	"net/url"
+	"sort"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetSitemap func for getting the XML sitemap of the published version of an app in a locale.
+func GetSitemap(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	locale := c.Query("locale")
+	if locale == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
+	}
+
+	baseURL := c.Query("baseUrl")
+	if !isSitemapBaseURL(baseURL) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "BaseUrl parameter must be an absolute http(s) URL.")
+	}
+
+	// Get published version.
+	version, err := services.GetPublishedVersionByAppName(appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Published version does not exist.")
+	}
+
+	// Get sitemaps.
+	sitemaps, err := services.GetSitemaps(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	urls, ok := sitemaps[locale]
+	if !ok {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.SitemapExists, "Sitemap not found for the specified locale.")
+	}
+
+	response := responses.Sitemap{}
+	response.SetSitemap(locale, baseURL, urls)
+
+	return sendXML(c, response)
+}
+
+// GetSitemapIndex func for getting the XML sitemap index of the published version of an app across its locales.
+func GetSitemapIndex(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	baseURL := c.Query("baseUrl")
+	if !isSitemapBaseURL(baseURL) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "BaseUrl parameter must be an absolute http(s) URL.")
+	}
+
+	// Get published version.
+	version, err := services.GetPublishedVersionByAppName(appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Published version does not exist.")
+	}
+
+	// Get sitemaps.
+	sitemaps, err := services.GetSitemaps(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	locales := make([]string, 0, len(sitemaps))
+	for locale := range sitemaps {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	// Point every entry at the sitemap endpoint of this service.
+	locations := make([]string, len(locales))
+	lastMods := make([]string, len(locales))
+	for i, locale := range locales {
+		query := url.Values{}
+		query.Set("app", appName)
+		query.Set("locale", locale)
+		query.Set("baseUrl", baseURL)
+		locations[i] = c.BaseURL() + "/v1/sitemap.xml?" + query.Encode()
+
+		for _, sitemapURL := range sitemaps[locale] {
+			if sitemapURL.LastMod > lastMods[i] {
+				lastMods[i] = sitemapURL.LastMod
+			}
+		}
+	}
+
+	response := responses.SitemapIndex{}
+	response.SetSitemapIndex(locations, lastMods)
+
+	return sendXML(c, response)
+}
+
+// isSitemapBaseURL checks if the base URL of a sitemap is an absolute http(s) URL.
+func isSitemapBaseURL(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// sendXML sends a value as an XML document.
+func sendXML(c fiber.Ctx, value any) error {
+	body, err := xml.Marshal(value)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+
+	return c.Status(fiber.StatusOK).Send(append([]byte(xml.Header), body...))
+}
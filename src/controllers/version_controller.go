@@ -3,12 +3,19 @@ package controllers
 import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/errors"
+	"api-page/main/src/models"
 	"api-page/main/src/services"
+	"api-page/main/src/validation"
+	stderrors "errors"
+	"fmt"
+	"time"
 
 	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
 	util "github.com/ArnoldPMolenaar/api-utils/utils"
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
 )
 
 // GetVersions func for getting all versions paginated.
@@ -63,6 +70,8 @@ func GetVersionByID(c fiber.Ctx) error {
 	response := responses.Version{}
 	response.SetVersion(version)
 
+	setETag(c, version.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -73,7 +82,20 @@ func GetPublishedVersionByAppName(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App name parameter is required.")
 	}
 
-	version, err := services.GetPublishedVersionByAppName(appName)
+	// Get preview token.
+	previewToken, err := getPreviewToken(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusUnauthorized, errorutil.Unauthorized, err.Error())
+	} else if previewToken != nil && previewToken.AppName != appName {
+		return errorutil.Response(c, fiber.StatusForbidden, errorutil.Forbidden, "Preview token does not grant access to this app.")
+	}
+
+	var version *models.Version
+	if previewToken != nil {
+		version, err = services.GetVersionByID(previewToken.VersionID)
+	} else {
+		version, err = services.GetPublishedVersionByAppName(appName)
+	}
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	} else if version.ID == 0 {
@@ -83,7 +105,17 @@ func GetPublishedVersionByAppName(c fiber.Ctx) error {
 	response := responses.PublishedVersion{}
 	response.SetVersion(version)
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	if previewToken != nil {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	// Publishing another version changes the response, but not the content of that version.
+	lastModified := version.UpdatedAt
+	if version.PublishedAt.Time.After(lastModified) {
+		lastModified = version.PublishedAt.Time
+	}
+
+	return sendPublishedJSON(c, response, lastModified, appSurrogateKey(appName), versionSurrogateKey(version.ID))
 }
 
 // GetMenusByVersionID func for getting menus by version ID.
@@ -99,13 +131,34 @@ func GetMenusByVersionID(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Locale parameter is required.")
 	}
 
-	if isPublished, err := services.IsVersionPublished(versionID); err != nil {
+	// Get preview token.
+	previewToken, err := getPreviewToken(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusUnauthorized, errorutil.Unauthorized, err.Error())
+	} else if previewToken != nil && (previewToken.VersionID != versionID || !previewToken.AllowsLocale(locale)) {
+		return errorutil.Response(c, fiber.StatusForbidden, errorutil.Forbidden, "Preview token does not grant access to this version and locale.")
+	}
+
+	// A preview token grants access to a version that is not published.
+	if previewToken == nil {
+		if isPublished, err := services.IsVersionPublished(versionID); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if !isPublished {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionNotPublished, "Version is not published.")
+		}
+	}
+
+	chain, err := services.GetLocaleChainByVersionID(versionID, locale)
+	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
-	} else if !isPublished {
-		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionNotPublished, "Version is not published.")
 	}
 
-	menus, err := services.GetMenusByVersionID(versionID, locale)
+	var menus *[]models.Menu
+	if previewToken != nil {
+		menus, err = services.GetPreviewMenusByVersionIDAndLocales(versionID, chain)
+	} else {
+		menus, err = services.GetMenusByVersionIDAndLocales(versionID, chain)
+	}
 	if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
@@ -113,7 +166,11 @@ func GetMenusByVersionID(c fiber.Ctx) error {
 	response := responses.PublishedMenuList{}
 	response.SetMenuList(menus)
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	if previewToken != nil {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	return sendPublishedJSON(c, response, services.GetMenusLastModified(menus), versionSurrogateKey(versionID))
 }
 
 // IsVersionNameAvailable method to check if version is available.
@@ -182,6 +239,10 @@ func CreateVersion(c fiber.Ctx) error {
 	response := responses.Version{}
 	response.SetVersion(version)
 
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_CREATE, nil, response)
+
+	setETag(c, version.Revision)
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -217,8 +278,10 @@ func UpdateVersion(c fiber.Ctx) error {
 	}
 
 	// Check if the version has been modified since it was last fetched.
-	if versionRequest.UpdatedAt.Unix() < oldVersion.UpdatedAt.Unix() {
-		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	if present, matched := getIfMatch(c, oldVersion.Revision); !present {
+		return errorutil.Response(c, fiber.StatusPreconditionRequired, errors.IfMatchRequired, "If-Match header is required.")
+	} else if !matched {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
 	}
 
 	// Check if version exists.
@@ -234,9 +297,14 @@ func UpdateVersion(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionIsPublished, "Published versions must be enabled.")
 	}
 
+	before := responses.Version{}
+	before.SetVersion(oldVersion)
+
 	// Update version.
-	updatedVersion, err := services.UpdateVersion(oldVersion, versionRequest)
-	if err != nil {
+	updatedVersion, err := services.UpdateVersion(oldVersion, versionRequest, oldVersion.Revision)
+	if isRevisionConflict(err) {
+		return errorutil.Response(c, fiber.StatusPreconditionFailed, errorutil.OutOfSync, "Data is out of sync.")
+	} else if err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
@@ -244,6 +312,10 @@ func UpdateVersion(c fiber.Ctx) error {
 	response := responses.Version{}
 	response.SetVersion(updatedVersion)
 
+	recordAudit(c, enums.AUDIT_VERSION, updatedVersion.ID, enums.AUDIT_UPDATE, before, response)
+
+	setETag(c, updatedVersion.Revision)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -303,6 +375,8 @@ func DuplicateVersion(c fiber.Ctx) error {
 	response := responses.Version{}
 	response.SetVersion(duplicatedVersion)
 
+	recordAudit(c, enums.AUDIT_VERSION, duplicatedVersion.ID, enums.AUDIT_DUPLICATE, nil, response)
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -333,14 +407,401 @@ func PublishVersion(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionIsPublished, "Version is already published.")
 	}
 
+	before := responses.Version{}
+	before.SetVersion(version)
+
 	// Publish version.
-	if err := services.PublishVersion(version.AppName, version.ID); err != nil {
+	if err := services.PublishVersion(version.AppName, version.ID, getActor(c)); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	// Get the published version.
+	version, err = services.GetVersionByID(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	after := responses.Version{}
+	after.SetVersion(version)
+
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_PUBLISH, before, after)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// GetVersionDiff func for getting the changes that turn a version into another version of the same app.
+func GetVersionDiff(c fiber.Ctx) error {
+	// Get the version IDs from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	otherVersionID, err := util.StringToUint(c.Params("otherId"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get versions.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	otherVersion, err := services.GetVersionByID(otherVersionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if otherVersion.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Other version does not exist.")
+	}
+
+	// Check if both versions belong to the same app.
+	if version.AppName != otherVersion.AppName {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Versions do not belong to the same app.")
+	}
+
+	// Get changes.
+	changes, err := services.DiffVersions(version.ID, otherVersion.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.VersionDiff{}
+	response.SetVersionDiff(version.ID, otherVersion.ID, changes)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// MergeVersion func for merging selected changes of a source version into a version.
+func MergeVersion(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Create a new merge struct for the request.
+	mergeRequest := &requests.MergeVersion{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(mergeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate merge fields.
+	if err := validation.Validate.Struct(mergeRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get versions.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	sourceVersion, err := services.GetVersionByID(mergeRequest.SourceVersionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if sourceVersion.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Source version does not exist.")
+	}
+
+	// Check if both versions are different versions of the same app.
+	if version.AppName != sourceVersion.AppName {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "Versions do not belong to the same app.")
+	} else if version.ID == sourceVersion.ID {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "A version can not be merged into itself.")
+	}
+
+	// Merge the selected changes.
+	result, err := services.MergeVersion(version, sourceVersion, mergeRequest, getActor(c))
+	if err != nil {
+		if selectionErr, ok := err.(*services.MergeSelectionError); ok {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionMergeInvalid, selectionErr.Error())
+		} else if isPageSlugNotAvailable(err) {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+		}
+
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if len(result.Conflicts) > 0 && !mergeRequest.Force {
+		return errorutil.Response(c, fiber.StatusConflict, errors.VersionMergeConflict, result.Conflicts)
+	}
+
+	response := responses.VersionMerge{}
+	response.SetVersionMerge(version.ID, sourceVersion.ID, result.Applied, result.Conflicts)
+
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_MERGE, nil, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ExportVersion func for exporting a version as a portable bundle.
+func ExportVersion(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get version.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	// Get the content of the version.
+	export, err := services.GetVersionExport(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.VersionBundle{}
+	response.SetVersionBundle(version, export.Menus, export.Pages, export.FooterRows, export.Modules)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ImportVersion func for creating a version from a portable bundle.
+func ImportVersion(c fiber.Ctx) error {
+	// Create a new import struct for the request.
+	importRequest := &requests.ImportVersion{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(importRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate import fields.
+	if err := validation.Validate.Struct(importRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if app exists.
+	appAvailable, err := services.IsAppAvailable(importRequest.AppName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !appAvailable {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.AppNotFound, "App not found.")
+	}
+
+	// Check if version exists.
+	if available, err := services.IsVersionAvailable(importRequest.AppName, importRequest.Name, nil); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !available {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionAvailable, "Version name already exist.")
+	}
+
+	// Check if the module types of the bundle exist.
+	bundleModules := make(map[string]string, len(importRequest.Modules))
+	for i := range importRequest.Modules {
+		bundleModules[importRequest.Modules[i].Name] = importRequest.Modules[i].Type
+		if notAvailable, err := services.IsModuleTypeNotAvailable(importRequest.Modules[i].Type); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if notAvailable {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleTypeNotFound, "Module type "+importRequest.Modules[i].Type+" not found.")
+		}
+
+		// Migrate the settings to the latest schema of the module type and validate them against it.
+		settings, settingsVersion, fields, err := services.MigrateModuleSettingsToLatest(importRequest.Modules[i].Type, importRequest.Modules[i].Settings, importRequest.Modules[i].SettingsVersion)
+		if err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if len(fields) > 0 {
+			moduleFields := make(map[string]string, len(fields))
+			for field, message := range fields {
+				moduleFields[fmt.Sprintf("modules.%d.%s", i, field)] = message
+			}
+			return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, moduleFields)
+		}
+		importRequest.Modules[i].Settings = settings
+		importRequest.Modules[i].SettingsVersion = settingsVersion
+	}
+
+	// Check if the modules that are not part of the bundle exist in the app.
+	for _, name := range importRequest.GetModuleNames() {
+		if _, ok := bundleModules[name]; ok {
+			continue
+		}
+
+		if available, err := services.IsModuleNameAvailable(importRequest.AppName, name, nil); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if available {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.ModuleExists, "Module "+name+" does not exist.")
+		}
+	}
+
+	// Check if the module types of the columns are allowed in the pages and in the footers.
+	for _, inFooter := range []bool{false, true} {
+		names := importRequest.GetPageModuleNames()
+		field, location := "menus", "page"
+		if inFooter {
+			names = importRequest.GetFooterModuleNames()
+			field, location = "footers", "footer"
+		}
+
+		moduleTypes := make([]string, 0)
+		appModules := make([]string, 0)
+		for _, name := range names {
+			if moduleType, ok := bundleModules[name]; ok {
+				moduleTypes = append(moduleTypes, moduleType)
+			} else {
+				appModules = append(appModules, name)
+			}
+		}
+
+		notAllowed, err := services.GetModuleTypesNotAllowed(moduleTypes, inFooter)
+		if err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		}
+		if len(notAllowed) == 0 {
+			if notAllowed, err = services.GetModuleTypesNotAllowedByModuleNames(importRequest.AppName, appModules, inFooter); err != nil {
+				return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+			}
+		}
+		if len(notAllowed) > 0 {
+			return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, map[string]string{field: "Module type " + notAllowed[0] + " is not allowed in a " + location + "."})
+		}
+	}
+
+	// Check if the plugin types of the pages exist.
+	for _, plugin := range importRequest.GetPlugins() {
+		if notAvailable, err := services.IsPluginTypeNotAvailable(plugin); err != nil {
+			return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+		} else if notAvailable {
+			return errorutil.Response(c, fiber.StatusBadRequest, errors.PluginTypeNotFound, "Plugin type "+plugin+" not found.")
+		}
+	}
+
+	// Import version.
+	version, err := services.ImportVersion(importRequest, getActor(c))
+	if isPageSlugNotAvailable(err) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.PageSlugAvailable, "Page slug already exist.")
+	} else if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the version.
+	response := responses.Version{}
+	response.SetVersion(version)
+
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_IMPORT, nil, response)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// CreatePreviewToken func for creating a signed preview token of a version.
+func CreatePreviewToken(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Create a new preview token struct for the request.
+	previewRequest := &requests.CreatePreviewToken{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(previewRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate preview token fields.
+	if err := validation.Validate.Struct(previewRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get version.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	// Use the default lifetime when no expiry is given.
+	var expiresAt time.Time
+	if previewRequest.ExpiresAt != nil {
+		expiresAt = *previewRequest.ExpiresAt
+	} else if expiration, err := services.GetPreviewTokenExpiration(); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, err.Error())
+	} else {
+		expiresAt = time.Now().Add(expiration)
+	}
+	expiresAt = expiresAt.Truncate(time.Second)
+
+	if !expiresAt.After(time.Now()) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "ExpiresAt must be in the future.")
+	}
+
+	// Create preview token.
+	token, err := services.CreatePreviewToken(version, previewRequest.Locale, expiresAt)
+	if stderrors.Is(err, services.ErrPreviewTokenSecretNotConfigured) {
+		log.Error("Preview token creation failed: ", err)
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, "Preview token could not be created.")
+	} else if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.InternalServerError, err.Error())
+	}
+
+	response := responses.PreviewToken{}
+	response.SetPreviewToken(token, version, previewRequest.Locale, expiresAt)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetVersionPublications func for getting the publication history of an app paginated.
+func GetVersionPublications(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	paginationModel, err := services.GetVersionPublications(c, appName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// RollbackVersion func for publishing the version that was live before the current one again.
+func RollbackVersion(c fiber.Ctx) error {
+	appName := c.Query("app")
+	if appName == "" {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, "App parameter is required.")
+	}
+
+	// Roll back to the version that was live before, it is resolved while the versions of the app are locked.
+	version, err := services.RollbackVersion(appName, getActor(c))
+	if stderrors.Is(err, services.ErrVersionRollbackNotFound) {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionRollbackExists, "There is no previously published version to roll back to.")
+	} else if stderrors.Is(err, services.ErrVersionNotEnabled) {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionNotEnabled, "Version is not enabled.")
+	} else if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	before := responses.Version{}
+	before.SetVersion(version)
+
+	// Return the published version.
+	version, err = services.GetVersionByID(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	response := responses.Version{}
+	response.SetVersion(version)
+
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_ROLLBACK, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 // DeleteVersion func for deleting a version.
 func DeleteVersion(c fiber.Ctx) error {
 	// Get the ID from the URL.
@@ -362,11 +823,16 @@ func DeleteVersion(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionIsPublished, "Published versions cannot be deleted.")
 	}
 
+	before := responses.Version{}
+	before.SetVersion(version)
+
 	// Delete the Version.
 	if err := services.DeleteVersion(version.ID, version.AppName); err != nil {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_VERSION, version.ID, enums.AUDIT_DELETE, before, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -390,5 +856,7 @@ func RestoreVersion(c fiber.Ctx) error {
 		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
 	}
 
+	recordAudit(c, enums.AUDIT_VERSION, id, enums.AUDIT_RESTORE, nil, nil)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetVersionSchedule func for getting the publish schedule of a version.
+func GetVersionSchedule(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get schedule.
+	schedule, err := services.GetVersionSchedule(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if schedule.VersionID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionScheduleExists, "Version schedule does not exist.")
+	}
+
+	response := responses.VersionSchedule{}
+	response.SetVersionSchedule(schedule)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetVersionSchedule func for creating or replacing the publish schedule of a version.
+func SetVersionSchedule(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Create a new schedule struct for the request.
+	scheduleRequest := &requests.SetVersionSchedule{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(scheduleRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate schedule fields.
+	validate := util.NewValidator()
+	if err := validate.Struct(scheduleRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get version.
+	version, err := services.GetVersionByID(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if version.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionExists, "Version does not exist.")
+	}
+
+	// Check if version is enabled.
+	if !version.EnabledAt.Valid {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.VersionNotEnabled, "Version is not enabled.")
+	}
+
+	// Get old schedule.
+	oldSchedule, err := services.GetVersionSchedule(version.ID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Set schedule.
+	schedule, err := services.SetVersionSchedule(version, scheduleRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the schedule.
+	response := responses.VersionSchedule{}
+	response.SetVersionSchedule(schedule)
+
+	if oldSchedule.VersionID == 0 {
+		recordAudit(c, enums.AUDIT_VERSION_SCHEDULE, version.ID, enums.AUDIT_CREATE, nil, response)
+	} else {
+		before := responses.VersionSchedule{}
+		before.SetVersionSchedule(oldSchedule)
+
+		recordAudit(c, enums.AUDIT_VERSION_SCHEDULE, version.ID, enums.AUDIT_UPDATE, before, response)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteVersionSchedule func for deleting the publish schedule of a version.
+func DeleteVersionSchedule(c fiber.Ctx) error {
+	// Get the versionID parameter from the URL.
+	versionID, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get schedule.
+	schedule, err := services.GetVersionSchedule(versionID)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if schedule.VersionID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.VersionScheduleExists, "Version schedule does not exist.")
+	}
+
+	before := responses.VersionSchedule{}
+	before.SetVersionSchedule(schedule)
+
+	// Delete schedule.
+	if err := services.DeleteVersionSchedule(schedule.VersionID); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_VERSION_SCHEDULE, schedule.VersionID, enums.AUDIT_DELETE, before, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/errors"
+	"api-page/main/src/services"
+	"api-page/main/src/validation"
+
+	errorutil "github.com/ArnoldPMolenaar/api-utils/errors"
+	util "github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetWebhooks func for getting all webhooks paginated.
+func GetWebhooks(c fiber.Ctx) error {
+	paginationModel, err := services.GetWebhooks(c)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// GetWebhookByID func for getting a webhook by ID.
+func GetWebhookByID(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Get webhook.
+	webhook, err := services.GetWebhookByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if webhook.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.WebhookExists, "Webhook does not exist.")
+	}
+
+	response := responses.Webhook{}
+	response.SetWebhook(webhook)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetWebhookDeliveries func for getting the delivery log of a webhook paginated.
+func GetWebhookDeliveries(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Check if the webhook exists.
+	if webhook, err := services.GetWebhookByID(id); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if webhook.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.WebhookExists, "Webhook does not exist.")
+	}
+
+	paginationModel, err := services.GetWebhookDeliveries(c, id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(paginationModel)
+}
+
+// CreateWebhook func for creating a webhook.
+func CreateWebhook(c fiber.Ctx) error {
+	// Create a new webhook struct for the request.
+	webhookRequest := &requests.CreateWebhook{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(webhookRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate webhook fields.
+	if err := validation.Validate.Struct(webhookRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Check if app exists.
+	appAvailable, err := services.IsAppAvailable(webhookRequest.AppName)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !appAvailable {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.AppNotFound, "App not found.")
+	}
+
+	// Create webhook.
+	webhook, err := services.CreateWebhook(webhookRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the webhook, the secret is only returned once.
+	response := responses.Webhook{}
+	response.SetWebhook(webhook)
+
+	recordAudit(c, enums.AUDIT_WEBHOOK, webhook.ID, enums.AUDIT_CREATE, nil, response)
+
+	response.SetSecret(webhook)
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// UpdateWebhook func for updating a webhook.
+func UpdateWebhook(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Create a new webhook struct for the request.
+	webhookRequest := &requests.UpdateWebhook{}
+
+	// Check, if received JSON data is parsed.
+	if err := c.Bind().Body(webhookRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.BodyParse, err.Error())
+	}
+
+	// Validate webhook fields.
+	if err := validation.Validate.Struct(webhookRequest); err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.Validator, util.ValidatorErrors(err))
+	}
+
+	// Get old webhook.
+	oldWebhook, err := services.GetWebhookByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if oldWebhook.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.WebhookExists, "Webhook does not exist.")
+	}
+
+	// Check if the webhook has been modified since it was last fetched.
+	if webhookRequest.UpdatedAt.Unix() < oldWebhook.UpdatedAt.Unix() {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.OutOfSync, "Data is out of sync.")
+	}
+
+	before := responses.Webhook{}
+	before.SetWebhook(oldWebhook)
+
+	// Update webhook.
+	webhook, err := services.UpdateWebhook(oldWebhook, webhookRequest)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	// Return the webhook.
+	response := responses.Webhook{}
+	response.SetWebhook(webhook)
+
+	recordAudit(c, enums.AUDIT_WEBHOOK, webhook.ID, enums.AUDIT_UPDATE, before, response)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteWebhook func for deleting a webhook.
+func DeleteWebhook(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Find the webhook.
+	webhook, err := services.GetWebhookByID(id)
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if webhook.ID == 0 {
+		return errorutil.Response(c, fiber.StatusNotFound, errors.WebhookExists, "Webhook does not exist.")
+	}
+
+	before := responses.Webhook{}
+	before.SetWebhook(webhook)
+
+	// Delete the webhook.
+	if err := services.DeleteWebhook(webhook.ID); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_WEBHOOK, webhook.ID, enums.AUDIT_DELETE, before, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreWebhook func for restoring a deleted webhook.
+func RestoreWebhook(c fiber.Ctx) error {
+	// Get the ID from the URL.
+	id, err := util.StringToUint(c.Params("id"))
+	if err != nil {
+		return errorutil.Response(c, fiber.StatusBadRequest, errorutil.InvalidParam, err.Error())
+	}
+
+	// Check if webhook is deleted.
+	if isDeleted, err := services.IsWebhookDeleted(id); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	} else if !isDeleted {
+		return errorutil.Response(c, fiber.StatusBadRequest, errors.WebhookExists, "Webhook is not deleted.")
+	}
+
+	// Restore the webhook.
+	if err := services.RestoreWebhook(id); err != nil {
+		return errorutil.Response(c, fiber.StatusInternalServerError, errorutil.QueryError, err.Error())
+	}
+
+	recordAudit(c, enums.AUDIT_WEBHOOK, id, enums.AUDIT_RESTORE, nil, nil)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -19,12 +19,25 @@ func Migrate(db *gorm.DB) error {
 		return tx.Error
 	}
 
+	// Adds the version of the menu item to existing pages before the unique slug index is created.
+	if db.Migrator().HasTable(&models.Page{}) && !db.Migrator().HasColumn(&models.Page{}, "VersionID") {
+		if err := db.Migrator().AddColumn(&models.Page{}, "VersionID"); err != nil {
+			return err
+		}
+		if tx := db.Exec(`UPDATE pages SET version_id = menu_items.version_id FROM menu_items WHERE menu_items.id = pages.menu_item_id`); tx.Error != nil {
+			return tx.Error
+		}
+	}
+
 	// Updated migration set: normalized models.
 	err := db.AutoMigrate(
 		&models.App{},
 		&models.PluginType{},
 		&models.ModuleType{},
 		&models.Version{},
+		&models.VersionSchedule{},
+		&models.VersionPublication{},
+		&models.Footer{},
 		&models.FooterRow{},
 		&models.FooterRowColumn{},
 		&models.Menu{},
@@ -32,11 +45,18 @@ func Migrate(db *gorm.DB) error {
 		&models.MenuItemIndexing{},
 		&models.MenuItemRelation{},
 		&models.Module{},
+		&models.ModuleTypeSchema{},
+		&models.ModuleSnapshot{},
 		&models.Page{},
 		&models.PageIndexing{},
 		&models.PagePartial{},
 		&models.PagePartialRow{},
-		&models.PagePartialRowColumn{})
+		&models.PagePartialRowColumn{},
+		&models.PageRevision{},
+		&models.Redirect{},
+		&models.Audit{},
+		&models.Webhook{},
+		&models.WebhookDelivery{})
 	if err != nil {
 		return err
 	}
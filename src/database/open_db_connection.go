@@ -68,6 +68,8 @@ func MigrationReadinessCheck() error {
 		&models.PluginType{},
 		&models.ModuleType{},
 		&models.Version{},
+		&models.VersionSchedule{},
+		&models.VersionPublication{},
 		&models.FooterRow{},
 		&models.FooterRowColumn{},
 		&models.Menu{},
@@ -80,6 +82,7 @@ func MigrationReadinessCheck() error {
 		&models.PagePartial{},
 		&models.PagePartialRow{},
 		&models.PagePartialRowColumn{},
+		&models.Redirect{},
 	}
 
 	for _, table := range requiredTables {
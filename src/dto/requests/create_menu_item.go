@@ -32,3 +32,20 @@ func (c *CreateMenuItem) SetMenuItemRelation(relation *models.MenuItemRelation)
 	c.Indexing = indexing
 	c.Items = make([]CreateMenuItem, 0)
 }
+
+// SetImportVersionMenuItem sets the menu item and its children from a menu item of an imported bundle.
+func (c *CreateMenuItem) SetImportVersionMenuItem(item *ImportVersionMenuItem) {
+	items := make([]CreateMenuItem, 0, len(item.Items))
+	for i := range item.Items {
+		child := CreateMenuItem{}
+		child.SetImportVersionMenuItem(&item.Items[i])
+		items = append(items, child)
+	}
+
+	c.Position = item.Position
+	c.Name = item.Name
+	c.Icon = item.Icon
+	c.EnabledAt = item.EnabledAt
+	c.Indexing = item.Indexing
+	c.Items = items
+}
@@ -0,0 +1,13 @@
+package requests
+
+// CreateModuleType represents the request payload for creating a new module type.
+// A module type is allowed in footers and pages unless a flag says otherwise.
+type CreateModuleType struct {
+	Name            string  `json:"name" validate:"required,excludesall=/"`
+	DisplayName     string  `json:"displayName" validate:"required"`
+	Description     *string `json:"description"`
+	Icon            *string `json:"icon"`
+	AllowedInFooter *bool   `json:"allowedInFooter"`
+	AllowedInPage   *bool   `json:"allowedInPage"`
+	Behavior        string  `json:"behavior" validate:"omitempty,modulebehavior"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+// CreatePluginType represents the request payload for creating a new plugin type.
+type CreatePluginType struct {
+	Name        string  `json:"name" validate:"required,excludesall=/"`
+	DisplayName string  `json:"displayName" validate:"required"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon"`
+}
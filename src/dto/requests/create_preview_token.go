@@ -0,0 +1,9 @@
+package requests
+
+import "time"
+
+// CreatePreviewToken represents the request payload for creating a preview token of a version.
+type CreatePreviewToken struct {
+	Locale    *string    `json:"locale" validate:"omitempty,min=2"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
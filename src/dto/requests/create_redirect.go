@@ -0,0 +1,10 @@
+package requests
+
+// CreateRedirect represents the request payload for creating a redirect.
+type CreateRedirect struct {
+	AppName    string `json:"appName" validate:"required"`
+	Locale     string `json:"locale" validate:"required"`
+	FromPath   string `json:"fromPath" validate:"required,startswith=/"`
+	ToPath     string `json:"toPath" validate:"required"`
+	StatusCode uint16 `json:"statusCode" validate:"required,oneof=301 302"`
+}
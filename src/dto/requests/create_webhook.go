@@ -0,0 +1,13 @@
+package requests
+
+import "time"
+
+// CreateWebhook represents the request payload for creating a webhook.
+// A secret is generated when none is given.
+type CreateWebhook struct {
+	AppName   string     `json:"appName" validate:"required"`
+	Url       string     `json:"url" validate:"required,url"`
+	Secret    *string    `json:"secret" validate:"omitempty,min=16"`
+	Events    []string   `json:"events" validate:"required,min=1,dive,webhookevent"`
+	EnabledAt *time.Time `json:"enabledAt"`
+}
@@ -0,0 +1,213 @@
+package requests
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ImportVersion represents the request payload for importing a version bundle into an app.
+// The bundle is the one of the version export, entities are keyed by name instead of ID.
+type ImportVersion struct {
+	AppName   string                `json:"appName" validate:"required"`
+	Format    int                   `json:"format" validate:"required,eq=1"`
+	Name      string                `json:"name" validate:"required"`
+	EnabledAt *time.Time            `json:"enabledAt"`
+	Modules   []ImportVersionModule `json:"modules" validate:"dive"`
+	Menus     []ImportVersionMenu   `json:"menus" validate:"dive"`
+	Footers   []ImportVersionFooter `json:"footers" validate:"dive"`
+}
+
+type ImportVersionModule struct {
+	Type            string          `json:"type" validate:"required"`
+	Name            string          `json:"name" validate:"required"`
+	Settings        json.RawMessage `json:"settings" validate:"required,validjson"`
+	SettingsVersion uint            `json:"settingsVersion"`
+}
+
+type ImportVersionMenu struct {
+	Name  string                  `json:"name" validate:"required"`
+	Depth *uint8                  `json:"depth"`
+	Items []ImportVersionMenuItem `json:"items" validate:"required,min=1,dive"`
+}
+
+type ImportVersionMenuItem struct {
+	// Use a pointer to uint for Position to allow zero value and required validation.
+	Position  *uint                   `json:"position" validate:"required"`
+	Name      string                  `json:"name" validate:"required"`
+	Icon      *string                 `json:"icon"`
+	EnabledAt *time.Time              `json:"enabledAt"`
+	Indexing  []MenuItemIndexing      `json:"indexing" validate:"required,min=1,dive"`
+	Pages     []ImportVersionPage     `json:"pages" validate:"dive"`
+	Items     []ImportVersionMenuItem `json:"items" validate:"dive"`
+}
+
+type ImportVersionPage struct {
+	Locale          string                     `json:"locale" validate:"required,min=2"`
+	Plugin          *string                    `json:"plugin"`
+	Name            string                     `json:"name" validate:"required"`
+	Slug            *string                    `json:"slug" validate:"omitempty,slug,max=255"`
+	MetaTitle       *string                    `json:"metaTitle"`
+	MetaDescription *string                    `json:"metaDescription"`
+	Hashtag         *string                    `json:"hashtag"`
+	NewTabEnabled   bool                       `json:"newTabEnabled"`
+	UrlEnabled      bool                       `json:"urlEnabled"`
+	Url             *string                    `json:"url"`
+	EnabledAt       *time.Time                 `json:"enabledAt"`
+	Indexing        []PageIndexing             `json:"indexing" validate:"required,dive"`
+	Partials        []ImportVersionPagePartial `json:"partials" validate:"dive"`
+}
+
+type ImportVersionPagePartial struct {
+	Name string             `json:"name" validate:"required"`
+	Rows []ImportVersionRow `json:"rows" validate:"required,min=1,dive"`
+}
+
+type ImportVersionFooter struct {
+	Locale string             `json:"locale" validate:"required,min=2"`
+	Rows   []ImportVersionRow `json:"rows" validate:"required,dive"`
+}
+
+// ImportVersionRow is a row of a page partial or footer.
+type ImportVersionRow struct {
+	// Use a pointer to uint for Position to allow zero value and required validation.
+	Position        *uint                 `json:"position" validate:"required"`
+	NoGutters       bool                  `json:"noGutters"`
+	Dense           bool                  `json:"dense"`
+	Hashtag         *string               `json:"hashtag"`
+	Align           *string               `json:"align"`
+	AlignXxl        *string               `json:"alignXxl"`
+	AlignXl         *string               `json:"alignXl"`
+	AlignLg         *string               `json:"alignLg"`
+	AlignMd         *string               `json:"alignMd"`
+	AlignSm         *string               `json:"alignSm"`
+	AlignContent    *string               `json:"alignContent"`
+	AlignContentXxl *string               `json:"alignContentXxl"`
+	AlignContentXl  *string               `json:"alignContentXl"`
+	AlignContentLg  *string               `json:"alignContentLg"`
+	AlignContentMd  *string               `json:"alignContentMd"`
+	AlignContentSm  *string               `json:"alignContentSm"`
+	Justify         *string               `json:"justify"`
+	JustifyXxl      *string               `json:"justifyXxl"`
+	JustifyXl       *string               `json:"justifyXl"`
+	JustifyLg       *string               `json:"justifyLg"`
+	JustifyMd       *string               `json:"justifyMd"`
+	JustifySm       *string               `json:"justifySm"`
+	Columns         []ImportVersionColumn `json:"columns" validate:"required,min=1,dive"`
+}
+
+// ImportVersionColumn is a column of a page partial or footer row. The module is referenced by name.
+type ImportVersionColumn struct {
+	Module *string `json:"module"`
+	// Use a pointer to uint for Position to allow zero value and required validation.
+	Position  *uint              `json:"position" validate:"required"`
+	Cols      string             `json:"cols" validate:"required"`
+	Xxl       *int16             `json:"xxl"`
+	Xl        *int16             `json:"xl"`
+	Lg        *int16             `json:"lg"`
+	Md        *int16             `json:"md"`
+	Sm        *int16             `json:"sm"`
+	Xs        *int16             `json:"xs"`
+	Offset    *int16             `json:"offset"`
+	OffsetXxl *int16             `json:"offsetXxl"`
+	OffsetXl  *int16             `json:"offsetXl"`
+	OffsetLg  *int16             `json:"offsetLg"`
+	OffsetMd  *int16             `json:"offsetMd"`
+	OffsetSm  *int16             `json:"offsetSm"`
+	Order     *int16             `json:"order"`
+	OrderXxl  *int16             `json:"orderXxl"`
+	OrderXl   *int16             `json:"orderXl"`
+	OrderLg   *int16             `json:"orderLg"`
+	OrderMd   *int16             `json:"orderMd"`
+	OrderSm   *int16             `json:"orderSm"`
+	AlignSelf *string            `json:"alignSelf"`
+	Content   *string            `json:"content"`
+	Rows      []ImportVersionRow `json:"rows" validate:"dive"`
+}
+
+// GetModuleNames returns the names of the modules the columns of the bundle refer to.
+func (i *ImportVersion) GetModuleNames() []string {
+	return i.getModuleNames(true, true)
+}
+
+// GetPageModuleNames returns the names of the modules the columns of the pages of the bundle refer to.
+func (i *ImportVersion) GetPageModuleNames() []string {
+	return i.getModuleNames(true, false)
+}
+
+// GetFooterModuleNames returns the names of the modules the columns of the footers of the bundle refer to.
+func (i *ImportVersion) GetFooterModuleNames() []string {
+	return i.getModuleNames(false, true)
+}
+
+// getModuleNames returns the names of the modules the columns of the pages and/or footers of the bundle refer to.
+func (i *ImportVersion) getModuleNames(pages, footers bool) []string {
+	names := make(map[string]struct{})
+	var walkRows func(rows []ImportVersionRow)
+	walkRows = func(rows []ImportVersionRow) {
+		for j := range rows {
+			for k := range rows[j].Columns {
+				if rows[j].Columns[k].Module != nil {
+					names[*rows[j].Columns[k].Module] = struct{}{}
+				}
+				walkRows(rows[j].Columns[k].Rows)
+			}
+		}
+	}
+
+	var walkItems func(items []ImportVersionMenuItem)
+	walkItems = func(items []ImportVersionMenuItem) {
+		for j := range items {
+			for k := range items[j].Pages {
+				for l := range items[j].Pages[k].Partials {
+					walkRows(items[j].Pages[k].Partials[l].Rows)
+				}
+			}
+			walkItems(items[j].Items)
+		}
+	}
+
+	for j := range i.Menus {
+		if pages {
+			walkItems(i.Menus[j].Items)
+		}
+	}
+	for j := range i.Footers {
+		if footers {
+			walkRows(i.Footers[j].Rows)
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// GetPlugins returns the plugin types the pages of the bundle use.
+func (i *ImportVersion) GetPlugins() []string {
+	plugins := make(map[string]struct{})
+	var walkItems func(items []ImportVersionMenuItem)
+	walkItems = func(items []ImportVersionMenuItem) {
+		for j := range items {
+			for k := range items[j].Pages {
+				if items[j].Pages[k].Plugin != nil {
+					plugins[*items[j].Pages[k].Plugin] = struct{}{}
+				}
+			}
+			walkItems(items[j].Items)
+		}
+	}
+
+	for j := range i.Menus {
+		walkItems(i.Menus[j].Items)
+	}
+
+	result := make([]string, 0, len(plugins))
+	for plugin := range plugins {
+		result = append(result, plugin)
+	}
+
+	return result
+}
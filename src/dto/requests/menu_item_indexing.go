@@ -7,8 +7,8 @@ import (
 )
 
 type MenuItemIndexing struct {
-	Option string  `json:"option" validate:"required"`
-	Value  *string `json:"value"`
+	Option string  `json:"option" validate:"required,indexing"`
+	Value  *string `json:"value" validate:"indexingvalue"`
 }
 
 func (m *MenuItemIndexing) SetMenuItemIndexing(indexing *models.MenuItemIndexing) {
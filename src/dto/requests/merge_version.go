@@ -0,0 +1,15 @@
+package requests
+
+// MergeVersion represents the request payload for merging changes of a source version into a version.
+type MergeVersion struct {
+	SourceVersionID uint                 `json:"sourceVersionId" validate:"required"`
+	Force           bool                 `json:"force"`
+	Changes         []MergeVersionChange `json:"changes" validate:"required,min=1,dive"`
+}
+
+// MergeVersionChange selects a change of the version diff to merge.
+type MergeVersionChange struct {
+	Entity string  `json:"entity" validate:"required,oneof=menu menuItem page pagePartial pagePartialRow pagePartialRowColumn footer footerRow footerRowColumn"`
+	Path   string  `json:"path" validate:"required_unless=Entity footer"`
+	Locale *string `json:"locale"`
+}
@@ -0,0 +1,7 @@
+package requests
+
+// MigrateModuleSettings represents the request payload for migrating the settings of all modules of a module type.
+type MigrateModuleSettings struct {
+	ToVersion *uint `json:"toVersion" validate:"omitempty,min=1"`
+	DryRun    bool  `json:"dryRun"`
+}
@@ -1,6 +1,6 @@
 package requests
 
 type PageIndexing struct {
-	Option string  `json:"option" validate:"required"`
-	Value  *string `json:"value"`
+	Option string  `json:"option" validate:"required,indexing"`
+	Value  *string `json:"value" validate:"indexingvalue"`
 }
@@ -0,0 +1,8 @@
+package requests
+
+// SetAppLocaleFallbacks represents the request payload to replace the locale fallbacks of an app.
+// Fallbacks are keyed by locale; the "*" key holds the fallbacks for every locale.
+type SetAppLocaleFallbacks struct {
+	App       string              `json:"app" validate:"required"`
+	Fallbacks map[string][]string `json:"fallbacks" validate:"required,dive,keys,required,max=32,endkeys,dive,required,max=32"`
+}
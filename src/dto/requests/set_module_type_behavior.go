@@ -0,0 +1,7 @@
+package requests
+
+// SetModuleTypeBehavior represents the request payload for setting whether published pages render the live
+// or the versioned settings of the modules of a module type.
+type SetModuleTypeBehavior struct {
+	Behavior string `json:"behavior" validate:"required,modulebehavior"`
+}
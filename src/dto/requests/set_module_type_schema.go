@@ -0,0 +1,20 @@
+package requests
+
+import "encoding/json"
+
+// SetModuleTypeSchema represents the request payload for adding a new schema version to a module type.
+// The migration transforms the settings of the previous schema version into the new version.
+type SetModuleTypeSchema struct {
+	Schema    json.RawMessage           `json:"schema" validate:"required,validjson"`
+	Migration []ModuleSettingsTransform `json:"migration" validate:"dive"`
+}
+
+// ModuleSettingsTransform represents a step of a module settings migration.
+// Paths are dot separated keys of nested objects, e.g. "image.alt".
+type ModuleSettingsTransform struct {
+	Op    string          `json:"op" validate:"required,modulesettingstransform"`
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
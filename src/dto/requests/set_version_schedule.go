@@ -0,0 +1,9 @@
+package requests
+
+import "time"
+
+// SetVersionSchedule represents the request payload for scheduling the publication of a version.
+type SetVersionSchedule struct {
+	PublishAt   time.Time  `json:"publishAt" validate:"required"`
+	UnpublishAt *time.Time `json:"unpublishAt" validate:"omitempty,gtfield=PublishAt"`
+}
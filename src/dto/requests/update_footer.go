@@ -3,3 +3,22 @@ package requests
 type UpdateFooter struct {
 	Rows []UpdateFooterRow `json:"rows" validate:"required,dive"`
 }
+
+// GetModuleIDs returns the IDs of the modules the columns of the footer refer to.
+func (u *UpdateFooter) GetModuleIDs() []uint {
+	moduleIDs := make([]uint, 0)
+	var walkRows func(rows []UpdateFooterRow)
+	walkRows = func(rows []UpdateFooterRow) {
+		for i := range rows {
+			for j := range rows[i].Columns {
+				if rows[i].Columns[j].ModuleID != nil {
+					moduleIDs = append(moduleIDs, *rows[i].Columns[j].ModuleID)
+				}
+				walkRows(rows[i].Columns[j].Rows)
+			}
+		}
+	}
+	walkRows(u.Rows)
+
+	return moduleIDs
+}
@@ -2,7 +2,6 @@ package requests
 
 import (
 	"api-page/main/src/models"
-	"time"
 
 	"github.com/ArnoldPMolenaar/api-utils/utils"
 )
@@ -34,7 +33,6 @@ type UpdateFooterRow struct {
 	JustifyLg       *string                 `json:"justifyLg"`
 	JustifyMd       *string                 `json:"justifyMd"`
 	JustifySm       *string                 `json:"justifySm"`
-	UpdatedAt       *time.Time              `json:"updatedAt"`
 	Columns         []UpdateFooterRowColumn `json:"columns" validate:"required,min=1,dive"`
 }
 
@@ -72,3 +70,39 @@ func (u *UpdateFooterRow) SetFooterRow(row *models.FooterRow, versionID uint, lo
 	u.JustifySm = utils.PtrFromNullString(row.JustifySm)
 	u.Columns = columns
 }
+
+// SetImportVersionRow sets the row from a row of an imported bundle, with the module names mapped to IDs.
+func (u *UpdateFooterRow) SetImportVersionRow(row *ImportVersionRow, versionID uint, locale string, moduleIDs map[string]uint) {
+	columns := make([]UpdateFooterRowColumn, 0, len(row.Columns))
+	for i := range row.Columns {
+		column := UpdateFooterRowColumn{}
+		column.SetImportVersionColumn(&row.Columns[i], versionID, locale, moduleIDs)
+		columns = append(columns, column)
+	}
+
+	u.VersionID = versionID
+	u.Locale = locale
+	u.Position = row.Position
+	u.NoGutters = row.NoGutters
+	u.Dense = row.Dense
+	u.Hashtag = row.Hashtag
+	u.Align = row.Align
+	u.AlignXxl = row.AlignXxl
+	u.AlignXl = row.AlignXl
+	u.AlignLg = row.AlignLg
+	u.AlignMd = row.AlignMd
+	u.AlignSm = row.AlignSm
+	u.AlignContent = row.AlignContent
+	u.AlignContentXxl = row.AlignContentXxl
+	u.AlignContentXl = row.AlignContentXl
+	u.AlignContentLg = row.AlignContentLg
+	u.AlignContentMd = row.AlignContentMd
+	u.AlignContentSm = row.AlignContentSm
+	u.Justify = row.Justify
+	u.JustifyXxl = row.JustifyXxl
+	u.JustifyXl = row.JustifyXl
+	u.JustifyLg = row.JustifyLg
+	u.JustifyMd = row.JustifyMd
+	u.JustifySm = row.JustifySm
+	u.Columns = columns
+}
@@ -1,6 +1,11 @@
 package requests
 
-import "time"
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
 
 type UpdateMenuItem struct {
 	ID *uint `json:"id"`
@@ -8,8 +13,25 @@ type UpdateMenuItem struct {
 	Position  *uint              `json:"position" validate:"required"`
 	Name      string             `json:"name" validate:"required"`
 	Icon      *string            `json:"icon"`
-	UpdatedAt *time.Time         `json:"updatedAt"`
 	EnabledAt *time.Time         `json:"enabledAt"`
 	Indexing  []MenuItemIndexing `json:"indexing" validate:"required,min=1,dive"`
 	Items     []UpdateMenuItem   `json:"items" validate:"dive"`
 }
+
+func (u *UpdateMenuItem) SetMenuItemRelation(relation *models.MenuItemRelation, id *uint) {
+	indexing := make([]MenuItemIndexing, 0, len(relation.MenuItemChild.Indexing))
+	for i := range relation.MenuItemChild.Indexing {
+		menuItemIndexing := MenuItemIndexing{}
+		menuItemIndexing.SetMenuItemIndexing(&relation.MenuItemChild.Indexing[i])
+		indexing = append(indexing, menuItemIndexing)
+	}
+
+	position := relation.Position
+	u.ID = id
+	u.Position = &position
+	u.Name = relation.MenuItemChild.Name
+	u.Icon = utils.PtrFromNullString(relation.MenuItemChild.Icon)
+	u.EnabledAt = utils.PtrFromNullTime(relation.MenuItemChild.EnabledAt)
+	u.Indexing = indexing
+	u.Items = make([]UpdateMenuItem, 0)
+}
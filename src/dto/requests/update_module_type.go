@@ -0,0 +1,12 @@
+package requests
+
+// UpdateModuleType represents the request payload for updating an existing module type.
+// A changed name renames the module type everywhere it is used.
+type UpdateModuleType struct {
+	Name            string  `json:"name" validate:"required,excludesall=/"`
+	DisplayName     string  `json:"displayName" validate:"required"`
+	Description     *string `json:"description"`
+	Icon            *string `json:"icon"`
+	AllowedInFooter bool    `json:"allowedInFooter"`
+	AllowedInPage   bool    `json:"allowedInPage"`
+}
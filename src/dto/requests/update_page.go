@@ -10,6 +10,7 @@ import (
 type UpdatePage struct {
 	Plugin          *string        `json:"plugin"`
 	Name            string         `json:"name" validate:"required"`
+	Slug            *string        `json:"slug" validate:"omitempty,slug,max=255"`
 	MetaTitle       *string        `json:"metaTitle"`
 	MetaDescription *string        `json:"metaDescription"`
 	Hashtag         *string        `json:"hashtag"`
@@ -17,7 +18,6 @@ type UpdatePage struct {
 	UrlEnabled      bool           `json:"urlEnabled"`
 	Url             *string        `json:"url"`
 	EnabledAt       *time.Time     `json:"enabledAt"`
-	UpdatedAt       time.Time      `json:"updatedAt" validate:"required"`
 	Indexing        []PageIndexing `json:"indexing" validate:"required,dive"`
 }
 
@@ -32,6 +32,7 @@ func (u *UpdatePage) SetPage(page *models.Page) {
 
 	u.Plugin = utils.PtrFromNullString(page.Plugin)
 	u.Name = page.Name
+	u.Slug = utils.PtrFromNullString(page.Slug)
 	u.MetaTitle = utils.PtrFromNullString(page.MetaTitle)
 	u.MetaDescription = utils.PtrFromNullString(page.MetaDescription)
 	u.Hashtag = utils.PtrFromNullString(page.Hashtag)
@@ -39,6 +40,20 @@ func (u *UpdatePage) SetPage(page *models.Page) {
 	u.UrlEnabled = page.UrlEnabled
 	u.Url = utils.PtrFromNullString(page.Url)
 	u.EnabledAt = utils.PtrFromNullTime(page.EnabledAt)
-	u.UpdatedAt = page.UpdatedAt
 	u.Indexing = indexing
 }
+
+// SetImportVersionPage sets the page from a page of an imported bundle.
+func (u *UpdatePage) SetImportVersionPage(page *ImportVersionPage) {
+	u.Plugin = page.Plugin
+	u.Name = page.Name
+	u.Slug = page.Slug
+	u.MetaTitle = page.MetaTitle
+	u.MetaDescription = page.MetaDescription
+	u.Hashtag = page.Hashtag
+	u.NewTabEnabled = page.NewTabEnabled
+	u.UrlEnabled = page.UrlEnabled
+	u.Url = page.Url
+	u.EnabledAt = page.EnabledAt
+	u.Indexing = page.Indexing
+}
@@ -1,14 +1,10 @@
 package requests
 
-import (
-	"api-page/main/src/models"
-	"time"
-)
+import "api-page/main/src/models"
 
 type UpdatePagePartial struct {
-	Name      string                 `json:"name" validate:"required"`
-	UpdatedAt time.Time              `json:"updatedAt" validate:"required"`
-	Rows      []UpdatePagePartialRow `json:"rows" validate:"required,min=1,dive"`
+	Name string                 `json:"name" validate:"required"`
+	Rows []UpdatePagePartialRow `json:"rows" validate:"required,min=1,dive"`
 }
 
 func (u *UpdatePagePartial) SetPagePartial(partial *models.PagePartial, partialID uint) {
@@ -20,6 +16,24 @@ func (u *UpdatePagePartial) SetPagePartial(partial *models.PagePartial, partialI
 	}
 
 	u.Name = partial.Name
-	u.UpdatedAt = partial.UpdatedAt
 	u.Rows = rows
 }
+
+// GetModuleIDs returns the IDs of the modules the columns of the partial refer to.
+func (u *UpdatePagePartial) GetModuleIDs() []uint {
+	moduleIDs := make([]uint, 0)
+	var walkRows func(rows []UpdatePagePartialRow)
+	walkRows = func(rows []UpdatePagePartialRow) {
+		for i := range rows {
+			for j := range rows[i].Columns {
+				if rows[i].Columns[j].ModuleID != nil {
+					moduleIDs = append(moduleIDs, *rows[i].Columns[j].ModuleID)
+				}
+				walkRows(rows[i].Columns[j].Rows)
+			}
+		}
+	}
+	walkRows(u.Rows)
+
+	return moduleIDs
+}
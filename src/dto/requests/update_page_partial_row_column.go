@@ -2,7 +2,6 @@ package requests
 
 import (
 	"api-page/main/src/models"
-	"time"
 
 	"github.com/ArnoldPMolenaar/api-utils/utils"
 )
@@ -34,7 +33,6 @@ type UpdatePagePartialRowColumn struct {
 	OrderSm   *int16                 `json:"orderSm"`
 	AlignSelf *string                `json:"alignSelf"`
 	Content   *string                `json:"content"`
-	UpdatedAt *time.Time             `json:"updatedAt"`
 	Rows      []UpdatePagePartialRow `json:"rows" validate:"dive"`
 }
 
@@ -71,3 +69,41 @@ func (u *UpdatePagePartialRowColumn) SetPagePartialRowColumn(column *models.Page
 	u.Content = utils.PtrFromNullString(column.Content)
 	u.Rows = rows
 }
+
+// SetImportVersionColumn sets the column from a column of an imported bundle, with the module names mapped to IDs.
+func (u *UpdatePagePartialRowColumn) SetImportVersionColumn(column *ImportVersionColumn, partialID uint, moduleIDs map[string]uint) {
+	rows := make([]UpdatePagePartialRow, 0, len(column.Rows))
+	for i := range column.Rows {
+		row := UpdatePagePartialRow{}
+		row.SetImportVersionRow(&column.Rows[i], partialID, moduleIDs)
+		rows = append(rows, row)
+	}
+
+	if column.Module != nil {
+		moduleID := moduleIDs[*column.Module]
+		u.ModuleID = &moduleID
+	}
+	u.Position = column.Position
+	u.Cols = column.Cols
+	u.Xxl = column.Xxl
+	u.Xl = column.Xl
+	u.Lg = column.Lg
+	u.Md = column.Md
+	u.Sm = column.Sm
+	u.Xs = column.Xs
+	u.Offset = column.Offset
+	u.OffsetXxl = column.OffsetXxl
+	u.OffsetXl = column.OffsetXl
+	u.OffsetLg = column.OffsetLg
+	u.OffsetMd = column.OffsetMd
+	u.OffsetSm = column.OffsetSm
+	u.Order = column.Order
+	u.OrderXxl = column.OrderXxl
+	u.OrderXl = column.OrderXl
+	u.OrderLg = column.OrderLg
+	u.OrderMd = column.OrderMd
+	u.OrderSm = column.OrderSm
+	u.AlignSelf = column.AlignSelf
+	u.Content = column.Content
+	u.Rows = rows
+}
@@ -0,0 +1,10 @@
+package requests
+
+// UpdatePluginType represents the request payload for updating an existing plugin type.
+// A changed name renames the plugin type everywhere it is used.
+type UpdatePluginType struct {
+	Name        string  `json:"name" validate:"required,excludesall=/"`
+	DisplayName string  `json:"displayName" validate:"required"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon"`
+}
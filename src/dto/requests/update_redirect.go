@@ -0,0 +1,12 @@
+package requests
+
+import "time"
+
+// UpdateRedirect represents the request payload for updating a redirect.
+type UpdateRedirect struct {
+	Locale     string    `json:"locale" validate:"required"`
+	FromPath   string    `json:"fromPath" validate:"required,startswith=/"`
+	ToPath     string    `json:"toPath" validate:"required"`
+	StatusCode uint16    `json:"statusCode" validate:"required,oneof=301 302"`
+	UpdatedAt  time.Time `json:"updatedAt" validate:"required"`
+}
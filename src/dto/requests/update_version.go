@@ -6,5 +6,4 @@ import "time"
 type UpdateVersion struct {
 	Name      string     `json:"name" validate:"required"`
 	EnabledAt *time.Time `json:"enabledAt"`
-	UpdatedAt time.Time  `json:"updatedAt" validate:"required"`
 }
@@ -0,0 +1,13 @@
+package requests
+
+import "time"
+
+// UpdateWebhook represents the request payload for updating a webhook.
+// The secret is only replaced when one is given.
+type UpdateWebhook struct {
+	Url       string     `json:"url" validate:"required,url"`
+	Secret    *string    `json:"secret" validate:"omitempty,min=16"`
+	Events    []string   `json:"events" validate:"required,min=1,dive,webhookevent"`
+	EnabledAt *time.Time `json:"enabledAt"`
+	UpdatedAt time.Time  `json:"updatedAt" validate:"required"`
+}
@@ -0,0 +1,13 @@
+package responses
+
+// AppLocaleFallbacks represents the locale fallbacks of an app.
+type AppLocaleFallbacks struct {
+	App       string              `json:"app"`
+	Fallbacks map[string][]string `json:"fallbacks"`
+}
+
+// SetAppLocaleFallbacks maps the app and its locale fallbacks to response fields.
+func (alf *AppLocaleFallbacks) SetAppLocaleFallbacks(app string, fallbacks map[string][]string) {
+	alf.App = app
+	alf.Fallbacks = fallbacks
+}
@@ -0,0 +1,14 @@
+package responses
+
+import "time"
+
+type CacheWarmUp struct {
+	VersionID  uint       `json:"versionId"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Warmed     int        `json:"warmed"`
+	Failed     int        `json:"failed"`
+	Errors     []string   `json:"errors"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
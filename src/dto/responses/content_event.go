@@ -0,0 +1,13 @@
+package responses
+
+import "time"
+
+type ContentEvent struct {
+	Type       string    `json:"type"`
+	AppName    string    `json:"appName"`
+	VersionID  *uint     `json:"versionId"`
+	EntityID   string    `json:"entityId"`
+	PageID     *string   `json:"pageId,omitempty"`
+	Revision   *uint64   `json:"revision,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
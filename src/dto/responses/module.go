@@ -7,13 +7,14 @@ import (
 )
 
 type Module struct {
-	ID        uint            `json:"id"`
-	AppName   string          `json:"appName"`
-	Type      string          `json:"type"`
-	Name      string          `json:"name"`
-	Settings  json.RawMessage `json:"settings"`
-	CreatedAt time.Time       `json:"createdAt"`
-	UpdatedAt time.Time       `json:"updatedAt"`
+	ID              uint            `json:"id"`
+	AppName         string          `json:"appName"`
+	Type            string          `json:"type"`
+	Name            string          `json:"name"`
+	Settings        json.RawMessage `json:"settings"`
+	SettingsVersion uint            `json:"settingsVersion"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
 }
 
 // SetModule sets the Module response from the models.Module model.
@@ -23,6 +24,7 @@ func (m *Module) SetModule(module *models.Module) {
 	m.Type = module.Type
 	m.Name = module.Name
 	m.Settings = json.RawMessage(module.Settings)
+	m.SettingsVersion = module.SettingsVersion
 	m.CreatedAt = module.CreatedAt
 	m.UpdatedAt = module.UpdatedAt
 }
@@ -0,0 +1,21 @@
+package responses
+
+import "encoding/json"
+
+type ModuleSettingsMigration struct {
+	ModuleType string                          `json:"moduleType"`
+	ToVersion  uint                            `json:"toVersion"`
+	DryRun     bool                            `json:"dryRun"`
+	Migrated   int                             `json:"migrated"`
+	Failed     int                             `json:"failed"`
+	Modules    []ModuleSettingsMigrationModule `json:"modules"`
+}
+
+type ModuleSettingsMigrationModule struct {
+	ID          uint              `json:"id"`
+	AppName     string            `json:"appName"`
+	Name        string            `json:"name"`
+	FromVersion uint              `json:"fromVersion"`
+	Settings    json.RawMessage   `json:"settings,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
@@ -0,0 +1,33 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type ModuleType struct {
+	Name            string    `json:"name"`
+	DisplayName     string    `json:"displayName"`
+	Description     *string   `json:"description"`
+	Icon            *string   `json:"icon"`
+	AllowedInFooter bool      `json:"allowedInFooter"`
+	AllowedInPage   bool      `json:"allowedInPage"`
+	Behavior        string    `json:"behavior"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// SetModuleType sets the ModuleType response from the models.ModuleType model.
+func (mt *ModuleType) SetModuleType(moduleType *models.ModuleType) {
+	mt.Name = moduleType.Name
+	mt.DisplayName = moduleType.DisplayName
+	mt.Description = utils.PtrFromNullString(moduleType.Description)
+	mt.Icon = utils.PtrFromNullString(moduleType.Icon)
+	mt.AllowedInFooter = moduleType.AllowedInFooter
+	mt.AllowedInPage = moduleType.AllowedInPage
+	mt.Behavior = moduleType.Behavior.String()
+	mt.CreatedAt = moduleType.CreatedAt
+	mt.UpdatedAt = moduleType.UpdatedAt
+}
@@ -0,0 +1,27 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"encoding/json"
+	"time"
+)
+
+type ModuleTypeSchema struct {
+	ModuleType string                           `json:"moduleType"`
+	Version    uint                             `json:"version"`
+	Schema     json.RawMessage                  `json:"schema"`
+	Migration  []models.ModuleSettingsTransform `json:"migration"`
+	CreatedAt  time.Time                        `json:"createdAt"`
+}
+
+// SetModuleTypeSchema sets the ModuleTypeSchema response from the models.ModuleTypeSchema model.
+func (m *ModuleTypeSchema) SetModuleTypeSchema(schema *models.ModuleTypeSchema) {
+	m.ModuleType = schema.ModuleTypeName
+	m.Version = schema.Version
+	m.Schema = json.RawMessage(schema.Schema)
+	m.Migration = schema.Migration.Data()
+	if m.Migration == nil {
+		m.Migration = make([]models.ModuleSettingsTransform, 0)
+	}
+	m.CreatedAt = schema.CreatedAt
+}
@@ -0,0 +1,28 @@
+package responses
+
+type ModuleUsages struct {
+	ModuleID uint                `json:"moduleId"`
+	Total    int                 `json:"total"`
+	Pages    []ModulePageUsage   `json:"pages"`
+	Footers  []ModuleFooterUsage `json:"footers"`
+}
+
+type ModulePageUsage struct {
+	VersionID       uint   `json:"versionId"`
+	VersionName     string `json:"versionName"`
+	MenuItemID      uint   `json:"menuItemId"`
+	Locale          string `json:"locale"`
+	PageName        string `json:"pageName"`
+	PagePartialID   uint   `json:"pagePartialId"`
+	PagePartialName string `json:"pagePartialName"`
+	RowID           uint   `json:"rowId"`
+	ColumnID        uint   `json:"columnId"`
+}
+
+type ModuleFooterUsage struct {
+	VersionID   uint   `json:"versionId"`
+	VersionName string `json:"versionName"`
+	Locale      string `json:"locale"`
+	RowID       uint   `json:"rowId"`
+	ColumnID    uint   `json:"columnId"`
+}
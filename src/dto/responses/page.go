@@ -12,6 +12,7 @@ type Page struct {
 	Locale          string         `json:"locale"`
 	Plugin          *string        `json:"plugin"`
 	Name            string         `json:"name"`
+	Slug            *string        `json:"slug"`
 	MetaTitle       *string        `json:"metaTitle"`
 	MetaDescription *string        `json:"metaDescription"`
 	Hashtag         *string        `json:"hashtag"`
@@ -31,6 +32,7 @@ func (p *Page) SetPage(page *models.Page) {
 	p.Locale = page.Locale
 	p.Plugin = utils.PtrFromNullString(page.Plugin)
 	p.Name = page.Name
+	p.Slug = utils.PtrFromNullString(page.Slug)
 	p.MetaTitle = utils.PtrFromNullString(page.MetaTitle)
 	p.MetaDescription = utils.PtrFromNullString(page.MetaDescription)
 	p.Hashtag = utils.PtrFromNullString(page.Hashtag)
@@ -0,0 +1,27 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+)
+
+type PageRevision struct {
+	ID         uint      `json:"id"`
+	MenuItemID uint      `json:"menuItemId"`
+	Locale     string    `json:"locale"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Page       Page      `json:"page"`
+}
+
+// SetPageRevision sets the PageRevision response from the models.PageRevision model and the page it holds.
+func (pr *PageRevision) SetPageRevision(revision *models.PageRevision, page *Page) {
+	pr.ID = revision.ID
+	pr.MenuItemID = revision.MenuItemID
+	pr.Locale = revision.Locale
+	pr.Actor = revision.Actor
+	pr.Action = revision.Action.String()
+	pr.CreatedAt = revision.CreatedAt
+	pr.Page = *page
+}
@@ -0,0 +1,30 @@
+package responses
+
+import "api-page/main/src/enums"
+
+type PageRevisionDiff struct {
+	RevisionID      uint            `json:"revisionId"`
+	OtherRevisionID uint            `json:"otherRevisionId"`
+	Added           int             `json:"added"`
+	Removed         int             `json:"removed"`
+	Modified        int             `json:"modified"`
+	Changes         []VersionChange `json:"changes"`
+}
+
+// SetPageRevisionDiff method to set the page revision diff data from the changes between two revisions.
+func (p *PageRevisionDiff) SetPageRevisionDiff(revisionID, otherRevisionID uint, changes []VersionChange) {
+	p.RevisionID = revisionID
+	p.OtherRevisionID = otherRevisionID
+	p.Changes = changes
+
+	for i := range changes {
+		switch changes[i].Change {
+		case enums.ADDED.String():
+			p.Added++
+		case enums.REMOVED.String():
+			p.Removed++
+		case enums.MODIFIED.String():
+			p.Modified++
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+)
+
+type PageRevisionList struct {
+	Revisions []PageRevisionListItem `json:"revisions"`
+}
+
+type PageRevisionListItem struct {
+	ID        uint      `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SetPageRevisionList sets the list of page revisions.
+func (prl *PageRevisionList) SetPageRevisionList(revisions *[]models.PageRevision) {
+	prl.Revisions = make([]PageRevisionListItem, len(*revisions))
+	for i := range *revisions {
+		prl.Revisions[i] = PageRevisionListItem{
+			ID:        (*revisions)[i].ID,
+			Actor:     (*revisions)[i].Actor,
+			Action:    (*revisions)[i].Action.String(),
+			CreatedAt: (*revisions)[i].CreatedAt,
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"encoding/json"
+	"time"
+)
+
+type PaginatedAudit struct {
+	ID        uint            `json:"id"`
+	Actor     string          `json:"actor"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entityId"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// SetPaginatedAudit method to set audit data from models.Audit{}.
+func (a *PaginatedAudit) SetPaginatedAudit(audit *models.Audit) {
+	a.ID = audit.ID
+	a.Actor = audit.Actor
+	a.Entity = audit.Entity.String()
+	a.EntityID = audit.EntityID
+	a.Action = audit.Action.String()
+	a.Before = json.RawMessage(audit.Before)
+	a.After = json.RawMessage(audit.After)
+	a.CreatedAt = audit.CreatedAt
+}
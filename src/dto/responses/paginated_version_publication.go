@@ -0,0 +1,35 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type PaginatedVersionPublication struct {
+	ID                  uint      `json:"id"`
+	AppName             string    `json:"appName"`
+	VersionID           uint      `json:"versionId"`
+	VersionName         string    `json:"versionName"`
+	Action              string    `json:"action"`
+	PreviousVersionID   *uint     `json:"previousVersionId"`
+	PreviousVersionName *string   `json:"previousVersionName"`
+	Actor               string    `json:"actor"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+// SetPaginatedVersionPublication method to set publication data from models.VersionPublication{}.
+func (v *PaginatedVersionPublication) SetPaginatedVersionPublication(publication *models.VersionPublication) {
+	v.ID = publication.ID
+	v.AppName = publication.AppName
+	v.VersionID = publication.VersionID
+	v.VersionName = publication.Version.Name
+	v.Action = publication.Action.String()
+	v.PreviousVersionID = utils.PtrFromNull[uint](publication.PreviousVersionID)
+	if publication.PreviousVersion != nil {
+		v.PreviousVersionName = &publication.PreviousVersion.Name
+	}
+	v.Actor = publication.Actor
+	v.CreatedAt = publication.CreatedAt
+}
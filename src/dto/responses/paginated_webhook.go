@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type PaginatedWebhook struct {
+	ID        uint       `json:"id"`
+	AppName   string     `json:"appName"`
+	Url       string     `json:"url"`
+	Events    []string   `json:"events"`
+	EnabledAt *time.Time `json:"enabledAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// SetPaginatedWebhook method to set webhook data from models.Webhook{}.
+func (w *PaginatedWebhook) SetPaginatedWebhook(webhook *models.Webhook) {
+	w.ID = webhook.ID
+	w.AppName = webhook.AppName
+	w.Url = webhook.Url
+	w.Events = make([]string, 0)
+	for _, event := range webhook.Events.Data() {
+		w.Events = append(w.Events, event.String())
+	}
+	w.EnabledAt = utils.PtrFromNullTime(webhook.EnabledAt)
+	w.CreatedAt = webhook.CreatedAt
+	w.UpdatedAt = webhook.UpdatedAt
+}
@@ -0,0 +1,40 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"encoding/json"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type PaginatedWebhookDelivery struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhookId"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       uint            `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time      `json:"lastAttemptAt"`
+	ResponseStatus *int            `json:"responseStatus"`
+	Error          *string         `json:"error"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// SetPaginatedWebhookDelivery method to set delivery data from models.WebhookDelivery{}.
+func (d *PaginatedWebhookDelivery) SetPaginatedWebhookDelivery(delivery *models.WebhookDelivery) {
+	d.ID = delivery.ID
+	d.WebhookID = delivery.WebhookID
+	d.Event = delivery.Event.String()
+	d.Payload = json.RawMessage(delivery.Payload)
+	d.Status = delivery.Status.String()
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.LastAttemptAt = utils.PtrFromNullTime(delivery.LastAttemptAt)
+	d.ResponseStatus = utils.PtrFromNull(delivery.ResponseStatus)
+	d.Error = utils.PtrFromNullString(delivery.Error)
+	d.CreatedAt = delivery.CreatedAt
+	d.UpdatedAt = delivery.UpdatedAt
+}
@@ -0,0 +1,27 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type PluginType struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+	Description *string   `json:"description"`
+	Icon        *string   `json:"icon"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// SetPluginType sets the PluginType response from the models.PluginType model.
+func (pt *PluginType) SetPluginType(pluginType *models.PluginType) {
+	pt.Name = pluginType.Name
+	pt.DisplayName = pluginType.DisplayName
+	pt.Description = utils.PtrFromNullString(pluginType.Description)
+	pt.Icon = utils.PtrFromNullString(pluginType.Icon)
+	pt.CreatedAt = pluginType.CreatedAt
+	pt.UpdatedAt = pluginType.UpdatedAt
+}
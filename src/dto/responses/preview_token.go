@@ -0,0 +1,23 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+)
+
+type PreviewToken struct {
+	Token     string    `json:"token"`
+	VersionID uint      `json:"versionId"`
+	AppName   string    `json:"appName"`
+	Locale    *string   `json:"locale"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SetPreviewToken method to set preview token data from a signed token and the models.Version{} it grants access to.
+func (p *PreviewToken) SetPreviewToken(token string, version *models.Version, locale *string, expiresAt time.Time) {
+	p.Token = token
+	p.VersionID = version.ID
+	p.AppName = version.AppName
+	p.Locale = locale
+	p.ExpiresAt = expiresAt.UTC()
+}
@@ -3,11 +3,18 @@ package responses
 import "api-page/main/src/models"
 
 type PublishedFooter struct {
-	Rows []PublishedFooterRow `json:"rows"`
+	Locale *string              `json:"locale"`
+	Rows   []PublishedFooterRow `json:"rows"`
 }
 
 // SetFooter to bind the rows from models.FooterRow.
+// The locale is the locale of the rows, or nil when there are no rows.
 func (pfr *PublishedFooter) SetFooter(rows *[]models.FooterRow) {
+	pfr.Locale = nil
+	if len(*rows) > 0 {
+		pfr.Locale = &(*rows)[0].Locale
+	}
+
 	pfr.Rows = make([]PublishedFooterRow, len(*rows))
 	for i := range *rows {
 		pfr.Rows[i] = PublishedFooterRow{}
@@ -10,6 +10,7 @@ type PublishedMenuItem struct {
 	ID            uint                `json:"id"`
 	Position      uint                `json:"position"`
 	Name          string              `json:"name"`
+	Locale        string              `json:"locale"`
 	URLName       string              `json:"urlName"`
 	Hashtag       *string             `json:"hashtag"`
 	Icon          *string             `json:"icon"`
@@ -31,7 +32,8 @@ func (pmi *PublishedMenuItem) SetMenuItem(menuItem *models.MenuItem, position ui
 
 	if page != nil {
 		pmi.Name = page.Name
-		pmi.URLName = utils.URLEncode(page.Name)
+		pmi.Locale = page.Locale
+		pmi.URLName = page.URLName()
 		pmi.UrlEnabled = page.UrlEnabled
 		pmi.NewTabEnabled = page.NewTabEnabled
 		pmi.Hashtag = utils.PtrFromNullString(page.Hashtag)
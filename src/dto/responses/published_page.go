@@ -7,15 +7,18 @@ import (
 )
 
 type PublishedPage struct {
+	Locale          string                 `json:"locale"`
 	Plugin          *string                `json:"plugin"`
 	MetaTitle       *string                `json:"metaTitle"`
 	MetaDescription *string                `json:"metaDescription"`
 	Indexing        []PageIndexing         `json:"indexing"`
+	Robots          string                 `json:"robots"`
 	Partials        []PublishedPagePartial `json:"partials"`
 }
 
 // SetPage sets the Page response from models.Page.
 func (pp *PublishedPage) SetPage(page *models.Page) {
+	pp.Locale = page.Locale
 	pp.Plugin = utils.PtrFromNullString(page.Plugin)
 	pp.MetaTitle = utils.PtrFromNullString(page.MetaTitle)
 	pp.MetaDescription = utils.PtrFromNullString(page.MetaDescription)
@@ -34,3 +37,8 @@ func (pp *PublishedPage) SetPage(page *models.Page) {
 		pp.Partials[i] = ppp
 	}
 }
+
+// SetRobots sets the merged robots directive string of the page.
+func (pp *PublishedPage) SetRobots(robots string) {
+	pp.Robots = robots
+}
@@ -0,0 +1,14 @@
+package responses
+
+import "api-page/main/src/models"
+
+type PublishedRedirect struct {
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode"`
+}
+
+// SetRedirect sets the PublishedRedirect response from models.Redirect.
+func (pr *PublishedRedirect) SetRedirect(redirect *models.Redirect) {
+	pr.Path = redirect.ToPath
+	pr.StatusCode = int(redirect.StatusCode)
+}
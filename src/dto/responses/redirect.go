@@ -0,0 +1,37 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type Redirect struct {
+	ID         uint       `json:"id"`
+	AppName    string     `json:"appName"`
+	Locale     string     `json:"locale"`
+	FromPath   string     `json:"fromPath"`
+	ToPath     string     `json:"toPath"`
+	StatusCode uint16     `json:"statusCode"`
+	Automatic  bool       `json:"automatic"`
+	Hits       uint64     `json:"hits"`
+	LastHitAt  *time.Time `json:"lastHitAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// SetRedirect method to set redirect data from models.Redirect{}.
+func (r *Redirect) SetRedirect(redirect *models.Redirect) {
+	r.ID = redirect.ID
+	r.AppName = redirect.AppName
+	r.Locale = redirect.Locale
+	r.FromPath = redirect.FromPath
+	r.ToPath = redirect.ToPath
+	r.StatusCode = redirect.StatusCode
+	r.Automatic = redirect.Automatic
+	r.Hits = redirect.Hits
+	r.LastHitAt = utils.PtrFromNullTime(redirect.LastHitAt)
+	r.CreatedAt = redirect.CreatedAt
+	r.UpdatedAt = redirect.UpdatedAt
+}
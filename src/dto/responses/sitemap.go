@@ -0,0 +1,56 @@
+package responses
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+type Sitemap struct {
+	XMLName    xml.Name     `xml:"urlset"`
+	Xmlns      string       `xml:"xmlns,attr"`
+	XmlnsXhtml string       `xml:"xmlns:xhtml,attr"`
+	URLs       []SitemapURL `xml:"url"`
+}
+
+type SitemapURL struct {
+	Loc        string             `xml:"loc" json:"loc"`
+	LastMod    string             `xml:"lastmod,omitempty" json:"lastMod"`
+	Alternates []SitemapAlternate `xml:"xhtml:link" json:"alternates"`
+}
+
+type SitemapAlternate struct {
+	Rel      string `xml:"rel,attr" json:"rel"`
+	Hreflang string `xml:"hreflang,attr" json:"hreflang"`
+	Href     string `xml:"href,attr" json:"href"`
+}
+
+// SetSitemap sets the Sitemap response from URLs with site-relative paths.
+// The base URL is prepended to every path, where a {locale} placeholder is replaced
+// by the locale of the URL or of the alternate.
+func (s *Sitemap) SetSitemap(locale, baseURL string, urls []SitemapURL) {
+	s.Xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	s.XmlnsXhtml = "http://www.w3.org/1999/xhtml"
+
+	s.URLs = make([]SitemapURL, len(urls))
+	for i := range urls {
+		s.URLs[i] = SitemapURL{
+			Loc:        SitemapLocation(baseURL, locale, urls[i].Loc),
+			LastMod:    urls[i].LastMod,
+			Alternates: make([]SitemapAlternate, len(urls[i].Alternates)),
+		}
+
+		for j := range urls[i].Alternates {
+			alternate := urls[i].Alternates[j]
+			s.URLs[i].Alternates[j] = SitemapAlternate{
+				Rel:      alternate.Rel,
+				Hreflang: alternate.Hreflang,
+				Href:     SitemapLocation(baseURL, alternate.Hreflang, alternate.Href),
+			}
+		}
+	}
+}
+
+// SitemapLocation joins the base URL of a locale and a site-relative path.
+func SitemapLocation(baseURL, locale, path string) string {
+	return strings.TrimRight(strings.ReplaceAll(baseURL, "{locale}", locale), "/") + path
+}
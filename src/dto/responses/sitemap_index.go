@@ -0,0 +1,27 @@
+package responses
+
+import "encoding/xml"
+
+type SitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	Xmlns    string              `xml:"xmlns,attr"`
+	Sitemaps []SitemapIndexEntry `xml:"sitemap"`
+}
+
+type SitemapIndexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SetSitemapIndex sets the SitemapIndex response from the locations of the sitemaps and their last modification.
+func (si *SitemapIndex) SetSitemapIndex(locations, lastMods []string) {
+	si.Xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+	si.Sitemaps = make([]SitemapIndexEntry, len(locations))
+	for i := range locations {
+		si.Sitemaps[i] = SitemapIndexEntry{Loc: locations[i]}
+		if i < len(lastMods) {
+			si.Sitemaps[i].LastMod = lastMods[i]
+		}
+	}
+}
@@ -8,14 +8,15 @@ import (
 )
 
 type Version struct {
-	ID          uint       `json:"id"`
-	PublishID   string     `json:"publishId"`
-	AppName     string     `json:"appName"`
-	Name        string     `json:"name"`
-	EnabledAt   *time.Time `json:"enabledAt"`
-	PublishedAt *time.Time `json:"publishedAt"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
+	ID           uint       `json:"id"`
+	PublishID    string     `json:"publishId"`
+	AppName      string     `json:"appName"`
+	Name         string     `json:"name"`
+	EnabledAt    *time.Time `json:"enabledAt"`
+	PublishedAt  *time.Time `json:"publishedAt"`
+	DuplicatedAt *time.Time `json:"duplicatedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
 }
 
 // SetVersion method to set version data from models.Version{}.
@@ -26,6 +27,7 @@ func (v *Version) SetVersion(version *models.Version) {
 	v.Name = version.Name
 	v.EnabledAt = utils.PtrFromNullTime(version.EnabledAt)
 	v.PublishedAt = utils.PtrFromNullTime(version.PublishedAt)
+	v.DuplicatedAt = utils.PtrFromNullTime(version.DuplicatedAt)
 	v.CreatedAt = version.CreatedAt
 	v.UpdatedAt = version.UpdatedAt
 }
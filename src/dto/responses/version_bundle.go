@@ -0,0 +1,372 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+// VersionBundleFormat is the format of a version bundle, so a bundle of an older format can be recognized.
+const VersionBundleFormat = 1
+
+// VersionBundle is the portable content of a version. Entities are keyed by name instead of ID,
+// so the bundle can be imported into another app or environment.
+type VersionBundle struct {
+	Format    int                   `json:"format"`
+	Name      string                `json:"name"`
+	EnabledAt *time.Time            `json:"enabledAt"`
+	Modules   []VersionBundleModule `json:"modules"`
+	Menus     []VersionBundleMenu   `json:"menus"`
+	Footers   []VersionBundleFooter `json:"footers"`
+}
+
+type VersionBundleModule struct {
+	Type            string          `json:"type"`
+	Name            string          `json:"name"`
+	Settings        json.RawMessage `json:"settings"`
+	SettingsVersion uint            `json:"settingsVersion"`
+}
+
+type VersionBundleMenu struct {
+	Name  string                  `json:"name"`
+	Depth *uint8                  `json:"depth"`
+	Items []VersionBundleMenuItem `json:"items"`
+}
+
+type VersionBundleMenuItem struct {
+	Position  uint                    `json:"position"`
+	Name      string                  `json:"name"`
+	Icon      *string                 `json:"icon"`
+	EnabledAt *time.Time              `json:"enabledAt"`
+	Indexing  []MenuItemIndexing      `json:"indexing"`
+	Pages     []VersionBundlePage     `json:"pages"`
+	Items     []VersionBundleMenuItem `json:"items"`
+}
+
+type VersionBundlePage struct {
+	Locale          string                     `json:"locale"`
+	Plugin          *string                    `json:"plugin"`
+	Name            string                     `json:"name"`
+	Slug            *string                    `json:"slug"`
+	MetaTitle       *string                    `json:"metaTitle"`
+	MetaDescription *string                    `json:"metaDescription"`
+	Hashtag         *string                    `json:"hashtag"`
+	NewTabEnabled   bool                       `json:"newTabEnabled"`
+	UrlEnabled      bool                       `json:"urlEnabled"`
+	Url             *string                    `json:"url"`
+	EnabledAt       *time.Time                 `json:"enabledAt"`
+	Indexing        []PageIndexing             `json:"indexing"`
+	Partials        []VersionBundlePagePartial `json:"partials"`
+}
+
+type VersionBundlePagePartial struct {
+	Name string             `json:"name"`
+	Rows []VersionBundleRow `json:"rows"`
+}
+
+type VersionBundleFooter struct {
+	Locale string             `json:"locale"`
+	Rows   []VersionBundleRow `json:"rows"`
+}
+
+// VersionBundleRow is a row of a page partial or footer.
+type VersionBundleRow struct {
+	Position        uint                  `json:"position"`
+	NoGutters       bool                  `json:"noGutters"`
+	Dense           bool                  `json:"dense"`
+	Hashtag         *string               `json:"hashtag"`
+	Align           *string               `json:"align"`
+	AlignXxl        *string               `json:"alignXxl"`
+	AlignXl         *string               `json:"alignXl"`
+	AlignLg         *string               `json:"alignLg"`
+	AlignMd         *string               `json:"alignMd"`
+	AlignSm         *string               `json:"alignSm"`
+	AlignContent    *string               `json:"alignContent"`
+	AlignContentXxl *string               `json:"alignContentXxl"`
+	AlignContentXl  *string               `json:"alignContentXl"`
+	AlignContentLg  *string               `json:"alignContentLg"`
+	AlignContentMd  *string               `json:"alignContentMd"`
+	AlignContentSm  *string               `json:"alignContentSm"`
+	Justify         *string               `json:"justify"`
+	JustifyXxl      *string               `json:"justifyXxl"`
+	JustifyXl       *string               `json:"justifyXl"`
+	JustifyLg       *string               `json:"justifyLg"`
+	JustifyMd       *string               `json:"justifyMd"`
+	JustifySm       *string               `json:"justifySm"`
+	Columns         []VersionBundleColumn `json:"columns"`
+}
+
+// VersionBundleColumn is a column of a page partial or footer row. The module is referenced by name.
+type VersionBundleColumn struct {
+	Module    *string            `json:"module"`
+	Position  uint               `json:"position"`
+	Cols      string             `json:"cols"`
+	Xxl       *int16             `json:"xxl"`
+	Xl        *int16             `json:"xl"`
+	Lg        *int16             `json:"lg"`
+	Md        *int16             `json:"md"`
+	Sm        *int16             `json:"sm"`
+	Xs        *int16             `json:"xs"`
+	Offset    *int16             `json:"offset"`
+	OffsetXxl *int16             `json:"offsetXxl"`
+	OffsetXl  *int16             `json:"offsetXl"`
+	OffsetLg  *int16             `json:"offsetLg"`
+	OffsetMd  *int16             `json:"offsetMd"`
+	OffsetSm  *int16             `json:"offsetSm"`
+	Order     *int16             `json:"order"`
+	OrderXxl  *int16             `json:"orderXxl"`
+	OrderXl   *int16             `json:"orderXl"`
+	OrderLg   *int16             `json:"orderLg"`
+	OrderMd   *int16             `json:"orderMd"`
+	OrderSm   *int16             `json:"orderSm"`
+	AlignSelf *string            `json:"alignSelf"`
+	Content   *string            `json:"content"`
+	Rows      []VersionBundleRow `json:"rows"`
+}
+
+// SetVersionBundle method to set the version bundle from a version and its content.
+// The pages are keyed by menu item ID and the footer rows by locale. The modules are the ones the columns refer to, keyed by ID.
+func (v *VersionBundle) SetVersionBundle(version *models.Version, menus []models.Menu, pages map[uint][]models.Page, footerRows map[string][]models.FooterRow, modules map[uint]models.Module) {
+	moduleNames := make(map[uint]string, len(modules))
+	for id, module := range modules {
+		moduleNames[id] = module.Name
+		v.Modules = append(v.Modules, VersionBundleModule{Type: module.Type, Name: module.Name, Settings: json.RawMessage(module.Settings), SettingsVersion: module.SettingsVersion})
+	}
+
+	v.Format = VersionBundleFormat
+	v.Name = version.Name
+	v.EnabledAt = utils.PtrFromNullTime(version.EnabledAt)
+	if v.Modules == nil {
+		v.Modules = make([]VersionBundleModule, 0)
+	}
+	sort.Slice(v.Modules, func(i, j int) bool {
+		return v.Modules[i].Name < v.Modules[j].Name
+	})
+
+	v.Menus = make([]VersionBundleMenu, len(menus))
+	for i := range menus {
+		v.Menus[i].SetMenu(&menus[i], pages, moduleNames)
+	}
+
+	v.Footers = make([]VersionBundleFooter, 0, len(footerRows))
+	for locale, rows := range footerRows {
+		footer := VersionBundleFooter{Locale: locale, Rows: make([]VersionBundleRow, len(rows))}
+		for i := range rows {
+			footer.Rows[i].SetFooterRow(&rows[i], moduleNames)
+		}
+		v.Footers = append(v.Footers, footer)
+	}
+	sort.Slice(v.Footers, func(i, j int) bool {
+		return v.Footers[i].Locale < v.Footers[j].Locale
+	})
+}
+
+// SetMenu sets the VersionBundleMenu from the models.Menu model with its menu item relations.
+func (m *VersionBundleMenu) SetMenu(menu *models.Menu, pages map[uint][]models.Page, moduleNames map[uint]string) {
+	m.Name = menu.Name
+	m.Depth = utils.PtrFromNull(menu.Depth)
+	m.Items = newVersionBundleMenuItems(menu, sql.Null[uint]{}, pages, moduleNames)
+}
+
+// newVersionBundleMenuItems creates the bundle tree of the menu items under a parent.
+func newVersionBundleMenuItems(menu *models.Menu, parentID sql.Null[uint], pages map[uint][]models.Page, moduleNames map[uint]string) []VersionBundleMenuItem {
+	items := make([]VersionBundleMenuItem, 0)
+	for i := range menu.MenuItemRelations {
+		rel := &menu.MenuItemRelations[i]
+		if rel.MenuItemChild.ID == 0 || rel.MenuItemParentID != parentID {
+			continue
+		}
+
+		item := VersionBundleMenuItem{
+			Position:  rel.Position,
+			Name:      rel.MenuItemChild.Name,
+			Icon:      utils.PtrFromNullString(rel.MenuItemChild.Icon),
+			EnabledAt: utils.PtrFromNullTime(rel.MenuItemChild.EnabledAt),
+			Indexing:  make([]MenuItemIndexing, len(rel.MenuItemChild.Indexing)),
+			Pages:     make([]VersionBundlePage, len(pages[rel.MenuItemChildID])),
+			Items:     newVersionBundleMenuItems(menu, sql.Null[uint]{V: rel.MenuItemChildID, Valid: true}, pages, moduleNames),
+		}
+		for j := range rel.MenuItemChild.Indexing {
+			item.Indexing[j].SetMenuIndexing(&rel.MenuItemChild.Indexing[j])
+		}
+		for j := range pages[rel.MenuItemChildID] {
+			item.Pages[j].SetPage(&pages[rel.MenuItemChildID][j], moduleNames)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// SetPage sets the VersionBundlePage from the models.Page model with its partials.
+func (p *VersionBundlePage) SetPage(page *models.Page, moduleNames map[uint]string) {
+	p.Locale = page.Locale
+	p.Plugin = utils.PtrFromNullString(page.Plugin)
+	p.Name = page.Name
+	p.Slug = utils.PtrFromNullString(page.Slug)
+	p.MetaTitle = utils.PtrFromNullString(page.MetaTitle)
+	p.MetaDescription = utils.PtrFromNullString(page.MetaDescription)
+	p.Hashtag = utils.PtrFromNullString(page.Hashtag)
+	p.NewTabEnabled = page.NewTabEnabled
+	p.UrlEnabled = page.UrlEnabled
+	p.Url = utils.PtrFromNullString(page.Url)
+	p.EnabledAt = utils.PtrFromNullTime(page.EnabledAt)
+
+	p.Indexing = make([]PageIndexing, len(page.Indexing))
+	for i := range page.Indexing {
+		p.Indexing[i].SetPageIndexing(&page.Indexing[i])
+	}
+
+	p.Partials = make([]VersionBundlePagePartial, len(page.Partials))
+	for i := range page.Partials {
+		p.Partials[i].Name = page.Partials[i].Name
+		p.Partials[i].Rows = make([]VersionBundleRow, len(page.Partials[i].Rows))
+		for j := range page.Partials[i].Rows {
+			p.Partials[i].Rows[j].SetPagePartialRow(&page.Partials[i].Rows[j], moduleNames)
+		}
+	}
+}
+
+// SetPagePartialRow sets the VersionBundleRow from the models.PagePartialRow model.
+func (r *VersionBundleRow) SetPagePartialRow(row *models.PagePartialRow, moduleNames map[uint]string) {
+	r.Position = row.Position
+	r.NoGutters = row.NoGutters
+	r.Dense = row.Dense
+	r.Hashtag = utils.PtrFromNullString(row.Hashtag)
+	r.Align = utils.PtrFromNullString(row.Align)
+	r.AlignXxl = utils.PtrFromNullString(row.AlignXxl)
+	r.AlignXl = utils.PtrFromNullString(row.AlignXl)
+	r.AlignLg = utils.PtrFromNullString(row.AlignLg)
+	r.AlignMd = utils.PtrFromNullString(row.AlignMd)
+	r.AlignSm = utils.PtrFromNullString(row.AlignSm)
+	r.AlignContent = utils.PtrFromNullString(row.AlignContent)
+	r.AlignContentXxl = utils.PtrFromNullString(row.AlignContentXxl)
+	r.AlignContentXl = utils.PtrFromNullString(row.AlignContentXl)
+	r.AlignContentLg = utils.PtrFromNullString(row.AlignContentLg)
+	r.AlignContentMd = utils.PtrFromNullString(row.AlignContentMd)
+	r.AlignContentSm = utils.PtrFromNullString(row.AlignContentSm)
+	r.Justify = utils.PtrFromNullString(row.Justify)
+	r.JustifyXxl = utils.PtrFromNullString(row.JustifyXxl)
+	r.JustifyXl = utils.PtrFromNullString(row.JustifyXl)
+	r.JustifyLg = utils.PtrFromNullString(row.JustifyLg)
+	r.JustifyMd = utils.PtrFromNullString(row.JustifyMd)
+	r.JustifySm = utils.PtrFromNullString(row.JustifySm)
+
+	r.Columns = make([]VersionBundleColumn, len(row.Columns))
+	for i := range row.Columns {
+		r.Columns[i].SetPagePartialRowColumn(&row.Columns[i], moduleNames)
+	}
+}
+
+// SetFooterRow sets the VersionBundleRow from the models.FooterRow model.
+func (r *VersionBundleRow) SetFooterRow(row *models.FooterRow, moduleNames map[uint]string) {
+	r.Position = row.Position
+	r.NoGutters = row.NoGutters
+	r.Dense = row.Dense
+	r.Hashtag = utils.PtrFromNullString(row.Hashtag)
+	r.Align = utils.PtrFromNullString(row.Align)
+	r.AlignXxl = utils.PtrFromNullString(row.AlignXxl)
+	r.AlignXl = utils.PtrFromNullString(row.AlignXl)
+	r.AlignLg = utils.PtrFromNullString(row.AlignLg)
+	r.AlignMd = utils.PtrFromNullString(row.AlignMd)
+	r.AlignSm = utils.PtrFromNullString(row.AlignSm)
+	r.AlignContent = utils.PtrFromNullString(row.AlignContent)
+	r.AlignContentXxl = utils.PtrFromNullString(row.AlignContentXxl)
+	r.AlignContentXl = utils.PtrFromNullString(row.AlignContentXl)
+	r.AlignContentLg = utils.PtrFromNullString(row.AlignContentLg)
+	r.AlignContentMd = utils.PtrFromNullString(row.AlignContentMd)
+	r.AlignContentSm = utils.PtrFromNullString(row.AlignContentSm)
+	r.Justify = utils.PtrFromNullString(row.Justify)
+	r.JustifyXxl = utils.PtrFromNullString(row.JustifyXxl)
+	r.JustifyXl = utils.PtrFromNullString(row.JustifyXl)
+	r.JustifyLg = utils.PtrFromNullString(row.JustifyLg)
+	r.JustifyMd = utils.PtrFromNullString(row.JustifyMd)
+	r.JustifySm = utils.PtrFromNullString(row.JustifySm)
+
+	r.Columns = make([]VersionBundleColumn, len(row.Columns))
+	for i := range row.Columns {
+		r.Columns[i].SetFooterRowColumn(&row.Columns[i], moduleNames)
+	}
+}
+
+// SetPagePartialRowColumn sets the VersionBundleColumn from the models.PagePartialRowColumn model.
+func (c *VersionBundleColumn) SetPagePartialRowColumn(column *models.PagePartialRowColumn, moduleNames map[uint]string) {
+	c.Position = column.Position
+	c.Cols = column.Cols
+	c.Xxl = utils.PtrFromNullInt16(column.Xxl)
+	c.Xl = utils.PtrFromNullInt16(column.Xl)
+	c.Lg = utils.PtrFromNullInt16(column.Lg)
+	c.Md = utils.PtrFromNullInt16(column.Md)
+	c.Sm = utils.PtrFromNullInt16(column.Sm)
+	c.Xs = utils.PtrFromNullInt16(column.Xs)
+	c.Offset = utils.PtrFromNullInt16(column.Offset)
+	c.OffsetXxl = utils.PtrFromNullInt16(column.OffsetXxl)
+	c.OffsetXl = utils.PtrFromNullInt16(column.OffsetXl)
+	c.OffsetLg = utils.PtrFromNullInt16(column.OffsetLg)
+	c.OffsetMd = utils.PtrFromNullInt16(column.OffsetMd)
+	c.OffsetSm = utils.PtrFromNullInt16(column.OffsetSm)
+	c.Order = utils.PtrFromNullInt16(column.Order)
+	c.OrderXxl = utils.PtrFromNullInt16(column.OrderXxl)
+	c.OrderXl = utils.PtrFromNullInt16(column.OrderXl)
+	c.OrderLg = utils.PtrFromNullInt16(column.OrderLg)
+	c.OrderMd = utils.PtrFromNullInt16(column.OrderMd)
+	c.OrderSm = utils.PtrFromNullInt16(column.OrderSm)
+	c.AlignSelf = utils.PtrFromNullString(column.AlignSelf)
+	c.Content = utils.PtrFromNullString(column.Content)
+	c.Module = versionBundleModuleName(column.ModuleID, moduleNames)
+
+	c.Rows = make([]VersionBundleRow, len(column.PagePartialRows))
+	for i := range column.PagePartialRows {
+		c.Rows[i].SetPagePartialRow(&column.PagePartialRows[i], moduleNames)
+	}
+}
+
+// SetFooterRowColumn sets the VersionBundleColumn from the models.FooterRowColumn model.
+func (c *VersionBundleColumn) SetFooterRowColumn(column *models.FooterRowColumn, moduleNames map[uint]string) {
+	c.Position = column.Position
+	c.Cols = column.Cols
+	c.Xxl = utils.PtrFromNullInt16(column.Xxl)
+	c.Xl = utils.PtrFromNullInt16(column.Xl)
+	c.Lg = utils.PtrFromNullInt16(column.Lg)
+	c.Md = utils.PtrFromNullInt16(column.Md)
+	c.Sm = utils.PtrFromNullInt16(column.Sm)
+	c.Xs = utils.PtrFromNullInt16(column.Xs)
+	c.Offset = utils.PtrFromNullInt16(column.Offset)
+	c.OffsetXxl = utils.PtrFromNullInt16(column.OffsetXxl)
+	c.OffsetXl = utils.PtrFromNullInt16(column.OffsetXl)
+	c.OffsetLg = utils.PtrFromNullInt16(column.OffsetLg)
+	c.OffsetMd = utils.PtrFromNullInt16(column.OffsetMd)
+	c.OffsetSm = utils.PtrFromNullInt16(column.OffsetSm)
+	c.Order = utils.PtrFromNullInt16(column.Order)
+	c.OrderXxl = utils.PtrFromNullInt16(column.OrderXxl)
+	c.OrderXl = utils.PtrFromNullInt16(column.OrderXl)
+	c.OrderLg = utils.PtrFromNullInt16(column.OrderLg)
+	c.OrderMd = utils.PtrFromNullInt16(column.OrderMd)
+	c.OrderSm = utils.PtrFromNullInt16(column.OrderSm)
+	c.AlignSelf = utils.PtrFromNullString(column.AlignSelf)
+	c.Content = utils.PtrFromNullString(column.Content)
+	c.Module = versionBundleModuleName(column.ModuleID, moduleNames)
+
+	c.Rows = make([]VersionBundleRow, len(column.FooterRows))
+	for i := range column.FooterRows {
+		c.Rows[i].SetFooterRow(&column.FooterRows[i], moduleNames)
+	}
+}
+
+// versionBundleModuleName gets the name of the module of a column, or nil when it has none.
+func versionBundleModuleName(moduleID sql.Null[uint], moduleNames map[uint]string) *string {
+	if !moduleID.Valid {
+		return nil
+	}
+
+	if name, ok := moduleNames[moduleID.V]; ok {
+		return &name
+	}
+
+	return nil
+}
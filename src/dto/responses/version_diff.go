@@ -0,0 +1,50 @@
+package responses
+
+import "api-page/main/src/enums"
+
+type VersionDiff struct {
+	VersionID      uint            `json:"versionId"`
+	OtherVersionID uint            `json:"otherVersionId"`
+	Added          int             `json:"added"`
+	Removed        int             `json:"removed"`
+	Moved          int             `json:"moved"`
+	Modified       int             `json:"modified"`
+	Changes        []VersionChange `json:"changes"`
+}
+
+type VersionChange struct {
+	Entity   string               `json:"entity"`
+	Change   string               `json:"change"`
+	Path     string               `json:"path"`
+	FromPath *string              `json:"fromPath"`
+	Locale   *string              `json:"locale"`
+	ID       *uint                `json:"id"`
+	OtherID  *uint                `json:"otherId"`
+	Fields   []VersionFieldChange `json:"fields"`
+}
+
+type VersionFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// SetVersionDiff method to set the version diff data from the changes between two versions.
+func (v *VersionDiff) SetVersionDiff(versionID, otherVersionID uint, changes []VersionChange) {
+	v.VersionID = versionID
+	v.OtherVersionID = otherVersionID
+	v.Changes = changes
+
+	for i := range changes {
+		switch changes[i].Change {
+		case enums.ADDED.String():
+			v.Added++
+		case enums.REMOVED.String():
+			v.Removed++
+		case enums.MOVED.String():
+			v.Moved++
+		case enums.MODIFIED.String():
+			v.Modified++
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package responses
+
+type VersionMerge struct {
+	VersionID       uint            `json:"versionId"`
+	SourceVersionID uint            `json:"sourceVersionId"`
+	Applied         []VersionChange `json:"applied"`
+	Conflicts       []VersionChange `json:"conflicts"`
+}
+
+// SetVersionMerge method to set the version merge data from the applied and conflicting changes.
+func (v *VersionMerge) SetVersionMerge(versionID, sourceVersionID uint, applied, conflicts []VersionChange) {
+	v.VersionID = versionID
+	v.SourceVersionID = sourceVersionID
+	v.Applied = applied
+	v.Conflicts = conflicts
+}
@@ -0,0 +1,39 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type VersionSchedule struct {
+	VersionID         uint       `json:"versionId"`
+	AppName           string     `json:"appName"`
+	PublishAt         time.Time  `json:"publishAt"`
+	UnpublishAt       *time.Time `json:"unpublishAt"`
+	FallbackVersionID *uint      `json:"fallbackVersionId"`
+	PublishedAt       *time.Time `json:"publishedAt"`
+	UnpublishedAt     *time.Time `json:"unpublishedAt"`
+	Attempts          uint       `json:"attempts"`
+	LastError         *string    `json:"lastError"`
+	RetryAt           *time.Time `json:"retryAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// SetVersionSchedule method to set version schedule data from models.VersionSchedule{}.
+func (v *VersionSchedule) SetVersionSchedule(schedule *models.VersionSchedule) {
+	v.VersionID = schedule.VersionID
+	v.AppName = schedule.AppName
+	v.PublishAt = schedule.PublishAt
+	v.UnpublishAt = utils.PtrFromNullTime(schedule.UnpublishAt)
+	v.FallbackVersionID = utils.PtrFromNull[uint](schedule.FallbackVersionID)
+	v.PublishedAt = utils.PtrFromNullTime(schedule.PublishedAt)
+	v.UnpublishedAt = utils.PtrFromNullTime(schedule.UnpublishedAt)
+	v.Attempts = schedule.Attempts
+	v.LastError = utils.PtrFromNullString(schedule.LastError)
+	v.RetryAt = utils.PtrFromNullTime(schedule.RetryAt)
+	v.CreatedAt = schedule.CreatedAt
+	v.UpdatedAt = schedule.UpdatedAt
+}
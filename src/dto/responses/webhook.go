@@ -0,0 +1,39 @@
+package responses
+
+import (
+	"api-page/main/src/models"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+type Webhook struct {
+	ID        uint       `json:"id"`
+	AppName   string     `json:"appName"`
+	Url       string     `json:"url"`
+	Secret    *string    `json:"secret,omitempty"`
+	Events    []string   `json:"events"`
+	EnabledAt *time.Time `json:"enabledAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// SetWebhook method to set webhook data from models.Webhook{}.
+// The secret is left out, see SetSecret.
+func (w *Webhook) SetWebhook(webhook *models.Webhook) {
+	w.ID = webhook.ID
+	w.AppName = webhook.AppName
+	w.Url = webhook.Url
+	w.Events = make([]string, 0)
+	for _, event := range webhook.Events.Data() {
+		w.Events = append(w.Events, event.String())
+	}
+	w.EnabledAt = utils.PtrFromNullTime(webhook.EnabledAt)
+	w.CreatedAt = webhook.CreatedAt
+	w.UpdatedAt = webhook.UpdatedAt
+}
+
+// SetSecret method to add the secret of the webhook, so a receiver can verify its signatures.
+func (w *Webhook) SetSecret(webhook *models.Webhook) {
+	w.Secret = &webhook.Secret
+}
@@ -0,0 +1,89 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// AuditEntity is the kind of entity of an audit entry.
+type AuditEntity string
+
+const (
+	AUDIT_APP              AuditEntity = "app"
+	AUDIT_VERSION          AuditEntity = "version"
+	AUDIT_VERSION_SCHEDULE AuditEntity = "versionSchedule"
+	AUDIT_FOOTER           AuditEntity = "footer"
+	AUDIT_MENU             AuditEntity = "menu"
+	AUDIT_PAGE             AuditEntity = "page"
+	AUDIT_PAGE_PARTIAL     AuditEntity = "pagePartial"
+	AUDIT_REDIRECT         AuditEntity = "redirect"
+	AUDIT_MODULE           AuditEntity = "module"
+	AUDIT_MODULE_TYPE      AuditEntity = "moduleType"
+	AUDIT_PLUGIN_TYPE      AuditEntity = "pluginType"
+	AUDIT_WEBHOOK          AuditEntity = "webhook"
+)
+
+func (a *AuditEntity) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = ""
+		return nil
+	case string:
+		*a = AuditEntity(v)
+		return nil
+	case []byte:
+		*a = AuditEntity(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for AuditEntity: %T", value)
+	}
+}
+
+func (a AuditEntity) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+func (a AuditEntity) String() string {
+	return string(a)
+}
+
+// AuditAction is the action of an audit entry.
+type AuditAction string
+
+const (
+	AUDIT_CREATE    AuditAction = "create"
+	AUDIT_UPDATE    AuditAction = "update"
+	AUDIT_DELETE    AuditAction = "delete"
+	AUDIT_RESTORE   AuditAction = "restore"
+	AUDIT_DUPLICATE AuditAction = "duplicate"
+	AUDIT_MERGE     AuditAction = "merge"
+	AUDIT_IMPORT    AuditAction = "import"
+	AUDIT_PUBLISH   AuditAction = "publish"
+	AUDIT_UNPUBLISH AuditAction = "unpublish"
+	AUDIT_ROLLBACK  AuditAction = "rollback"
+	AUDIT_MIGRATE   AuditAction = "migrate"
+)
+
+func (a *AuditAction) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = ""
+		return nil
+	case string:
+		*a = AuditAction(v)
+		return nil
+	case []byte:
+		*a = AuditAction(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for AuditAction: %T", value)
+	}
+}
+
+func (a AuditAction) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+func (a AuditAction) String() string {
+	return string(a)
+}
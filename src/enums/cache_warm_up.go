@@ -0,0 +1,39 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// CacheWarmUpStatus is the state of the cache warm-up of a version.
+type CacheWarmUpStatus string
+
+const (
+	CACHE_WARM_UP_RUNNING   CacheWarmUpStatus = "running"
+	CACHE_WARM_UP_COMPLETED CacheWarmUpStatus = "completed"
+	CACHE_WARM_UP_FAILED    CacheWarmUpStatus = "failed"
+)
+
+func (c *CacheWarmUpStatus) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = ""
+		return nil
+	case string:
+		*c = CacheWarmUpStatus(v)
+		return nil
+	case []byte:
+		*c = CacheWarmUpStatus(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for CacheWarmUpStatus: %T", value)
+	}
+}
+
+func (c CacheWarmUpStatus) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+func (c CacheWarmUpStatus) String() string {
+	return string(c)
+}
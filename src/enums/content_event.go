@@ -0,0 +1,52 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// ContentEventType is the kind of change of a content event.
+type ContentEventType string
+
+const (
+	CONTENT_MENU_CREATED          ContentEventType = "menu.created"
+	CONTENT_MENU_UPDATED          ContentEventType = "menu.updated"
+	CONTENT_MENU_DELETED          ContentEventType = "menu.deleted"
+	CONTENT_MENU_RESTORED         ContentEventType = "menu.restored"
+	CONTENT_PAGE_UPDATED          ContentEventType = "page.updated"
+	CONTENT_PAGE_DELETED          ContentEventType = "page.deleted"
+	CONTENT_PAGE_RESTORED         ContentEventType = "page.restored"
+	CONTENT_PAGE_PARTIAL_CREATED  ContentEventType = "pagePartial.created"
+	CONTENT_PAGE_PARTIAL_UPDATED  ContentEventType = "pagePartial.updated"
+	CONTENT_PAGE_PARTIAL_DELETED  ContentEventType = "pagePartial.deleted"
+	CONTENT_PAGE_PARTIAL_RESTORED ContentEventType = "pagePartial.restored"
+	CONTENT_FOOTER_UPDATED        ContentEventType = "footer.updated"
+	CONTENT_MODULE_CREATED        ContentEventType = "module.created"
+	CONTENT_MODULE_UPDATED        ContentEventType = "module.updated"
+	CONTENT_MODULE_DELETED        ContentEventType = "module.deleted"
+	CONTENT_MODULE_RESTORED       ContentEventType = "module.restored"
+)
+
+func (c *ContentEventType) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = ""
+		return nil
+	case string:
+		*c = ContentEventType(v)
+		return nil
+	case []byte:
+		*c = ContentEventType(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for ContentEventType: %T", value)
+	}
+}
+
+func (c ContentEventType) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+func (c ContentEventType) String() string {
+	return string(c)
+}
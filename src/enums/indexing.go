@@ -3,6 +3,9 @@ package enums
 import (
 	"database/sql/driver"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Indexing string
@@ -54,3 +57,55 @@ func (i Indexing) Value() (driver.Value, error) {
 func (i Indexing) String() string {
 	return string(i)
 }
+
+// IsValid checks if the indexing option is part of the robots directive vocabulary.
+func (i Indexing) IsValid() bool {
+	switch i {
+	case ALL, FOLLOW, INDEX, INDEXIFEMBEDDED, MAX_IMAGE_PREVIEW, MAX_SNIPPET, MAX_VIDEO_PREVIEW,
+		NOAI, NOARCHIVE, NOCACHE, NOFOLLOW, NOIMAGEAI, NOIMAGEINDEX, NOINDEX, NOINDEXIFEMBEDDED,
+		NONE, NOODP, NOSNIPPET, NOTRANSLATE, NOYDIR, UNAVAILABLE_AFTER:
+		return true
+	}
+
+	return false
+}
+
+// HasValue checks if the indexing option is a directive that takes a value.
+func (i Indexing) HasValue() bool {
+	return i == MAX_IMAGE_PREVIEW || i == MAX_SNIPPET || i == MAX_VIDEO_PREVIEW || i == UNAVAILABLE_AFTER
+}
+
+// IsValidValue checks if a value is allowed for the indexing option.
+// Options with a value require one, all other options must not have a value.
+func (i Indexing) IsValidValue(value *string) bool {
+	if !i.HasValue() {
+		return value == nil || strings.TrimSpace(*value) == ""
+	} else if value == nil {
+		return false
+	}
+
+	v := strings.TrimSpace(*value)
+	switch i {
+	case MAX_SNIPPET, MAX_VIDEO_PREVIEW:
+		n, err := strconv.Atoi(v)
+		return err == nil && n >= -1
+	case MAX_IMAGE_PREVIEW:
+		return v == "none" || v == "standard" || v == "large"
+	case UNAVAILABLE_AFTER:
+		return ParseUnavailableAfter(v) != nil
+	}
+
+	return false
+}
+
+// ParseUnavailableAfter parses the date of an unavailable_after directive.
+// It accepts RFC 3339, RFC 850 and ISO 8601 dates and returns nil when the value is not a date.
+func ParseUnavailableAfter(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339, time.RFC850, time.RFC1123, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return &t
+		}
+	}
+
+	return nil
+}
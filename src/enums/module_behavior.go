@@ -0,0 +1,49 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// ModuleBehavior decides whether published pages render the live settings of a module
+// or the settings that were frozen when the version was published.
+type ModuleBehavior string
+
+const (
+	MODULE_LIVE      ModuleBehavior = "live"
+	MODULE_VERSIONED ModuleBehavior = "versioned"
+)
+
+// IsValid checks if the behavior is a known module behavior.
+func (m ModuleBehavior) IsValid() bool {
+	switch m {
+	case MODULE_LIVE, MODULE_VERSIONED:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *ModuleBehavior) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = ""
+		return nil
+	case string:
+		*m = ModuleBehavior(v)
+		return nil
+	case []byte:
+		*m = ModuleBehavior(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for ModuleBehavior: %T", value)
+	}
+}
+
+func (m ModuleBehavior) Value() (driver.Value, error) {
+	return string(m), nil
+}
+
+func (m ModuleBehavior) String() string {
+	return string(m)
+}
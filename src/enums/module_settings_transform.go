@@ -0,0 +1,51 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// ModuleSettingsTransformOp is the operation of a step that migrates module settings to the next schema version.
+type ModuleSettingsTransformOp string
+
+const (
+	MODULE_SETTINGS_RENAME  ModuleSettingsTransformOp = "rename"
+	MODULE_SETTINGS_COPY    ModuleSettingsTransformOp = "copy"
+	MODULE_SETTINGS_REMOVE  ModuleSettingsTransformOp = "remove"
+	MODULE_SETTINGS_SET     ModuleSettingsTransformOp = "set"
+	MODULE_SETTINGS_DEFAULT ModuleSettingsTransformOp = "default"
+)
+
+// IsValid checks if the operation is a known module settings transform.
+func (m ModuleSettingsTransformOp) IsValid() bool {
+	switch m {
+	case MODULE_SETTINGS_RENAME, MODULE_SETTINGS_COPY, MODULE_SETTINGS_REMOVE, MODULE_SETTINGS_SET, MODULE_SETTINGS_DEFAULT:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *ModuleSettingsTransformOp) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = ""
+		return nil
+	case string:
+		*m = ModuleSettingsTransformOp(v)
+		return nil
+	case []byte:
+		*m = ModuleSettingsTransformOp(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for ModuleSettingsTransformOp: %T", value)
+	}
+}
+
+func (m ModuleSettingsTransformOp) Value() (driver.Value, error) {
+	return string(m), nil
+}
+
+func (m ModuleSettingsTransformOp) String() string {
+	return string(m)
+}
@@ -0,0 +1,38 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type PublicationAction string
+
+const (
+	PUBLISH   PublicationAction = "publish"
+	UNPUBLISH PublicationAction = "unpublish"
+	ROLLBACK  PublicationAction = "rollback"
+)
+
+func (p *PublicationAction) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = ""
+		return nil
+	case string:
+		*p = PublicationAction(v)
+		return nil
+	case []byte:
+		*p = PublicationAction(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for PublicationAction: %T", value)
+	}
+}
+
+func (p PublicationAction) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+func (p PublicationAction) String() string {
+	return string(p)
+}
@@ -0,0 +1,34 @@
+package enums
+
+// VersionChange is the kind of change of an entry in a version diff.
+type VersionChange string
+
+const (
+	ADDED    VersionChange = "added"
+	REMOVED  VersionChange = "removed"
+	MOVED    VersionChange = "moved"
+	MODIFIED VersionChange = "modified"
+)
+
+func (v VersionChange) String() string {
+	return string(v)
+}
+
+// VersionEntity is the kind of entity of an entry in a version diff.
+type VersionEntity string
+
+const (
+	MENU                    VersionEntity = "menu"
+	MENU_ITEM               VersionEntity = "menuItem"
+	PAGE                    VersionEntity = "page"
+	PAGE_PARTIAL            VersionEntity = "pagePartial"
+	PAGE_PARTIAL_ROW        VersionEntity = "pagePartialRow"
+	PAGE_PARTIAL_ROW_COLUMN VersionEntity = "pagePartialRowColumn"
+	FOOTER                  VersionEntity = "footer"
+	FOOTER_ROW              VersionEntity = "footerRow"
+	FOOTER_ROW_COLUMN       VersionEntity = "footerRowColumn"
+)
+
+func (v VersionEntity) String() string {
+	return string(v)
+}
@@ -0,0 +1,89 @@
+package enums
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// WebhookEvent is the kind of change a webhook is notified of.
+type WebhookEvent string
+
+const (
+	WEBHOOK_VERSION_PUBLISHED   WebhookEvent = "version.published"
+	WEBHOOK_VERSION_UNPUBLISHED WebhookEvent = "version.unpublished"
+	WEBHOOK_VERSION_MERGED      WebhookEvent = "version.merged"
+	WEBHOOK_VERSION_IMPORTED    WebhookEvent = "version.imported"
+	WEBHOOK_PAGE_UPDATED        WebhookEvent = "page.updated"
+	WEBHOOK_PAGE_DELETED        WebhookEvent = "page.deleted"
+	WEBHOOK_MENU_UPDATED        WebhookEvent = "menu.updated"
+	WEBHOOK_MENU_DELETED        WebhookEvent = "menu.deleted"
+	WEBHOOK_FOOTER_UPDATED      WebhookEvent = "footer.updated"
+)
+
+func (w *WebhookEvent) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = ""
+		return nil
+	case string:
+		*w = WebhookEvent(v)
+		return nil
+	case []byte:
+		*w = WebhookEvent(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for WebhookEvent: %T", value)
+	}
+}
+
+func (w WebhookEvent) Value() (driver.Value, error) {
+	return string(w), nil
+}
+
+func (w WebhookEvent) String() string {
+	return string(w)
+}
+
+// IsValid checks if the webhook event is one that is emitted.
+func (w WebhookEvent) IsValid() bool {
+	switch w {
+	case WEBHOOK_VERSION_PUBLISHED, WEBHOOK_VERSION_UNPUBLISHED, WEBHOOK_VERSION_MERGED, WEBHOOK_VERSION_IMPORTED,
+		WEBHOOK_PAGE_UPDATED, WEBHOOK_PAGE_DELETED, WEBHOOK_MENU_UPDATED, WEBHOOK_MENU_DELETED, WEBHOOK_FOOTER_UPDATED:
+		return true
+	}
+
+	return false
+}
+
+// WebhookDeliveryStatus is the state of the delivery of an event to a webhook.
+type WebhookDeliveryStatus string
+
+const (
+	WEBHOOK_PENDING   WebhookDeliveryStatus = "pending"
+	WEBHOOK_SUCCEEDED WebhookDeliveryStatus = "succeeded"
+	WEBHOOK_FAILED    WebhookDeliveryStatus = "failed"
+)
+
+func (w *WebhookDeliveryStatus) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = ""
+		return nil
+	case string:
+		*w = WebhookDeliveryStatus(v)
+		return nil
+	case []byte:
+		*w = WebhookDeliveryStatus(string(v))
+		return nil
+	default:
+		return fmt.Errorf("unsupported Scan type for WebhookDeliveryStatus: %T", value)
+	}
+}
+
+func (w WebhookDeliveryStatus) Value() (driver.Value, error) {
+	return string(w), nil
+}
+
+func (w WebhookDeliveryStatus) String() string {
+	return string(w)
+}
@@ -2,21 +2,38 @@ package errors
 
 // Define error codes as constants.
 const (
-	AppNotFound          = "appNotFound"
-	VersionExists        = "versionExists"
-	VersionAvailable     = "versionAvailable"
-	VersionNotEnabled    = "versionNotEnabled"
-	VersionIsPublished   = "versionIsPublished"
-	VersionNotPublished  = "versionNotPublished"
-	MenuExists           = "menuExists"
-	MenuAvailable        = "menuAvailable"
-	MenuDepthInvalid     = "menuDepthInvalid"
-	PageExists           = "pageExists"
-	PageAvailable        = "pageAvailable"
-	PagePartialAvailable = "pagePartialAvailable"
-	LastPagePartial      = "lastPagePartial"
-	ModuleExists         = "moduleExists"
-	ModuleAvailable      = "moduleAvailable"
-	ModuleTypeNotFound   = "moduleTypeNotFound"
+	AppNotFound            = "appNotFound"
+	VersionExists          = "versionExists"
+	VersionAvailable       = "versionAvailable"
+	VersionNotEnabled      = "versionNotEnabled"
+	VersionIsPublished     = "versionIsPublished"
+	VersionNotPublished    = "versionNotPublished"
+	VersionScheduleExists  = "versionScheduleExists"
+	VersionRollbackExists  = "versionRollbackExists"
+	VersionMergeConflict   = "versionMergeConflict"
+	VersionMergeInvalid    = "versionMergeInvalid"
+	MenuExists             = "menuExists"
+	MenuAvailable          = "menuAvailable"
+	MenuDepthInvalid       = "menuDepthInvalid"
+	PageExists             = "pageExists"
+	PageAvailable          = "pageAvailable"
+	PageSlugAvailable      = "pageSlugAvailable"
+	PagePartialAvailable   = "pagePartialAvailable"
+	LastPagePartial        = "lastPagePartial"
+	PageRevisionExists     = "pageRevisionExists"
+	RedirectExists         = "redirectExists"
+	RedirectAvailable      = "redirectAvailable"
+	SitemapExists          = "sitemapExists"
+	ModuleExists           = "moduleExists"
+	ModuleAvailable        = "moduleAvailable"
+	ModuleInUse            = "moduleInUse"
+	ModuleTypeNotFound     = "moduleTypeNotFound"
+	ModuleTypeAvailable    = "moduleTypeAvailable"
+	ModuleTypeSchemaExists = "moduleTypeSchemaExists"
+	PluginTypeNotFound     = "pluginTypeNotFound"
+	PluginTypeAvailable    = "pluginTypeAvailable"
+	IfMatchRequired        = "ifMatchRequired"
+	WebhookExists          = "webhookExists"
+	CacheWarmUpExists      = "cacheWarmUpExists"
 	// Add more error codes as needed.
 )
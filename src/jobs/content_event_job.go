@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"api-page/main/src/services"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// contentEventRetryDelay is the delay before the cache subscription is opened again after it failed.
+const contentEventRetryDelay = 5 * time.Second
+
+// StartContentEventListener starts the in-process listener that passes the content events of every replica
+// on to the event streams of this replica. The listener stops when the context is cancelled.
+func StartContentEventListener(ctx context.Context) {
+	go func() {
+		for {
+			if err := services.ListenContentEvents(ctx); err != nil && ctx.Err() == nil {
+				log.Error("Content event listener failed: ", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(contentEventRetryDelay):
+			}
+		}
+	}()
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"api-page/main/src/services"
+	"context"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// defaultRedirectHitFlushInterval is used when REDIRECT_HIT_FLUSH_INTERVAL is not set or invalid.
+const defaultRedirectHitFlushInterval = 10 * time.Second
+
+// StartRedirectHitJob starts the in-process worker that writes the buffered hits of redirects
+// to the database. The buffered hits are written once more when the context is cancelled.
+func StartRedirectHitJob(ctx context.Context) {
+	interval, err := time.ParseDuration(os.Getenv("REDIRECT_HIT_FLUSH_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = defaultRedirectHitFlushInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if _, err := services.FlushRedirectHits(); err != nil {
+					log.Error("Redirect hit job failed: ", err)
+				}
+				return
+			case <-ticker.C:
+			}
+
+			if _, err := services.FlushRedirectHits(); err != nil {
+				log.Error("Redirect hit job failed: ", err)
+			}
+		}
+	}()
+}
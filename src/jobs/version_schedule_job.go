@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"api-page/main/src/services"
+	"context"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// defaultVersionScheduleInterval is used when VERSION_SCHEDULE_INTERVAL is not set or invalid.
+const defaultVersionScheduleInterval = 30 * time.Second
+
+// StartVersionScheduleJob starts the in-process scheduler that publishes and unpublishes versions
+// at their scheduled moment. The job stops when the context is cancelled.
+func StartVersionScheduleJob(ctx context.Context) {
+	interval, err := time.ParseDuration(os.Getenv("VERSION_SCHEDULE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = defaultVersionScheduleInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if processed, err := services.ProcessDueVersionSchedules(time.Now()); err != nil {
+				log.Error("Version schedule job failed: ", err)
+			} else if processed > 0 {
+				log.Info("Version schedule job processed schedules: ", processed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"api-page/main/src/services"
+	"context"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// defaultWebhookDeliveryInterval is used when WEBHOOK_DELIVERY_INTERVAL is not set or invalid.
+const defaultWebhookDeliveryInterval = 10 * time.Second
+
+// StartWebhookDeliveryJob starts the in-process worker that sends queued webhook deliveries
+// and retries the failed ones. The job stops when the context is cancelled.
+func StartWebhookDeliveryJob(ctx context.Context) {
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_DELIVERY_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = defaultWebhookDeliveryInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if processed, err := services.ProcessDueWebhookDeliveries(time.Now()); err != nil {
+				log.Error("Webhook delivery job failed: ", err)
+			} else if processed > 0 {
+				log.Info("Webhook delivery job processed deliveries: ", processed)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
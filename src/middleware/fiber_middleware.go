@@ -45,6 +45,8 @@ func FiberMiddleware(a *fiber.App) {
 				fiber.MethodOptions,
 			},
 			AllowHeaders: csvEnvList("CORS_ALLOW_HEADERS"),
+			// Let browsers read the revision of an editable resource.
+			ExposeHeaders: []string{fiber.HeaderETag},
 		}),
 
 		// Add simple logger.
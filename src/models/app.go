@@ -1,9 +1,42 @@
 package models
 
+import "gorm.io/datatypes"
+
+// DefaultLocaleFallback is the key of the locale fallbacks that applies to every locale.
+const DefaultLocaleFallback = "*"
+
 type App struct {
-	Name string `gorm:"primaryKey:true;autoIncrement:false"`
+	Name            string                                  `gorm:"primaryKey:true;autoIncrement:false"`
+	LocaleFallbacks datatypes.JSONType[map[string][]string] `gorm:"not null;default:'{}'"`
 
 	// Relationships.
 	ModuleTypes []ModuleType `gorm:"many2many:app_module_types;foreignKey:Name;joinForeignKey:AppName;references:Name;joinReferences:ModuleType;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	PluginTypes []PluginType `gorm:"many2many:app_plugin_types;foreignKey:Name;joinForeignKey:AppName;references:Name;joinReferences:PluginType;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
+
+// LocaleChain returns the locale followed by its fallbacks in the order they are tried.
+// Fallbacks of fallbacks are followed, e.g. nl-BE → nl → en, and the default fallbacks
+// under "*" are tried last. Every locale appears once.
+func (a *App) LocaleChain(locale string) []string {
+	fallbacks := a.LocaleFallbacks.Data()
+	chain := []string{locale}
+	seen := map[string]bool{locale: true}
+
+	for i := 0; i < len(chain); i++ {
+		for _, fallback := range fallbacks[chain[i]] {
+			if !seen[fallback] {
+				seen[fallback] = true
+				chain = append(chain, fallback)
+			}
+		}
+	}
+
+	for _, fallback := range fallbacks[DefaultLocaleFallback] {
+		if !seen[fallback] {
+			seen[fallback] = true
+			chain = append(chain, fallback)
+		}
+	}
+
+	return chain
+}
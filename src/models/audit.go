@@ -0,0 +1,19 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type Audit struct {
+	ID        uint              `gorm:"primarykey"`
+	Actor     string            `gorm:"not null;index"`
+	Entity    enums.AuditEntity `gorm:"not null;size:32;index:idx_audit_entity"`
+	EntityID  string            `gorm:"not null;index:idx_audit_entity"`
+	Action    enums.AuditAction `gorm:"not null;size:32"`
+	Before    datatypes.JSON
+	After     datatypes.JSON
+	CreatedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Footer struct {
+	VersionID uint   `gorm:"primaryKey:true;autoIncrement:false"`
+	Locale    string `gorm:"primaryKey:true;autoIncrement:false;size:32"`
+	Revision  uint64 `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+
+	// Relationships.
+	Version Version `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:VersionID;references:ID"`
+}
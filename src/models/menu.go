@@ -11,6 +11,7 @@ type Menu struct {
 	VersionID uint   `gorm:"not null;index:idx_name,unique"`
 	Name      string `gorm:"not null;index:idx_name,unique"`
 	Depth     sql.Null[uint8]
+	Revision  uint64 `gorm:"not null;default:1"`
 
 	// Relationships.
 	Version           Version            `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:VersionID;references:ID"`
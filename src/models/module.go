@@ -7,10 +7,12 @@ import (
 
 type Module struct {
 	gorm.Model
-	AppName  string         `gorm:"not null;index:idx_module_name,unique"`
-	Type     string         `gorm:"not null"`
-	Name     string         `gorm:"not null;index:idx_module_name,unique"`
-	Settings datatypes.JSON `gorm:"not null"`
+	AppName         string         `gorm:"not null;index:idx_module_name,unique"`
+	Type            string         `gorm:"not null"`
+	Name            string         `gorm:"not null;index:idx_module_name,unique"`
+	Settings        datatypes.JSON `gorm:"not null"`
+	SettingsVersion uint           `gorm:"not null;default:0"`
+	Revision        uint64         `gorm:"not null;default:1"`
 
 	// Relationships.
 	App        App        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:AppName;references:Name"`
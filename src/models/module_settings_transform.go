@@ -0,0 +1,14 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"encoding/json"
+)
+
+type ModuleSettingsTransform struct {
+	Op    enums.ModuleSettingsTransformOp `json:"op"`
+	From  string                          `json:"from,omitempty"`
+	To    string                          `json:"to,omitempty"`
+	Path  string                          `json:"path,omitempty"`
+	Value json.RawMessage                 `json:"value,omitempty"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type ModuleSnapshot struct {
+	VersionID       uint           `gorm:"primaryKey:true;autoIncrement:false"`
+	ModuleID        uint           `gorm:"primaryKey:true;autoIncrement:false"`
+	Type            string         `gorm:"not null"`
+	Name            string         `gorm:"not null"`
+	Settings        datatypes.JSON `gorm:"not null"`
+	SettingsVersion uint           `gorm:"not null;default:0"`
+	Revision        uint64         `gorm:"not null"`
+	CreatedAt       time.Time
+
+	// Relationships.
+	Version Version `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:VersionID;references:ID"`
+	Module  Module  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:ModuleID;references:ID"`
+}
+
+// ToModule converts the snapshot to the module it was taken from, as it was at the moment of the snapshot.
+func (s *ModuleSnapshot) ToModule() *Module {
+	module := &Module{
+		Type:            s.Type,
+		Name:            s.Name,
+		Settings:        s.Settings,
+		SettingsVersion: s.SettingsVersion,
+		Revision:        s.Revision,
+	}
+	module.ID = s.ModuleID
+	module.CreatedAt = s.CreatedAt
+	module.UpdatedAt = s.CreatedAt
+
+	return module
+}
@@ -1,8 +1,22 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"api-page/main/src/enums"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ModuleType struct {
-	Name      string         `gorm:"primaryKey:true;autoIncrement:false"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Name            string `gorm:"primaryKey:true;autoIncrement:false"`
+	DisplayName     string `gorm:"not null;default:''"`
+	Description     sql.NullString
+	Icon            sql.NullString
+	AllowedInFooter bool                 `gorm:"not null;default:true"`
+	AllowedInPage   bool                 `gorm:"not null;default:true"`
+	Behavior        enums.ModuleBehavior `gorm:"not null;size:32;default:live"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 }
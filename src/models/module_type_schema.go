@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type ModuleTypeSchema struct {
+	ModuleTypeName string                                        `gorm:"primaryKey:true;autoIncrement:false"`
+	Version        uint                                          `gorm:"primaryKey:true;autoIncrement:false"`
+	Schema         datatypes.JSON                                `gorm:"not null"`
+	Migration      datatypes.JSONType[[]ModuleSettingsTransform] `gorm:"not null;default:'[]'"`
+	CreatedAt      time.Time
+
+	// Relationships.
+	ModuleType ModuleType `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:ModuleTypeName;references:Name"`
+}
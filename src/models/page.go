@@ -4,14 +4,17 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/ArnoldPMolenaar/api-utils/utils"
 	"gorm.io/gorm"
 )
 
 type Page struct {
 	MenuItemID      uint   `gorm:"primaryKey:true;autoIncrement:false"`
-	Locale          string `gorm:"primaryKey:true;autoIncrement:false;size:32"`
+	Locale          string `gorm:"primaryKey:true;autoIncrement:false;size:32;index:idx_page_slug,priority:2"`
+	VersionID       uint   `gorm:"not null;default:0;index:idx_page_slug,unique,priority:1,where:slug IS NOT NULL AND deleted_at IS NULL"`
 	Plugin          sql.NullString
-	Name            string `gorm:"not null"`
+	Name            string         `gorm:"not null"`
+	Slug            sql.NullString `gorm:"index:idx_page_slug,priority:3"`
 	MetaTitle       sql.NullString
 	MetaDescription sql.NullString
 	Hashtag         sql.NullString
@@ -19,6 +22,7 @@ type Page struct {
 	UrlEnabled      bool `gorm:"not null;default:false"`
 	Url             sql.NullString
 	EnabledAt       sql.NullTime
+	Revision        uint64 `gorm:"not null;default:1"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	DeletedAt       gorm.DeletedAt `gorm:"index"`
@@ -29,3 +33,25 @@ type Page struct {
 	Indexing   []PageIndexing `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:MenuItemID,Locale;references:MenuItemID,Locale"`
 	Partials   []PagePartial  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:MenuItemID,Locale;references:MenuItemID,Locale"`
 }
+
+// BeforeCreate sets the VersionID of the MenuItem, which keeps the slugs unique within a version and locale.
+func (p *Page) BeforeCreate(tx *gorm.DB) error {
+	if p.VersionID == 0 {
+		return tx.Session(&gorm.Session{NewDB: true}).
+			Unscoped().
+			Model(&MenuItem{}).
+			Select("version_id").
+			Where("id = ?", p.MenuItemID).
+			Scan(&p.VersionID).Error
+	}
+	return nil
+}
+
+// URLName returns the path segment of the page: its slug, or the URL encoded name when no slug is set.
+func (p *Page) URLName() string {
+	if p.Slug.Valid && p.Slug.String != "" {
+		return p.Slug.String
+	}
+
+	return utils.URLEncode(p.Name)
+}
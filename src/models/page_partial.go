@@ -7,6 +7,7 @@ type PagePartial struct {
 	MenuItemID uint   `gorm:"not null;index:idx_name,unique"`
 	Locale     string `gorm:"not null;size:32;index:idx_name,unique"`
 	Name       string `gorm:"not null;index:idx_name,unique"`
+	Revision   uint64 `gorm:"not null;default:1"`
 
 	// Relationships.
 	MenuItem MenuItem         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:MenuItemID;references:ID"`
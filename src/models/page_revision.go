@@ -0,0 +1,21 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type PageRevision struct {
+	ID         uint              `gorm:"primarykey"`
+	MenuItemID uint              `gorm:"not null;index:idx_page_revision"`
+	Locale     string            `gorm:"not null;size:32;index:idx_page_revision"`
+	Actor      string            `gorm:"not null"`
+	Action     enums.AuditAction `gorm:"not null;size:32;default:update"`
+	Content    datatypes.JSON    `gorm:"not null"`
+	CreatedAt  time.Time
+
+	// Relationships.
+	MenuItem MenuItem `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:MenuItemID;references:ID"`
+}
@@ -1,5 +1,18 @@
 package models
 
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type PluginType struct {
-	Name string `gorm:"primaryKey:true;autoIncrement:false"`
+	Name        string `gorm:"primaryKey:true;autoIncrement:false"`
+	DisplayName string `gorm:"not null;default:''"`
+	Description sql.NullString
+	Icon        sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+type Redirect struct {
+	gorm.Model
+	AppName    string `gorm:"not null;index:idx_redirect_from,unique"`
+	Locale     string `gorm:"not null;size:32;index:idx_redirect_from,unique"`
+	FromPath   string `gorm:"not null;index:idx_redirect_from,unique"`
+	ToPath     string `gorm:"not null"`
+	StatusCode uint16 `gorm:"not null;default:301"`
+	Automatic  bool   `gorm:"not null;default:false"`
+	Hits       uint64 `gorm:"not null;default:0"`
+	LastHitAt  sql.NullTime
+
+	// Relationships.
+	App App `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:AppName;references:Name"`
+}
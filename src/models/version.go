@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
@@ -10,11 +11,14 @@ import (
 
 type Version struct {
 	gorm.Model
-	EnabledAt   sql.NullTime
-	PublishedAt sql.NullTime
-	PublishID   datatypes.UUID `gorm:"not null;uniqueIndex"`
-	AppName     string         `gorm:"not null;index:idx_name,unique"`
-	Name        string         `gorm:"not null;index:idx_name,unique"`
+	EnabledAt        sql.NullTime
+	PublishedAt      sql.NullTime
+	DuplicatedAt     sql.NullTime
+	PublishID        datatypes.UUID `gorm:"not null;uniqueIndex"`
+	AppName          string         `gorm:"not null;index:idx_name,unique"`
+	Name             string         `gorm:"not null;index:idx_name,unique"`
+	Revision         uint64         `gorm:"not null;default:1"`
+	ContentChangedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
 	// Relationships.
 	App        App         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:AppName;references:Name"`
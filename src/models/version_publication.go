@@ -0,0 +1,22 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"database/sql"
+	"time"
+)
+
+type VersionPublication struct {
+	ID                uint                    `gorm:"primarykey"`
+	AppName           string                  `gorm:"not null;index"`
+	VersionID         uint                    `gorm:"not null;index"`
+	Action            enums.PublicationAction `gorm:"not null;size:32"`
+	PreviousVersionID sql.Null[uint]
+	Actor             string    `gorm:"not null"`
+	CreatedAt         time.Time `gorm:"index"`
+
+	// Relationships.
+	App             App      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:AppName;references:Name"`
+	Version         Version  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:VersionID;references:ID"`
+	PreviousVersion *Version `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;foreignKey:PreviousVersionID;references:ID"`
+}
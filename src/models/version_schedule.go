@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type VersionSchedule struct {
+	VersionID         uint         `gorm:"primaryKey:true;autoIncrement:false"`
+	AppName           string       `gorm:"not null;index"`
+	PublishAt         time.Time    `gorm:"not null;index"`
+	UnpublishAt       sql.NullTime `gorm:"index"`
+	FallbackVersionID sql.Null[uint]
+	PublishedAt       sql.NullTime
+	UnpublishedAt     sql.NullTime
+	Attempts          uint `gorm:"not null;default:0"`
+	LastError         sql.NullString
+	RetryAt           sql.NullTime `gorm:"index"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+
+	// Relationships.
+	Version         Version  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:VersionID;references:ID"`
+	FallbackVersion *Version `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;foreignKey:FallbackVersionID;references:ID"`
+}
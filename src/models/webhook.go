@@ -0,0 +1,32 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"database/sql"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type Webhook struct {
+	gorm.Model
+	AppName   string                                   `gorm:"not null;index"`
+	Url       string                                   `gorm:"not null"`
+	Secret    string                                   `gorm:"not null"`
+	Events    datatypes.JSONType[[]enums.WebhookEvent] `gorm:"not null;default:'[]'"`
+	EnabledAt sql.NullTime
+
+	// Relationships.
+	App App `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:AppName;references:Name"`
+}
+
+// IsSubscribed checks if the webhook is notified of the event.
+func (w *Webhook) IsSubscribed(event enums.WebhookEvent) bool {
+	for _, e := range w.Events.Data() {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
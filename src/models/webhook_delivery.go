@@ -0,0 +1,27 @@
+package models
+
+import (
+	"api-page/main/src/enums"
+	"database/sql"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type WebhookDelivery struct {
+	ID             uint                        `gorm:"primarykey"`
+	WebhookID      uint                        `gorm:"not null;index"`
+	Event          enums.WebhookEvent          `gorm:"not null;size:32"`
+	Payload        datatypes.JSON              `gorm:"not null"`
+	Status         enums.WebhookDeliveryStatus `gorm:"not null;size:32;index:idx_webhook_delivery_due"`
+	Attempts       uint                        `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time                   `gorm:"not null;index:idx_webhook_delivery_due"`
+	LastAttemptAt  sql.NullTime
+	ResponseStatus sql.Null[int]
+	Error          sql.NullString
+	CreatedAt      time.Time `gorm:"index"`
+	UpdatedAt      time.Time
+
+	// Relationships.
+	Webhook Webhook `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:WebhookID;references:ID"`
+}
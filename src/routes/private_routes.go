@@ -17,6 +17,8 @@ func PrivateRoutes(a *fiber.App) {
 	apps.Post("/", middleware.MachineProtected(), controllers.CreateApp)
 	apps.Patch("/modules/types", middleware.MachineProtected(), controllers.SetAppModuleTypes)
 	apps.Patch("/plugins/types", middleware.MachineProtected(), controllers.SetAppPluginTypes)
+	apps.Get("/locales/fallbacks", middleware.MachineProtected(), controllers.GetAppLocaleFallbacks)
+	apps.Patch("/locales/fallbacks", middleware.MachineProtected(), controllers.SetAppLocaleFallbacks)
 
 	// Register route group for /v1/versions.
 	versions := route.Group("/versions")
@@ -24,14 +26,26 @@ func PrivateRoutes(a *fiber.App) {
 	versions.Post("/", middleware.MachineProtected(), controllers.CreateVersion)
 	versions.Get("/lookup", middleware.MachineProtected(), controllers.GetVersionLookup)
 	versions.Get("/name/available", middleware.MachineProtected(), controllers.IsVersionNameAvailable)
+	versions.Get("/publications", middleware.MachineProtected(), controllers.GetVersionPublications)
+	versions.Post("/rollback", middleware.MachineProtected(), controllers.RollbackVersion)
+	versions.Post("/import", middleware.MachineProtected(), controllers.ImportVersion)
 	versions.Get("/:id", middleware.MachineProtected(), controllers.GetVersionByID)
 	versions.Get("/:id/footer", middleware.MachineProtected(), controllers.GetFooterByVersionID)
+	versions.Get("/:id/diff/:otherId", middleware.MachineProtected(), controllers.GetVersionDiff)
+	versions.Get("/:id/export", middleware.MachineProtected(), controllers.ExportVersion)
 	versions.Patch("/:id", middleware.MachineProtected(), controllers.UpdateVersion)
 	versions.Put("/:id/duplicate", middleware.MachineProtected(), controllers.DuplicateVersion)
 	versions.Patch("/:id/footer", middleware.MachineProtected(), controllers.UpdateFooter)
 	versions.Delete("/:id", middleware.MachineProtected(), controllers.DeleteVersion)
 	versions.Patch("/:id/publish", middleware.MachineProtected(), controllers.PublishVersion)
 	versions.Post("/:id/restore", middleware.MachineProtected(), controllers.RestoreVersion)
+	versions.Post("/:id/preview", middleware.MachineProtected(), controllers.CreatePreviewToken)
+	versions.Post("/:id/merge", middleware.MachineProtected(), controllers.MergeVersion)
+	versions.Get("/:id/schedule", middleware.MachineProtected(), controllers.GetVersionSchedule)
+	versions.Put("/:id/schedule", middleware.MachineProtected(), controllers.SetVersionSchedule)
+	versions.Delete("/:id/schedule", middleware.MachineProtected(), controllers.DeleteVersionSchedule)
+	versions.Get("/:id/cache/warm", middleware.MachineProtected(), controllers.GetVersionCacheWarmUp)
+	versions.Post("/:id/cache/warm", middleware.MachineProtected(), controllers.WarmVersionCache)
 
 	// Register route group for /v1/menus.
 	menus := route.Group("/menus")
@@ -59,20 +73,68 @@ func PrivateRoutes(a *fiber.App) {
 	pages.Patch("/:menuItemId/:locale/partials/:id", middleware.MachineProtected(), controllers.UpdatePagePartial)
 	pages.Delete("/:menuItemId/:locale/partials/:id", middleware.MachineProtected(), controllers.DeletePagePartial)
 	pages.Post("/:menuItemId/:locale/partials/:id/restore", middleware.MachineProtected(), controllers.RestorePagePartial)
+	pages.Get("/:menuItemId/:locale/revisions", middleware.MachineProtected(), controllers.GetPageRevisions)
+	pages.Get("/:menuItemId/:locale/revisions/:id", middleware.MachineProtected(), controllers.GetPageRevisionByID)
+	pages.Get("/:menuItemId/:locale/revisions/:id/diff/:otherId", middleware.MachineProtected(), controllers.GetPageRevisionDiff)
+	pages.Post("/:menuItemId/:locale/revisions/:id/restore", middleware.MachineProtected(), controllers.RestorePageRevision)
+
+	// Register route group for /v1/redirects.
+	redirects := route.Group("/redirects")
+	redirects.Get("/", middleware.MachineProtected(), controllers.GetRedirects)
+	redirects.Post("/", middleware.MachineProtected(), controllers.CreateRedirect)
+	redirects.Get("/:id", middleware.MachineProtected(), controllers.GetRedirectByID)
+	redirects.Patch("/:id", middleware.MachineProtected(), controllers.UpdateRedirect)
+	redirects.Delete("/:id", middleware.MachineProtected(), controllers.DeleteRedirect)
+	redirects.Post("/:id/restore", middleware.MachineProtected(), controllers.RestoreRedirect)
 
 	// Register route group for /v1/modules.
 	modules := route.Group("/modules")
 	modules.Get("/", middleware.MachineProtected(), controllers.GetModules)
 	modules.Post("/", middleware.MachineProtected(), controllers.CreateModule)
 	modules.Get("/lookup", middleware.MachineProtected(), controllers.GetModuleLookup)
+	modules.Get("/types", middleware.MachineProtected(), controllers.GetModuleTypes)
+	modules.Post("/types", middleware.MachineProtected(), controllers.CreateModuleType)
 	modules.Get("/types/lookup", middleware.MachineProtected(), controllers.GetModuleTypeLookup)
+	modules.Get("/types/:name", middleware.MachineProtected(), controllers.GetModuleType)
+	modules.Patch("/types/:name", middleware.MachineProtected(), controllers.UpdateModuleType)
+	modules.Delete("/types/:name", middleware.MachineProtected(), controllers.DeleteModuleType)
+	modules.Post("/types/:name/restore", middleware.MachineProtected(), controllers.RestoreModuleType)
+	modules.Put("/types/:name/behavior", middleware.MachineProtected(), controllers.SetModuleTypeBehavior)
+	modules.Get("/types/:name/schema", middleware.MachineProtected(), controllers.GetModuleTypeSchema)
+	modules.Put("/types/:name/schema", middleware.MachineProtected(), controllers.SetModuleTypeSchema)
+	modules.Post("/types/:name/migrate", middleware.MachineProtected(), controllers.MigrateModuleSettings)
 	modules.Get("/name/available", middleware.MachineProtected(), controllers.IsModuleNameAvailable)
 	modules.Get("/:id", middleware.MachineProtected(), controllers.GetModuleByID)
+	modules.Get("/:id/usages", middleware.MachineProtected(), controllers.GetModuleUsages)
 	modules.Patch("/:id", middleware.MachineProtected(), controllers.UpdateModule)
 	modules.Delete("/:id", middleware.MachineProtected(), controllers.DeleteModule)
 	modules.Post("/:id/restore", middleware.MachineProtected(), controllers.RestoreModule)
 
 	// Register route group for /v1/plugins.
 	plugins := route.Group("/plugins")
+	plugins.Get("/types", middleware.MachineProtected(), controllers.GetPluginTypes)
+	plugins.Post("/types", middleware.MachineProtected(), controllers.CreatePluginType)
 	plugins.Get("/types/lookup", middleware.MachineProtected(), controllers.GetPluginTypeLookup)
+	plugins.Get("/types/:name", middleware.MachineProtected(), controllers.GetPluginType)
+	plugins.Patch("/types/:name", middleware.MachineProtected(), controllers.UpdatePluginType)
+	plugins.Delete("/types/:name", middleware.MachineProtected(), controllers.DeletePluginType)
+	plugins.Post("/types/:name/restore", middleware.MachineProtected(), controllers.RestorePluginType)
+
+	// Register route group for /v1/webhooks.
+	webhooks := route.Group("/webhooks")
+	webhooks.Get("/", middleware.MachineProtected(), controllers.GetWebhooks)
+	webhooks.Post("/", middleware.MachineProtected(), controllers.CreateWebhook)
+	webhooks.Get("/:id", middleware.MachineProtected(), controllers.GetWebhookByID)
+	webhooks.Get("/:id/deliveries", middleware.MachineProtected(), controllers.GetWebhookDeliveries)
+	webhooks.Patch("/:id", middleware.MachineProtected(), controllers.UpdateWebhook)
+	webhooks.Delete("/:id", middleware.MachineProtected(), controllers.DeleteWebhook)
+	webhooks.Post("/:id/restore", middleware.MachineProtected(), controllers.RestoreWebhook)
+
+	// Register route group for /v1/events.
+	events := route.Group("/events")
+	events.Get("/", middleware.MachineProtected(), controllers.GetContentEvents)
+
+	// Register route group for /v1/audit.
+	audit := route.Group("/audit")
+	audit.Get("/", middleware.MachineProtected(), controllers.GetAudits)
 }
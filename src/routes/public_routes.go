@@ -11,6 +11,10 @@ func PublicRoutes(a *fiber.App) {
 	// Create private routes group.
 	route := a.Group("/v1")
 
+	// Register sitemap routes.
+	route.Get("/sitemap.xml", controllers.GetSitemap)
+	route.Get("/sitemap-index.xml", controllers.GetSitemapIndex)
+
 	// Register route group for /v1/versions.
 	versions := route.Group("/versions")
 	versions.Get("/published", controllers.GetPublishedVersionByAppName)
@@ -19,5 +23,10 @@ func PublicRoutes(a *fiber.App) {
 
 	// Register route group for v1/pages
 	pages := route.Group("/pages")
+	pages.Get("/published", controllers.GetPublishedPageByPath)
 	pages.Get("/:menuItemId/:locale/published", controllers.GetPublishedPageByID)
+
+	// Register route group for v1/redirects
+	redirects := route.Group("/redirects")
+	redirects.Get("/published", controllers.GetPublishedRedirect)
 }
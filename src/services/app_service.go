@@ -1,15 +1,20 @@
 package services
 
 import (
+	"api-page/main/src/cache"
 	"api-page/main/src/database"
 	"api-page/main/src/dto/responses"
 	"api-page/main/src/models"
 	"fmt"
 	"strings"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+// localeChainSeparator joins the locales of a fallback chain in cache keys, e.g. "nl-BE>nl>en".
+const localeChainSeparator = ">"
+
 // IsAppAvailable checks whether an app with the given name exists.
 // It returns true when a matching row is found.
 func IsAppAvailable(app string) (bool, error) {
@@ -54,6 +59,86 @@ func GetAppPluginTypes(app string) ([]models.PluginType, error) {
 	return a.PluginTypes, nil
 }
 
+// GetAppLocaleFallbacks returns the locale fallbacks of an app, keyed by locale.
+func GetAppLocaleFallbacks(appName string) (map[string][]string, error) {
+	if inCache, err := isAppLocaleFallbacksInCache(appName); err != nil {
+		return nil, err
+	} else if inCache {
+		if cacheFallbacks, err := getAppLocaleFallbacksFromCache(appName); err != nil {
+			return nil, err
+		} else if cacheFallbacks != nil {
+			return cacheFallbacks, nil
+		}
+	}
+
+	app := &models.App{}
+	if result := database.Pg.Select("name", "locale_fallbacks").Limit(1).Find(app, "name = ?", appName); result.Error != nil {
+		return nil, result.Error
+	}
+
+	fallbacks := app.LocaleFallbacks.Data()
+	if fallbacks == nil {
+		fallbacks = map[string][]string{}
+	}
+
+	_ = setAppLocaleFallbacksToCache(appName, fallbacks)
+
+	return fallbacks, nil
+}
+
+// SetAppLocaleFallbacks replaces the locale fallbacks of an app.
+// Chains already cached stay valid, because every cache key holds its resolved chain.
+func SetAppLocaleFallbacks(appName string, fallbacks map[string][]string) (map[string][]string, error) {
+	normalized := make(map[string][]string, len(fallbacks))
+	for locale, localeFallbacks := range fallbacks {
+		normalized[strings.TrimSpace(locale)] = dedupeStrings(localeFallbacks)
+	}
+
+	if result := database.Pg.Model(&models.App{}).
+		Where("name = ?", appName).
+		Update("locale_fallbacks", datatypes.NewJSONType(normalized)); result.Error != nil {
+		return nil, result.Error
+	}
+
+	_ = deleteAppLocaleFallbacksFromCache(appName)
+
+	return normalized, nil
+}
+
+// GetLocaleChain returns the locale followed by its fallbacks for an app.
+func GetLocaleChain(appName, locale string) ([]string, error) {
+	fallbacks, err := GetAppLocaleFallbacks(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &models.App{Name: appName, LocaleFallbacks: datatypes.NewJSONType(fallbacks)}
+
+	return app.LocaleChain(locale), nil
+}
+
+// GetLocaleChainByVersionID returns the locale followed by its fallbacks for the app of a version.
+func GetLocaleChainByVersionID(versionID uint, locale string) ([]string, error) {
+	var appName string
+	if result := database.Pg.Model(&models.Version{}).Where("id = ?", versionID).Pluck("app_name", &appName); result.Error != nil {
+		return nil, result.Error
+	} else if appName == "" {
+		return []string{locale}, nil
+	}
+
+	return GetLocaleChain(appName, locale)
+}
+
+// GetLocaleChainByMenuItemID returns the locale followed by its fallbacks for the app of a menu item.
+func GetLocaleChainByMenuItemID(menuItemID uint, locale string) ([]string, error) {
+	versionID, err := GetVersionIDByMenuItemID(menuItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetLocaleChainByVersionID(versionID, locale)
+}
+
 // CreateApp creates the app when it does not exist yet.
 // If the app already exists, the existing row is reused.
 func CreateApp(name string) (*models.App, error) {
@@ -265,3 +350,64 @@ func SetAppPluginTypes(app string, pluginTypes []string) (*responses.AppTypes, e
 
 	return response, nil
 }
+
+// getAppLocaleFallbacksCacheKey gets the key for the cache.
+func getAppLocaleFallbacksCacheKey(appName string) string {
+	return fmt.Sprintf("apps:locales:fallbacks:%s", appName)
+}
+
+// isAppLocaleFallbacksInCache checks if the locale fallbacks of an app exists in the cache.
+func isAppLocaleFallbacksInCache(appName string) (bool, error) {
+	return cache.Exists(getAppLocaleFallbacksCacheKey(appName))
+}
+
+// getAppLocaleFallbacksFromCache gets the locale fallbacks of an app from the cache.
+func getAppLocaleFallbacksFromCache(appName string) (map[string][]string, error) {
+	fallbacks, err := cache.Get[map[string][]string](getAppLocaleFallbacksCacheKey(appName))
+	if err != nil {
+		return nil, err
+	}
+
+	return *fallbacks, nil
+}
+
+// setAppLocaleFallbacksToCache sets the locale fallbacks of an app to the cache.
+func setAppLocaleFallbacksToCache(appName string, fallbacks map[string][]string) error {
+	return cache.Set(getAppLocaleFallbacksCacheKey(appName), fallbacks)
+}
+
+// deleteAppLocaleFallbacksFromCache deletes the locale fallbacks of an app from the cache.
+func deleteAppLocaleFallbacksFromCache(appName string) error {
+	return cache.Delete(getAppLocaleFallbacksCacheKey(appName))
+}
+
+// getLocaleChainsCacheKey gets the key of the set that holds the keys starting with the prefix
+// whose locale chain contains the locale.
+func getLocaleChainsCacheKey(prefix, locale string) string {
+	return prefix + "chains:" + locale
+}
+
+// setLocaleChainKeyToCache adds the key of the prefix and the locale chain to the set of every locale of the chain,
+// so deleteLocaleChainKeysFromCache finds it without scanning the keys of the cache.
+func setLocaleChainKeyToCache(prefix string, locales []string) error {
+	key := prefix + strings.Join(locales, localeChainSeparator)
+	for _, locale := range locales {
+		if err := cache.AddMembers(getLocaleChainsCacheKey(prefix, locale), key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteLocaleChainKeysFromCache deletes every key starting with the prefix whose locale chain contains the locale.
+func deleteLocaleChainKeysFromCache(prefix, locale string) error {
+	chainsKey := getLocaleChainsCacheKey(prefix, locale)
+
+	keys, err := cache.Members(chainsKey)
+	if err != nil {
+		return err
+	}
+
+	return cache.Delete(append(keys, chainsKey)...)
+}
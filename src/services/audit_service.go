@@ -0,0 +1,105 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"encoding/json"
+	"strconv"
+
+	"github.com/ArnoldPMolenaar/api-utils/pagination"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/datatypes"
+)
+
+// GetAudits method to get paginated audits.
+func GetAudits(c fiber.Ctx) (*pagination.Model, error) {
+	audits := make([]models.Audit, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"id":         true,
+		"actor":      true,
+		"entity":     true,
+		"entity_id":  true,
+		"action":     true,
+		"created_at": true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Limit(limit).
+		Offset(offset)
+
+	// Without an explicit sort the newest audits come first.
+	if len(values.Peek("sortBy")) == 0 {
+		dbResult = dbResult.Order("id DESC")
+	}
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.Audit{})
+
+	if result := dbResult.Find(&audits); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedAudits := make([]responses.PaginatedAudit, 0)
+	for i := range audits {
+		paginatedAudit := responses.PaginatedAudit{}
+		paginatedAudit.SetPaginatedAudit(&audits[i])
+		paginatedAudits = append(paginatedAudits, paginatedAudit)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedAudits)
+
+	return &paginationModel, nil
+}
+
+// CreateAudit method to record an action of an actor on an entity.
+// The before and after states are stored as JSON and left empty when nil.
+func CreateAudit(actor string, entity enums.AuditEntity, entityID string, action enums.AuditAction, before, after any) error {
+	audit := &models.Audit{
+		Actor:    actor,
+		Entity:   entity,
+		EntityID: entityID,
+		Action:   action,
+	}
+
+	var err error
+	if audit.Before, err = toAuditState(before); err != nil {
+		return err
+	}
+	if audit.After, err = toAuditState(after); err != nil {
+		return err
+	}
+
+	return database.Pg.Create(audit).Error
+}
+
+// toAuditState converts a state of an entity to JSON.
+func toAuditState(state any) (datatypes.JSON, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCacheWarmUpConcurrency is used when CACHE_WARM_UP_CONCURRENCY is not set or invalid.
+	defaultCacheWarmUpConcurrency = 4
+	// maxCacheWarmUpErrors is the number of errors kept in the status of a warm-up.
+	maxCacheWarmUpErrors = 50
+)
+
+// runningCacheWarmUps holds the versions that this replica is warming up.
+var runningCacheWarmUps = struct {
+	sync.Mutex
+	versionIDs map[uint]struct{}
+}{versionIDs: make(map[uint]struct{})}
+
+// cacheWarmUpTask fills the cache keys of one menu list, footer or page.
+type cacheWarmUpTask struct {
+	name string
+	warm func() error
+}
+
+// GetVersionCacheWarmUp method to get the status of the last cache warm-up of a version.
+// A version that was not warmed up recently has an empty status.
+func GetVersionCacheWarmUp(versionID uint) (*responses.CacheWarmUp, error) {
+	status, err := getCacheWarmUpFromCache(versionID)
+	if errors.Is(err, cache.ErrMiss) {
+		return &responses.CacheWarmUp{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// StartVersionCacheWarmUp method to fill the cache of the published menus, footers and pages of a version
+// in the background. The progress is kept in the cache, so every replica can report it.
+// When this replica is already warming up the version, the status of that warm-up is returned.
+func StartVersionCacheWarmUp(versionID uint) (*responses.CacheWarmUp, error) {
+	runningCacheWarmUps.Lock()
+	if _, ok := runningCacheWarmUps.versionIDs[versionID]; ok {
+		runningCacheWarmUps.Unlock()
+		return GetVersionCacheWarmUp(versionID)
+	}
+	runningCacheWarmUps.versionIDs[versionID] = struct{}{}
+	runningCacheWarmUps.Unlock()
+
+	tasks, err := getVersionCacheWarmUpTasks(versionID)
+	if err != nil {
+		finishVersionCacheWarmUp(versionID)
+		return nil, err
+	}
+
+	status := &responses.CacheWarmUp{
+		VersionID: versionID,
+		Status:    enums.CACHE_WARM_UP_RUNNING.String(),
+		Total:     len(tasks),
+		Errors:    make([]string, 0),
+		StartedAt: time.Now(),
+	}
+	if err := setCacheWarmUpToCache(versionID, status); err != nil {
+		finishVersionCacheWarmUp(versionID)
+		return nil, err
+	}
+
+	response := *status
+	go runVersionCacheWarmUp(status, tasks)
+
+	return &response, nil
+}
+
+// startPublishedVersionCacheWarmUp starts the cache warm-up of the version that is published for the app, if any.
+func startPublishedVersionCacheWarmUp(appName string) {
+	version, err := GetPublishedVersionByAppName(appName)
+	if err != nil || version.ID == 0 {
+		return
+	}
+
+	_, _ = StartVersionCacheWarmUp(version.ID)
+}
+
+// runVersionCacheWarmUp runs the tasks of a warm-up with bounded concurrency and stores the progress after every task.
+func runVersionCacheWarmUp(status *responses.CacheWarmUp, tasks []cacheWarmUpTask) {
+	defer finishVersionCacheWarmUp(status.VersionID)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, getCacheWarmUpConcurrency())
+
+	for _, task := range tasks {
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			err := task.warm()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				status.Failed++
+				if len(status.Errors) < maxCacheWarmUpErrors {
+					status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", task.name, err.Error()))
+				}
+			} else {
+				status.Warmed++
+			}
+
+			_ = setCacheWarmUpToCache(status.VersionID, status)
+		}()
+	}
+
+	wg.Wait()
+
+	finishedAt := time.Now()
+	status.FinishedAt = &finishedAt
+	status.Status = enums.CACHE_WARM_UP_COMPLETED.String()
+	if status.Failed > 0 {
+		status.Status = enums.CACHE_WARM_UP_FAILED.String()
+	}
+
+	_ = setCacheWarmUpToCache(status.VersionID, status)
+}
+
+// finishVersionCacheWarmUp allows this replica to warm up the version again.
+func finishVersionCacheWarmUp(versionID uint) {
+	runningCacheWarmUps.Lock()
+	delete(runningCacheWarmUps.versionIDs, versionID)
+	runningCacheWarmUps.Unlock()
+}
+
+// getVersionCacheWarmUpTasks builds a task for the menus and footer of every locale of the version,
+// and for the page of every enabled menu item in every locale. The tasks use the same locale chains
+// as the published endpoints, so they fill the keys those endpoints read.
+func getVersionCacheWarmUpTasks(versionID uint) ([]cacheWarmUpTask, error) {
+	var pageLocales []string
+	if result := database.Pg.Model(&models.Page{}).
+		Distinct("pages.locale").
+		Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+		Where("menu_items.version_id = ? AND pages.enabled_at IS NOT NULL", versionID).
+		Pluck("pages.locale", &pageLocales); result.Error != nil {
+		return nil, result.Error
+	}
+
+	var footerLocales []string
+	if result := database.Pg.Model(&models.FooterRow{}).Distinct("locale").Where("version_id = ?", versionID).Pluck("locale", &footerLocales); result.Error != nil {
+		return nil, result.Error
+	}
+
+	var menuItemIDs []uint
+	if result := database.Pg.Model(&models.MenuItem{}).Where("version_id = ? AND enabled_at IS NOT NULL", versionID).Pluck("id", &menuItemIDs); result.Error != nil {
+		return nil, result.Error
+	}
+
+	locales := dedupeStrings(append(pageLocales, footerLocales...))
+	tasks := make([]cacheWarmUpTask, 0, len(locales)*(len(menuItemIDs)+2))
+
+	for _, locale := range locales {
+		tasks = append(tasks,
+			cacheWarmUpTask{
+				name: fmt.Sprintf("menus %s", locale),
+				warm: func() error {
+					chain, err := GetLocaleChainByVersionID(versionID, locale)
+					if err != nil {
+						return err
+					}
+
+					_, err = GetMenusByVersionIDAndLocales(versionID, chain)
+					return err
+				},
+			},
+			cacheWarmUpTask{
+				name: fmt.Sprintf("footer %s", locale),
+				warm: func() error {
+					chain, err := GetLocaleChainByVersionID(versionID, locale)
+					if err != nil {
+						return err
+					}
+
+					_, err = GetFooterByVersionIDAndLocales(versionID, chain)
+					return err
+				},
+			},
+		)
+
+		for _, menuItemID := range menuItemIDs {
+			tasks = append(tasks, cacheWarmUpTask{
+				name: fmt.Sprintf("page %d %s", menuItemID, locale),
+				warm: func() error {
+					_, err := GetPublishedPageWithFallback(menuItemID, locale)
+					return err
+				},
+			})
+		}
+	}
+
+	return tasks, nil
+}
+
+// getCacheWarmUpConcurrency gets the number of tasks of a warm-up that run at the same time.
+func getCacheWarmUpConcurrency() int {
+	concurrency, err := strconv.Atoi(os.Getenv("CACHE_WARM_UP_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		return defaultCacheWarmUpConcurrency
+	}
+
+	return concurrency
+}
+
+// getCacheWarmUpCacheKey gets the key for the cache.
+func getCacheWarmUpCacheKey(versionID uint) string {
+	return fmt.Sprintf("versions:warm:%d", versionID)
+}
+
+// getCacheWarmUpFromCache gets the warm-up status of a version from the cache.
+func getCacheWarmUpFromCache(versionID uint) (*responses.CacheWarmUp, error) {
+	return cache.Get[responses.CacheWarmUp](getCacheWarmUpCacheKey(versionID))
+}
+
+// setCacheWarmUpToCache sets the warm-up status of a version to the cache.
+func setCacheWarmUpToCache(versionID uint, status *responses.CacheWarmUp) error {
+	return cache.Set(getCacheWarmUpCacheKey(versionID), status)
+}
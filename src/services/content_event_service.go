@@ -0,0 +1,149 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// contentEventChannel is the cache pub/sub channel that fans content events out across replicas.
+const contentEventChannel = "content:events"
+
+// contentEventBuffer is the number of events buffered per subscriber.
+// A subscriber that falls further behind misses events.
+const contentEventBuffer = 64
+
+// contentEventSubscriber receives the content events of an app, optionally limited to one version.
+type contentEventSubscriber struct {
+	appName   string
+	versionID *uint
+	events    chan responses.ContentEvent
+}
+
+// contentEventSubscribers holds the subscribers connected to this replica.
+var contentEventSubscribers = struct {
+	sync.RWMutex
+	subscribers map[*contentEventSubscriber]struct{}
+}{subscribers: make(map[*contentEventSubscriber]struct{})}
+
+// SubscribeContentEvents method to receive the content events of an app, optionally limited to one version.
+// Events without a version, e.g. of modules, are received by every subscriber of the app.
+// The returned function ends the subscription.
+func SubscribeContentEvents(appName string, versionID *uint) (<-chan responses.ContentEvent, func()) {
+	subscriber := &contentEventSubscriber{
+		appName:   appName,
+		versionID: versionID,
+		events:    make(chan responses.ContentEvent, contentEventBuffer),
+	}
+
+	contentEventSubscribers.Lock()
+	contentEventSubscribers.subscribers[subscriber] = struct{}{}
+	contentEventSubscribers.Unlock()
+
+	return subscriber.events, func() {
+		contentEventSubscribers.Lock()
+		delete(contentEventSubscribers.subscribers, subscriber)
+		contentEventSubscribers.Unlock()
+	}
+}
+
+// ListenContentEvents method to receive the content events of every replica from the cache store
+// and pass them on to the subscribers of this replica.
+// It blocks until the context is cancelled or the subscription fails.
+func ListenContentEvents(ctx context.Context) error {
+	return cache.Subscribe(ctx, contentEventChannel, func(message []byte) {
+		event := responses.ContentEvent{}
+		if err := json.Unmarshal(message, &event); err != nil {
+			return
+		}
+
+		dispatchContentEvent(&event)
+	})
+}
+
+// dispatchContentEvent passes an event on to the matching subscribers without waiting for slow ones.
+func dispatchContentEvent(event *responses.ContentEvent) {
+	contentEventSubscribers.RLock()
+	defer contentEventSubscribers.RUnlock()
+
+	for subscriber := range contentEventSubscribers.subscribers {
+		if subscriber.appName != event.AppName {
+			continue
+		} else if subscriber.versionID != nil && event.VersionID != nil && *subscriber.versionID != *event.VersionID {
+			continue
+		}
+
+		select {
+		case subscriber.events <- *event:
+		default:
+		}
+	}
+}
+
+// publishContentEvent publishes a content event to the subscribers of every replica.
+func publishContentEvent(eventType enums.ContentEventType, appName string, versionID *uint, entityID string, pageID *string, revision uint64) error {
+	event := responses.ContentEvent{
+		Type:       eventType.String(),
+		AppName:    appName,
+		VersionID:  versionID,
+		EntityID:   entityID,
+		PageID:     pageID,
+		OccurredAt: time.Now(),
+	}
+	if revision > 0 {
+		event.Revision = &revision
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return cache.Publish(contentEventChannel, value)
+}
+
+// publishVersionContentEvent publishes a content event of an entity of a version.
+// A revision of zero is left out of the event.
+func publishVersionContentEvent(eventType enums.ContentEventType, versionID uint, entityID string, revision uint64) error {
+	var appName string
+	if result := database.Pg.Unscoped().Model(&models.Version{}).Where("id = ?", versionID).Pluck("app_name", &appName); result.Error != nil {
+		return result.Error
+	}
+
+	return publishContentEvent(eventType, appName, &versionID, entityID, nil, revision)
+}
+
+// publishPageContentEvent publishes a content event of a page, or of a page partial when partialID is not zero.
+// A revision of zero is left out of the event.
+func publishPageContentEvent(eventType enums.ContentEventType, menuItemID uint, locale string, partialID uint, revision uint64) error {
+	versionID, err := GetVersionIDByMenuItemID(menuItemID)
+	if err != nil {
+		return err
+	}
+
+	var appName string
+	if result := database.Pg.Unscoped().Model(&models.Version{}).Where("id = ?", versionID).Pluck("app_name", &appName); result.Error != nil {
+		return result.Error
+	}
+
+	pageID := fmt.Sprintf("%d/%s", menuItemID, locale)
+	if partialID == 0 {
+		return publishContentEvent(eventType, appName, &versionID, pageID, nil, revision)
+	}
+
+	return publishContentEvent(eventType, appName, &versionID, strconv.FormatUint(uint64(partialID), 10), &pageID, revision)
+}
+
+// publishModuleContentEvent publishes a content event of a module. Modules belong to the app, not to a version.
+// A revision of zero is left out of the event.
+func publishModuleContentEvent(eventType enums.ContentEventType, appName string, moduleID uint, revision uint64) error {
+	return publishContentEvent(eventType, appName, nil, strconv.FormatUint(uint64(moduleID), 10), nil, revision)
+}
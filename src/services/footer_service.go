@@ -4,15 +4,13 @@ import (
 	"api-page/main/src/cache"
 	"api-page/main/src/database"
 	"api-page/main/src/dto/requests"
+	"api-page/main/src/enums"
 	"api-page/main/src/models"
-	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/ArnoldPMolenaar/api-utils/utils"
-	"github.com/valkey-io/valkey-go"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -21,56 +19,122 @@ const MaxRowTreeDepth = 4
 
 // GetFooterByVersionID retrieves a Footer by its versionID.
 func GetFooterByVersionID(versionID uint, locale string) (*[]models.FooterRow, error) {
-	rows := make([]models.FooterRow, 0)
+	return GetFooterByVersionIDAndLocales(versionID, []string{locale})
+}
 
-	if inCache, err := isFooterInCache(versionID, locale); err != nil {
-		return nil, err
-	} else if inCache {
-		if cacheRows, err := getFooterFromCache(versionID, locale); err != nil {
+// GetFooterRevision retrieves the revision of the Footer of a version in a locale.
+// A Footer that was never updated has revision 0.
+func GetFooterRevision(versionID uint, locale string) (uint64, error) {
+	return getFooterRevisionWithTx(database.Pg, versionID, locale)
+}
+
+// getFooterRevisionWithTx retrieves the revision of the Footer of a version in a locale using the provided transaction.
+func getFooterRevisionWithTx(tx *gorm.DB, versionID uint, locale string) (uint64, error) {
+	footer := &models.Footer{}
+
+	if result := tx.Limit(1).Find(footer, "version_id = ? AND locale = ?", versionID, locale); result.Error != nil {
+		return 0, result.Error
+	}
+
+	return footer.Revision, nil
+}
+
+// GetFooterByVersionIDAndLocales retrieves the Footer of a version in the first locale of the chain that has rows.
+// The rows are cached under the whole chain, also when there are none, and the served locale is the Locale of the rows.
+// Modules of a versioned module type are served as they were when the version was published.
+func GetFooterByVersionIDAndLocales(versionID uint, locales []string) (*[]models.FooterRow, error) {
+	chain := strings.Join(locales, localeChainSeparator)
+
+	return cache.Fetch(getFooterCacheKey(versionID, chain), func() (*[]models.FooterRow, error) {
+		_ = setLocaleChainKeyToCache(getFooterCacheKey(versionID, ""), locales)
+
+		rows, err := findFooterByVersionIDAndLocales(versionID, locales)
+		if err != nil {
 			return nil, err
-		} else if cacheRows != nil {
-			rows = *cacheRows
 		}
-	}
 
-	if len(rows) == 0 {
+		if err := applyModuleSnapshotsToFooter(versionID, *rows); err != nil {
+			return nil, err
+		}
+
+		return rows, nil
+	})
+}
+
+// GetPreviewFooterByVersionIDAndLocales retrieves the Footer of a version in the first locale of the chain that has rows.
+// The rows are never read from or written to the cache.
+func GetPreviewFooterByVersionIDAndLocales(versionID uint, locales []string) (*[]models.FooterRow, error) {
+	return findFooterByVersionIDAndLocales(versionID, locales)
+}
+
+// findFooterByVersionIDAndLocales finds the root rows of the Footer of a version in the first locale of the chain that has rows.
+// The Version is included, so the cached rows hold the moment their content changed.
+func findFooterByVersionIDAndLocales(versionID uint, locales []string) (*[]models.FooterRow, error) {
+	rows := make([]models.FooterRow, 0)
+
+	for _, locale := range locales {
 		if result := preloadFooterTree(database.Pg).
+			Preload("Version").
 			Where("NOT EXISTS (SELECT 1 FROM footer_row_column_rows frcr WHERE frcr.row_id = footer_rows.id)").
 			Order("position asc").
 			Find(&rows, "version_id = ? AND locale = ?", versionID, locale); result.Error != nil {
 			return nil, result.Error
+		} else if len(rows) > 0 {
+			break
 		}
-		_ = setFooterToCache(versionID, locale, &rows)
 	}
 
 	return &rows, nil
 }
 
 // UpdateFooter updates the given Footer and its associated rows and columns
-// based on the data provided in the UpdateFooter request.
-func UpdateFooter(versionID uint, locale string, footerRows *[]models.FooterRow, dtoFooter *requests.UpdateFooter) (*[]models.FooterRow, error) {
+// based on the data provided in the UpdateFooter request, and notifies the webhooks of the app.
+// ErrRevisionConflict is returned when the Footer is no longer at the given revision.
+func UpdateFooter(versionID uint, locale string, footerRows *[]models.FooterRow, dtoFooter *requests.UpdateFooter, revision uint64) (*[]models.FooterRow, error) {
 	var result *[]models.FooterRow
 
 	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
 		var txErr error
-		result, txErr = UpdateFooterWithTx(tx, versionID, locale, footerRows, dtoFooter)
-		return txErr
+		if result, txErr = UpdateFooterWithTx(tx, versionID, locale, footerRows, dtoFooter, revision); txErr != nil {
+			return txErr
+		}
+
+		if txErr := touchVersionWithTx(tx, versionID); txErr != nil {
+			return txErr
+		}
+
+		var appName string
+		if result := tx.Model(&models.Version{}).Where("id = ?", versionID).Pluck("app_name", &appName); result.Error != nil {
+			return result.Error
+		}
+
+		return enqueueWebhookEventWithTx(tx, appName, enums.WEBHOOK_FOOTER_UPDATED, map[string]any{
+			"versionId": versionID,
+			"locale":    locale,
+		})
 	}); err != nil {
 		return nil, err
 	}
 
 	_ = deleteFooterFromCache(versionID, locale)
+	if revision, err := GetFooterRevision(versionID, locale); err == nil {
+		_ = publishVersionContentEvent(enums.CONTENT_FOOTER_UPDATED, versionID, fmt.Sprintf("%d/%s", versionID, locale), revision)
+	}
 
 	return result, nil
 }
 
-// UpdateFooterWithTx updates footer rows/columns using the provided transaction.
+// UpdateFooterWithTx updates footer rows/columns of a Footer at the expected revision using the provided transaction.
 // It performs no transaction lifecycle control and no cache side effects.
-func UpdateFooterWithTx(tx *gorm.DB, versionID uint, locale string, footerRows *[]models.FooterRow, dtoFooter *requests.UpdateFooter) (*[]models.FooterRow, error) {
+func UpdateFooterWithTx(tx *gorm.DB, versionID uint, locale string, footerRows *[]models.FooterRow, dtoFooter *requests.UpdateFooter, revision uint64) (*[]models.FooterRow, error) {
 	if tx == nil {
 		return nil, gorm.ErrInvalidDB
 	}
 
+	if err := incrementFooterRevisionWithTx(tx, versionID, locale, revision); err != nil {
+		return nil, err
+	}
+
 	result := make([]models.FooterRow, 0)
 
 	existingRows := make([]models.FooterRow, len(*footerRows))
@@ -86,6 +150,30 @@ func UpdateFooterWithTx(tx *gorm.DB, versionID uint, locale string, footerRows *
 	return &result, nil
 }
 
+// incrementFooterRevisionWithTx increments the revision of the Footer of a version in a locale when it is still
+// at the expected revision, where a Footer that does not exist yet is at revision 0. The row stays locked until
+// the transaction ends. ErrRevisionConflict is returned when the revision has moved on.
+// It performs no transaction lifecycle control and no cache side effects.
+func incrementFooterRevisionWithTx(tx *gorm.DB, versionID uint, locale string, revision uint64) error {
+	footer := &models.Footer{VersionID: versionID, Locale: locale, Revision: 1}
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "version_id"}, {Name: "locale"}},
+		Where:   clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "footers", Name: "revision"}, Value: revision}}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"revision":   gorm.Expr("footers.revision + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(footer)
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrRevisionConflict
+	}
+
+	return nil
+}
+
 // syncFooterRows synchronizes the FooterRows for a given Footer based on the provided DTO rows.
 func syncFooterRows(tx *gorm.DB, versionID uint, locale string, parentColumnID *uint, existingRows []models.FooterRow, dtoRows []requests.UpdateFooterRow, depth int) ([]models.FooterRow, error) {
 	if depth > MaxRowTreeDepth {
@@ -297,73 +385,12 @@ func ensureFooterRowColumnRelation(tx *gorm.DB, columnID, rowID uint) error {
 	return tx.Where("column_id = ? AND row_id = ?", columnID, rowID).FirstOrCreate(&relation).Error
 }
 
-// getFooterCacheKey gets the key for the cache.
+// getFooterCacheKey gets the key for the cache. The locale can be a locale chain.
 func getFooterCacheKey(versionID uint, locale string) string {
 	return fmt.Sprintf("footers:%d:%s", versionID, locale)
 }
 
-// isFooterInCache checks if the footer exists in the cache.
-func isFooterInCache(versionID uint, locale string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getFooterCacheKey(versionID, locale)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
-}
-
-// getFooterFromCache gets the footer from the cache.
-func getFooterFromCache(versionID uint, locale string) (*[]models.FooterRow, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getFooterCacheKey(versionID, locale)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var rows []models.FooterRow
-	if err := json.Unmarshal([]byte(value), &rows); err != nil {
-		return nil, err
-	}
-
-	return &rows, nil
-}
-
-// setFooterToCache sets the footer rows to the cache.
-func setFooterToCache(versionID uint, locale string, rows *[]models.FooterRow) error {
-	value, err := json.Marshal(rows)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getFooterCacheKey(versionID, locale)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
-}
-
-// deleteFooterFromCache deletes existing footer from the cache.
+// deleteFooterFromCache deletes existing footer from the cache, including every locale chain that contains the locale.
 func deleteFooterFromCache(versionID uint, locale string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getFooterCacheKey(versionID, locale)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return deleteLocaleChainKeysFromCache(getFooterCacheKey(versionID, ""), locale)
 }
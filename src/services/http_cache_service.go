@@ -0,0 +1,48 @@
+package services
+
+import (
+	"api-page/main/src/models"
+	"fmt"
+	"os"
+	"time"
+)
+
+// GetHTTPCacheControl method to get the Cache-Control header of published content.
+// Browsers keep it for HTTP_CACHE_MAX_AGE and shared caches, e.g. a CDN, for HTTP_CACHE_SHARED_MAX_AGE.
+// A missing or invalid duration is zero, so the content is revalidated on every request.
+func GetHTTPCacheControl() string {
+	maxAge, err := time.ParseDuration(os.Getenv("HTTP_CACHE_MAX_AGE"))
+	if err != nil || maxAge < 0 {
+		maxAge = 0
+	}
+
+	sharedMaxAge, err := time.ParseDuration(os.Getenv("HTTP_CACHE_SHARED_MAX_AGE"))
+	if err != nil || sharedMaxAge < 0 {
+		sharedMaxAge = 0
+	}
+
+	return fmt.Sprintf("public, max-age=%d, s-maxage=%d", int64(maxAge.Seconds()), int64(sharedMaxAge.Seconds()))
+}
+
+// GetPageLastModified method to get the moment the content of a published page changed, as cached with the page.
+func GetPageLastModified(page *models.Page) time.Time {
+	return page.MenuItem.Version.ContentChangedAt
+}
+
+// GetMenusLastModified method to get the moment the content of published menus changed, as cached with the menus.
+func GetMenusLastModified(menus *[]models.Menu) time.Time {
+	if menus == nil || len(*menus) == 0 {
+		return time.Time{}
+	}
+
+	return (*menus)[0].Version.ContentChangedAt
+}
+
+// GetFooterLastModified method to get the moment the content of a published footer changed, as cached with the rows.
+func GetFooterLastModified(rows *[]models.FooterRow) time.Time {
+	if rows == nil || len(*rows) == 0 {
+		return time.Time{}
+	}
+
+	return (*rows)[0].Version.ContentChangedAt
+}
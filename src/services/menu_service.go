@@ -7,20 +7,15 @@ import (
 	"api-page/main/src/dto/responses"
 	"api-page/main/src/enums"
 	"api-page/main/src/models"
-	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/ArnoldPMolenaar/api-utils/pagination"
 	"github.com/ArnoldPMolenaar/api-utils/utils"
 	"github.com/gofiber/fiber/v3"
-	"github.com/valkey-io/valkey-go"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -162,41 +157,98 @@ func GetMenuLookup(versionID uint, name *string) (*[]models.Menu, error) {
 
 // GetMenusByVersionID method to get menus by version ID and locale.
 func GetMenusByVersionID(versionID uint, locale string) (*[]models.Menu, error) {
-	menus := make([]models.Menu, 0)
+	return GetMenusByVersionIDAndLocales(versionID, []string{locale})
+}
 
-	if inCache, err := isVersionMenusInCache(versionID); err != nil {
-		return nil, err
-	} else if inCache {
-		if cacheMenus, err := getVersionMenusFromCache(versionID, locale); err != nil {
+// GetMenusByVersionIDAndLocales method to get menus by version ID along a locale chain.
+// Every menu item is served with the page of the first locale in the chain that has an enabled page,
+// and items without such a page are left out. The menus are cached under the whole chain
+// and concurrent misses load them once.
+func GetMenusByVersionIDAndLocales(versionID uint, locales []string) (*[]models.Menu, error) {
+	chain := strings.Join(locales, localeChainSeparator)
+
+	menus, err := cache.Fetch(getVersionMenusCacheKey(versionID, chain), func() (*[]models.Menu, error) {
+		_ = setLocaleChainKeyToCache(getVersionMenusCacheKey(versionID, ""), locales)
+
+		findMenus, err := findMenusByVersionIDAndLocales(versionID, locales, true)
+		if err != nil || len(*findMenus) == 0 {
 			return nil, err
-		} else if cacheMenus != nil && len(*cacheMenus) > 0 {
-			menus = *cacheMenus
 		}
+
+		return findMenus, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if menus == nil {
+		menus = &[]models.Menu{}
 	}
 
-	if len(menus) == 0 {
-		if result := database.Pg.
-			Preload("MenuItemRelations", func(db *gorm.DB) *gorm.DB {
-				return db.Preload("MenuItemChild", func(db2 *gorm.DB) *gorm.DB {
-					return db2.Preload("Pages", func(db3 *gorm.DB) *gorm.DB {
-						return db3.Where("locale = ? AND enabled_at IS NOT NULL", locale)
-					}).Where("enabled_at IS NOT NULL")
-				}).
-					Joins("JOIN menu_items mi ON mi.id = menu_item_relations.menu_item_child_id").
-					Joins("JOIN pages p ON p.menu_item_id = mi.id AND p.locale = ? AND p.enabled_at IS NOT NULL", locale).
-					Order("menu_item_parent_id NULLS FIRST").
-					Order("position ASC")
+	return menus, nil
+}
+
+// GetPreviewMenusByVersionIDAndLocales method to get menus by version ID along a locale chain,
+// including disabled menu items and pages. The menus are never read from or written to the cache.
+func GetPreviewMenusByVersionIDAndLocales(versionID uint, locales []string) (*[]models.Menu, error) {
+	return findMenusByVersionIDAndLocales(versionID, locales, false)
+}
+
+// findMenusByVersionIDAndLocales finds the menus of a version with the page of the first locale in the chain
+// for every menu item. When published is true, only enabled menu items and pages are found.
+// The Version is included, so the cached menus hold the moment their content changed.
+func findMenusByVersionIDAndLocales(versionID uint, locales []string, published bool) (*[]models.Menu, error) {
+	menus := make([]models.Menu, 0)
+	pageCondition := "p.deleted_at IS NULL"
+	if published {
+		pageCondition = "p.enabled_at IS NOT NULL AND " + pageCondition
+	}
+
+	if result := database.Pg.
+		Preload("Version").
+		Preload("MenuItemRelations", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("MenuItemChild", func(db2 *gorm.DB) *gorm.DB {
+				db2 = db2.Preload("Pages", func(db3 *gorm.DB) *gorm.DB {
+					if published {
+						db3 = db3.Where("enabled_at IS NOT NULL")
+					}
+					return db3.Where("locale IN ?", locales)
+				})
+				if published {
+					db2 = db2.Where("enabled_at IS NOT NULL")
+				}
+				return db2
 			}).
-			Find(&menus, "version_id = ?", versionID); result.Error != nil {
-			return nil, result.Error
-		}
+				Joins("JOIN menu_items mi ON mi.id = menu_item_relations.menu_item_child_id").
+				Where("EXISTS (SELECT 1 FROM pages p WHERE p.menu_item_id = mi.id AND p.locale IN ? AND "+pageCondition+")", locales).
+				Order("menu_item_parent_id NULLS FIRST").
+				Order("position ASC")
+		}).
+		Find(&menus, "version_id = ?", versionID); result.Error != nil {
+		return nil, result.Error
+	}
 
-		_ = setVersionMenusToCache(versionID, locale, &menus)
+	for i := range menus {
+		for j := range menus[i].MenuItemRelations {
+			menuItem := &menus[i].MenuItemRelations[j].MenuItemChild
+			menuItem.Pages = selectPageByLocales(menuItem.Pages, locales)
+		}
 	}
 
 	return &menus, nil
 }
 
+// selectPageByLocales keeps only the page of the first locale in the chain.
+func selectPageByLocales(pages []models.Page, locales []string) []models.Page {
+	for _, locale := range locales {
+		for i := range pages {
+			if pages[i].Locale == locale {
+				return []models.Page{pages[i]}
+			}
+		}
+	}
+
+	return make([]models.Page, 0)
+}
+
 // GetMenuByID method to get a menu by ID.
 func GetMenuByID(menuID uint) (*models.Menu, error) {
 	menu := &models.Menu{}
@@ -231,14 +283,18 @@ func CreateMenu(menu *requests.CreateMenu) (*models.Menu, error) {
 	var result *models.Menu
 	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
 		var txErr error
-		result, txErr = CreateMenuWithTx(tx, menu)
-		return txErr
+		if result, txErr = CreateMenuWithTx(tx, menu); txErr != nil {
+			return txErr
+		}
+
+		return touchVersionWithTx(tx, menu.VersionID)
 	}); err != nil {
 		return nil, err
 	}
 
 	_ = deleteMenusLookupFromCache(menu.VersionID)
 	_ = deleteAllVersionMenusFromCache(menu.VersionID)
+	_ = publishVersionContentEvent(enums.CONTENT_MENU_CREATED, menu.VersionID, strconv.FormatUint(uint64(result.ID), 10), result.Revision)
 
 	return result, nil
 }
@@ -273,28 +329,70 @@ func CreateMenuWithTx(tx *gorm.DB, menu *requests.CreateMenu) (*models.Menu, err
 	return result, nil
 }
 
-// UpdateMenu method to update a menu.
-func UpdateMenu(oldMenu *models.Menu, menu *requests.UpdateMenu) (*models.Menu, error) {
+// UpdateMenu method to update a menu and notify the webhooks of the app.
+// ErrRevisionConflict is returned when the menu is no longer at the given revision.
+func UpdateMenu(oldMenu *models.Menu, menu *requests.UpdateMenu, revision uint64) (*models.Menu, error) {
 	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
-		_, txErr := UpdateMenuWithTx(tx, oldMenu, menu)
-		return txErr
+		version := &models.Version{}
+		if result := tx.Select("app_name", "published_at").First(version, oldMenu.VersionID); result.Error != nil {
+			return result.Error
+		}
+		appName := version.AppName
+
+		// Only addresses of the published version are live, a draft registers its redirects when it is published.
+		var oldAddresses map[string]map[uint]*pageAddress
+		if version.PublishedAt.Valid {
+			var txErr error
+			if oldAddresses, txErr = getVersionPageAddressesWithTx(tx, oldMenu.VersionID); txErr != nil {
+				return txErr
+			}
+		}
+
+		if _, txErr := UpdateMenuWithTx(tx, oldMenu, menu, revision); txErr != nil {
+			return txErr
+		}
+
+		if version.PublishedAt.Valid {
+			newAddresses, txErr := getVersionPageAddressesWithTx(tx, oldMenu.VersionID)
+			if txErr != nil {
+				return txErr
+			}
+
+			if txErr := registerVersionAddressRedirectsWithTx(tx, appName, oldAddresses, newAddresses, nil); txErr != nil {
+				return txErr
+			}
+		}
+
+		if txErr := touchVersionWithTx(tx, oldMenu.VersionID); txErr != nil {
+			return txErr
+		}
+
+		return enqueueWebhookEventWithTx(tx, appName, enums.WEBHOOK_MENU_UPDATED, map[string]any{
+			"versionId": oldMenu.VersionID,
+			"menuId":    oldMenu.ID,
+		})
 	}); err != nil {
 		return nil, err
 	}
 
 	_ = deleteMenusLookupFromCache(oldMenu.VersionID)
 	_ = deleteAllVersionMenusFromCache(oldMenu.VersionID)
+	_ = publishVersionContentEvent(enums.CONTENT_MENU_UPDATED, oldMenu.VersionID, strconv.FormatUint(uint64(oldMenu.ID), 10), oldMenu.Revision)
 
 	return oldMenu, nil
 }
 
-// UpdateMenuWithTx updates a menu using the provided transaction.
+// UpdateMenuWithTx updates a menu at the expected revision using the provided transaction.
 // It performs no transaction lifecycle control and no cache side effects.
-func UpdateMenuWithTx(tx *gorm.DB, oldMenu *models.Menu, menu *requests.UpdateMenu) (*models.Menu, error) {
+func UpdateMenuWithTx(tx *gorm.DB, oldMenu *models.Menu, menu *requests.UpdateMenu, revision uint64) (*models.Menu, error) {
 	if tx == nil {
 		return nil, gorm.ErrInvalidDB
 	}
 
+	if err := incrementRevisionWithTx(tx, oldMenu, revision); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Model(&oldMenu).
 		Clauses(clause.Returning{Columns: []clause.Column{{Name: "name"}, {Name: "updated_at"}}}).
 		Updates(models.Menu{Name: menu.Name, Depth: utils.NewNull[uint8](menu.Depth)}).Error; err != nil {
@@ -315,32 +413,60 @@ func UpdateMenuWithTx(tx *gorm.DB, oldMenu *models.Menu, menu *requests.UpdateMe
 	return oldMenu, nil
 }
 
-// DeleteMenu method to delete a menu.
+// DeleteMenu method to delete a menu and notify the webhooks of the app.
 func DeleteMenu(versionID, menuID uint) error {
-	err := database.Pg.Delete(&models.Menu{}, menuID).Error
-	if err == nil {
-		_ = deleteMenusLookupFromCache(versionID)
-		_ = deleteAllVersionMenusFromCache(versionID)
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Delete(&models.Menu{}, menuID).Error; txErr != nil {
+			return txErr
+		}
+
+		if txErr := touchVersionWithTx(tx, versionID); txErr != nil {
+			return txErr
+		}
+
+		var appName string
+		if result := tx.Model(&models.Version{}).Where("id = ?", versionID).Pluck("app_name", &appName); result.Error != nil {
+			return result.Error
+		}
+
+		return enqueueWebhookEventWithTx(tx, appName, enums.WEBHOOK_MENU_DELETED, map[string]any{
+			"versionId": versionID,
+			"menuId":    menuID,
+		})
+	}); err != nil {
+		return err
 	}
 
-	return err
+	_ = deleteMenusLookupFromCache(versionID)
+	_ = deleteAllVersionMenusFromCache(versionID)
+	_ = publishVersionContentEvent(enums.CONTENT_MENU_DELETED, versionID, strconv.FormatUint(uint64(menuID), 10), 0)
+
+	return nil
 }
 
 // RestoreMenu method to restore a deleted menu.
 func RestoreMenu(menuID uint) error {
-	err := database.Pg.Unscoped().Model(&models.Menu{}).Where("id = ?", menuID).Update("deleted_at", nil).Error
-	if err == nil {
-		var versionID uint
+	var versionID uint
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Unscoped().Model(&models.Menu{}).Where("id = ?", menuID).Update("deleted_at", nil).Error; txErr != nil {
+			return txErr
+		}
 
-		if result := database.Pg.Unscoped().Model(&models.Menu{}).Where("id = ?", menuID).Pluck("version_id", &versionID); result.Error != nil {
+		if result := tx.Unscoped().Model(&models.Menu{}).Where("id = ?", menuID).Pluck("version_id", &versionID); result.Error != nil {
 			return result.Error
 		}
 
-		_ = deleteMenusLookupFromCache(versionID)
-		_ = deleteAllVersionMenusFromCache(versionID)
+		return touchVersionWithTx(tx, versionID)
+	}); err != nil {
+		return err
 	}
 
-	return err
+	_ = deleteMenusLookupFromCache(versionID)
+	_ = deleteAllVersionMenusFromCache(versionID)
+	_ = publishVersionContentEvent(enums.CONTENT_MENU_RESTORED, versionID, strconv.FormatUint(uint64(menuID), 10), 0)
+
+	return nil
 }
 
 // getMenusLookupCacheKey gets the key for the cache.
@@ -350,223 +476,51 @@ func getMenusLookupCacheKey(versionID uint) string {
 
 // isMenusLookupInCache checks if the menus exists in the cache.
 func isMenusLookupInCache(versionID uint) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getMenusLookupCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+	return cache.Exists(getMenusLookupCacheKey(versionID))
 }
 
 // getMenusLookupFromCache gets the menus from the cache.
 func getMenusLookupFromCache(versionID uint) (*[]models.Menu, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getMenusLookupCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var menus []models.Menu
-	if err := json.Unmarshal([]byte(value), &menus); err != nil {
-		return nil, err
-	}
-
-	return &menus, nil
+	return cache.Get[[]models.Menu](getMenusLookupCacheKey(versionID))
 }
 
 // setMenusLookupToCache sets the menus to the cache.
 func setMenusLookupToCache(versionID uint, menus *[]models.Menu) error {
-	value, err := json.Marshal(menus)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getMenusLookupCacheKey(versionID)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Set(getMenusLookupCacheKey(versionID), menus)
 }
 
 // deleteMenusLookupFromCache deletes existing menus from the cache.
 func deleteMenusLookupFromCache(versionID uint) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getMenusLookupCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
-}
-
-// getVersionMenusCacheKey gets the key for the cache.
-func getVersionMenusCacheKey(versionID uint) string {
-	return fmt.Sprintf("menus:version:%d:locales", versionID)
+	return cache.Delete(getMenusLookupCacheKey(versionID))
 }
 
-// isVersionMenusInCache checks if the menus of a version exists in the cache.
-func isVersionMenusInCache(versionID uint) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getVersionMenusCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+// getVersionMenusCacheKey gets the key for the cache. The locale can be a locale chain.
+func getVersionMenusCacheKey(versionID uint, locale string) string {
+	return fmt.Sprintf("menus:version:%d:%s", versionID, locale)
 }
 
-// getAllVersionMenusFromCache gets the menus in a version from the cache.
-func getAllVersionMenusFromCache(versionID uint) (map[string][]models.Menu, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getVersionMenusCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var versionMenus map[string][]models.Menu
-	if err := json.Unmarshal([]byte(value), &versionMenus); err != nil {
-		return nil, err
-	}
-
-	return versionMenus, nil
-}
-
-// getVersionMenusFromCache gets the menus in a version with a locale from the cache.
-func getVersionMenusFromCache(versionID uint, locale string) (*[]models.Menu, error) {
-	var versionMenus map[string][]models.Menu
-
-	if inCache, err := isVersionMenusInCache(versionID); err != nil {
-		return nil, err
-	} else if inCache {
-		if versionMenus, err = getAllVersionMenusFromCache(versionID); err != nil {
-			return nil, err
-		}
-	}
-
-	if versionMenus == nil {
-		return nil, nil
-	}
-
-	if menus, ok := versionMenus[locale]; ok {
-		return &menus, nil
-	}
-
-	return nil, nil
-}
-
-// setVersionMenusToCache sets the menus of a version to the cache.
-func setVersionMenusToCache(versionID uint, locale string, menus *[]models.Menu) error {
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	var versionMenus map[string][]models.Menu
-
-	if inCache, err := isVersionMenusInCache(versionID); err != nil {
-		return err
-	} else if inCache {
-		if versionMenus, err = getAllVersionMenusFromCache(versionID); err != nil {
-			return err
-		}
-	}
-
-	if versionMenus == nil {
-		versionMenus = map[string][]models.Menu{}
-	}
-	versionMenus[locale] = *menus
-
-	value, err := json.Marshal(versionMenus)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getVersionMenusCacheKey(versionID)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
-}
-
-// deleteVersionMenusFromCache deletes existing menus in a version from the cache.
+// deleteVersionMenusFromCache deletes existing menus in a version from the cache, including every locale chain that contains the locale.
 func deleteVersionMenusFromCache(versionID uint, locale string) error {
-	var versionMenus map[string][]models.Menu
-
-	if inCache, err := isVersionMenusInCache(versionID); err != nil {
+	if err := deletePagePathsFromCache(versionID); err != nil {
 		return err
-	} else if inCache {
-		if versionMenus, err = getAllVersionMenusFromCache(versionID); err != nil {
-			return err
-		}
-	}
-
-	if versionMenus == nil {
-		return nil
 	}
-
-	delete(versionMenus, locale)
-
-	if len(versionMenus) > 0 {
-		expiration := os.Getenv("VALKEY_EXPIRATION")
-		duration, err := time.ParseDuration(expiration)
-		if err != nil {
-			return err
-		}
-
-		value, err := json.Marshal(versionMenus)
-		if err != nil {
-			return err
-		}
-
-		result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getVersionMenusCacheKey(versionID)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-		if result.Error() != nil {
-			return result.Error()
-		}
-
-		return nil
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getVersionMenusCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return result.Error()
+	if err := deleteSitemapsFromCache(versionID); err != nil {
+		return err
 	}
 
-	return nil
+	return deleteLocaleChainKeysFromCache(getVersionMenusCacheKey(versionID, ""), locale)
 }
 
 // deleteAllVersionMenusFromCache deletes existing menus in a version for all languages from the cache.
 func deleteAllVersionMenusFromCache(versionID uint) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getVersionMenusCacheKey(versionID)).Build())
-	if result.Error() != nil {
-		return result.Error()
+	if err := deletePagePathsFromCache(versionID); err != nil {
+		return err
+	}
+	if err := deleteSitemapsFromCache(versionID); err != nil {
+		return err
 	}
 
-	return nil
+	return cache.DeleteByPrefix(getVersionMenusCacheKey(versionID, ""))
 }
 
 // sortMenuItemRelations sorts the relations grouped by parent (NULL first, then by parent ID) and within each group by Position.
@@ -5,18 +5,14 @@ import (
 	"api-page/main/src/database"
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/models"
-	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/ArnoldPMolenaar/api-utils/pagination"
 	"github.com/gofiber/fiber/v3"
-	"github.com/valkey-io/valkey-go"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -195,13 +191,19 @@ func GetModuleByID(moduleID uint) (*models.Module, error) {
 	return module, nil
 }
 
-// CreateModule method to create a module.
+// CreateModule method to create a module. The settings are recorded as the latest schema version of the module type.
 func CreateModule(module *requests.CreateModule) (*models.Module, error) {
+	settingsVersion, err := GetLatestModuleTypeSchemaVersion(module.Type)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &models.Module{
-		AppName:  module.AppName,
-		Type:     module.Type,
-		Name:     module.Name,
-		Settings: datatypes.JSON(module.Settings),
+		AppName:         module.AppName,
+		Type:            module.Type,
+		Name:            module.Name,
+		Settings:        datatypes.JSON(module.Settings),
+		SettingsVersion: settingsVersion,
 	}
 
 	result := &models.Module{}
@@ -210,34 +212,66 @@ func CreateModule(module *requests.CreateModule) (*models.Module, error) {
 	}
 
 	_ = deleteModulesLookupFromCache(m.AppName)
+	_ = publishModuleContentEvent(enums.CONTENT_MODULE_CREATED, result.AppName, result.ID, result.Revision)
 
 	return result, nil
 }
 
-// UpdateModule method to update a module.
-func UpdateModule(oldModule *models.Module, module *requests.UpdateModule) (*models.Module, error) {
+// UpdateModule method to update a module. The settings are recorded as the latest schema version of the module type.
+// The pages and footers that use the module render the new settings, so they are deleted from the cache.
+// ErrRevisionConflict is returned when the module is no longer at the given revision.
+func UpdateModule(oldModule *models.Module, module *requests.UpdateModule, revision uint64) (*models.Module, error) {
 	if oldModule == nil {
 		return nil, gorm.ErrRecordNotFound
 	}
 
+	settingsVersion, err := GetLatestModuleTypeSchemaVersion(module.Type)
+	if err != nil {
+		return nil, err
+	}
+
 	oldModule.Name = module.Name
 	oldModule.Type = module.Type
 	oldModule.Settings = datatypes.JSON(module.Settings)
+	oldModule.SettingsVersion = settingsVersion
 
-	if result := database.Pg.Save(oldModule); result.Error != nil {
-		return nil, result.Error
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := incrementRevisionWithTx(tx, oldModule, revision); txErr != nil {
+			return txErr
+		}
+
+		if txErr := tx.Save(oldModule).Error; txErr != nil {
+			return txErr
+		}
+
+		return touchAppVersionsWithTx(tx, oldModule.AppName)
+	}); err != nil {
+		return nil, err
 	}
 
 	_ = deleteModulesLookupFromCache(oldModule.AppName)
+	_ = deleteModuleUsagesByIDFromCache(oldModule.ID)
+	_ = publishModuleContentEvent(enums.CONTENT_MODULE_UPDATED, oldModule.AppName, oldModule.ID, oldModule.Revision)
 
 	return oldModule, nil
 }
 
-// DeleteModule method to delete a module.
-func DeleteModule(moduleID uint, appName string) error {
-	err := database.Pg.Delete(&models.Module{}, moduleID).Error
+// DeleteModule method to delete a module. The pages and footers of the usages no longer render the module,
+// so they are deleted from the cache.
+func DeleteModule(moduleID uint, appName string, usages *responses.ModuleUsages) error {
+	err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Delete(&models.Module{}, moduleID).Error; txErr != nil {
+			return txErr
+		}
+
+		return touchAppVersionsWithTx(tx, appName)
+	})
 	if err == nil {
 		_ = deleteModulesLookupFromCache(appName)
+		if usages != nil {
+			deleteModuleUsagesFromCache(usages)
+		}
+		_ = publishModuleContentEvent(enums.CONTENT_MODULE_DELETED, appName, moduleID, 0)
 	}
 
 	return err
@@ -245,18 +279,26 @@ func DeleteModule(moduleID uint, appName string) error {
 
 // RestoreModule method to restore a deleted module.
 func RestoreModule(moduleID uint) error {
-	err := database.Pg.Unscoped().Model(&models.Module{}).Where("id = ?", moduleID).Update("deleted_at", nil).Error
-	if err == nil {
-		var appName string
+	var appName string
 
-		if result := database.Pg.Unscoped().Model(&models.Module{}).Where("id = ?", moduleID).Pluck("app_name", &appName); result.Error != nil {
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Unscoped().Model(&models.Module{}).Where("id = ?", moduleID).Update("deleted_at", nil).Error; txErr != nil {
+			return txErr
+		}
+
+		if result := tx.Unscoped().Model(&models.Module{}).Where("id = ?", moduleID).Pluck("app_name", &appName); result.Error != nil {
 			return result.Error
 		}
 
-		_ = deleteModulesLookupFromCache(appName)
+		return touchAppVersionsWithTx(tx, appName)
+	}); err != nil {
+		return err
 	}
 
-	return err
+	_ = deleteModulesLookupFromCache(appName)
+	_ = publishModuleContentEvent(enums.CONTENT_MODULE_RESTORED, appName, moduleID, 0)
+
+	return nil
 }
 
 // getModuleTypesLookupCacheKey gets the key for the cache.
@@ -270,68 +312,22 @@ func getModuleTypesLookupCacheKey(appName *string) string {
 
 // isModuleTypesLookupInCache checks if module types exist in the cache.
 func isModuleTypesLookupInCache(appName *string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getModuleTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+	return cache.Exists(getModuleTypesLookupCacheKey(appName))
 }
 
 // getModuleTypesLookupFromCache gets module types from the cache.
 func getModuleTypesLookupFromCache(appName *string) (*[]models.ModuleType, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getModuleTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var moduleTypes []models.ModuleType
-	if err := json.Unmarshal([]byte(value), &moduleTypes); err != nil {
-		return nil, err
-	}
-
-	return &moduleTypes, nil
+	return cache.Get[[]models.ModuleType](getModuleTypesLookupCacheKey(appName))
 }
 
 // setModuleTypesLookupToCache sets module types to the cache.
 func setModuleTypesLookupToCache(appName *string, moduleTypes *[]models.ModuleType) error {
-	value, err := json.Marshal(moduleTypes)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getModuleTypesLookupCacheKey(appName)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Set(getModuleTypesLookupCacheKey(appName), moduleTypes)
 }
 
 // deleteModuleTypesLookupFromCache deletes existing module type lookups from cache.
 func deleteModuleTypesLookupFromCache(appName *string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getModuleTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Delete(getModuleTypesLookupCacheKey(appName))
 }
 
 // getModulesLookupCacheKey gets the key for the cache.
@@ -341,68 +337,22 @@ func getModulesLookupCacheKey(appName string) string {
 
 // isModulesLookupInCache checks if the modules exists in the cache.
 func isModulesLookupInCache(appName string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getModulesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+	return cache.Exists(getModulesLookupCacheKey(appName))
 }
 
 // getModulesLookupFromCache gets the modules from the cache.
 func getModulesLookupFromCache(appName string) (*[]models.Module, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getModulesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var modules []models.Module
-	if err := json.Unmarshal([]byte(value), &modules); err != nil {
-		return nil, err
-	}
-
-	return &modules, nil
+	return cache.Get[[]models.Module](getModulesLookupCacheKey(appName))
 }
 
 // setModulesLookupToCache sets the modules to the cache.
 func setModulesLookupToCache(appName string, modules *[]models.Module) error {
-	value, err := json.Marshal(modules)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getModulesLookupCacheKey(appName)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Set(getModulesLookupCacheKey(appName), modules)
 }
 
 // deleteModulesLookupFromCache deletes existing modules from the cache.
 func deleteModulesLookupFromCache(appName string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getModulesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Delete(getModulesLookupCacheKey(appName))
 }
 
 // scopeExcludeDeletedModuleType excludes modules whose Type was soft-deleted.
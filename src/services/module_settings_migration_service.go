@@ -0,0 +1,334 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// MigrateModuleSettings method to migrate the settings of every module of a module type to a schema version.
+// The migrations of the schema versions after the settings version of a module are applied in order, and the result
+// is validated against the target schema. Modules that fail are reported and left unchanged.
+// When dryRun is set, the migrated settings are reported and nothing is saved. A module that changed
+// since it was migrated is reported as failed and left unchanged.
+// When the module type is live, the pages and footers that use a migrated module are deleted from the cache.
+func MigrateModuleSettings(moduleType string, toVersion uint, dryRun bool) (*responses.ModuleSettingsMigration, error) {
+	schemas := make([]models.ModuleTypeSchema, 0)
+	if result := database.Pg.Where("module_type_name = ? AND version <= ?", moduleType, toVersion).Order("version").Find(&schemas); result.Error != nil {
+		return nil, result.Error
+	} else if len(schemas) == 0 || schemas[len(schemas)-1].Version != toVersion {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	schema, err := getCompiledModuleTypeSchema(&schemas[len(schemas)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]models.Module, 0)
+	if result := database.Pg.Where("type = ? AND settings_version < ?", moduleType, toVersion).Order("id").Find(&modules); result.Error != nil {
+		return nil, result.Error
+	}
+
+	response := &responses.ModuleSettingsMigration{
+		ModuleType: moduleType,
+		ToVersion:  toVersion,
+		DryRun:     dryRun,
+		Modules:    make([]responses.ModuleSettingsMigrationModule, 0, len(modules)),
+	}
+	migratedModules := make([]*models.Module, 0, len(modules))
+	migratedResults := make([]int, 0, len(modules))
+
+	for i := range modules {
+		module := &modules[i]
+		result := responses.ModuleSettingsMigrationModule{
+			ID:          module.ID,
+			AppName:     module.AppName,
+			Name:        module.Name,
+			FromVersion: module.SettingsVersion,
+		}
+
+		settings, fields, err := migrateModuleSettings(module, schemas, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) > 0 {
+			result.Errors = fields
+			response.Failed++
+		} else {
+			result.Settings = settings
+			response.Migrated++
+
+			module.Settings = datatypes.JSON(settings)
+			module.SettingsVersion = toVersion
+			migratedModules = append(migratedModules, module)
+			migratedResults = append(migratedResults, len(response.Modules))
+		}
+
+		response.Modules = append(response.Modules, result)
+	}
+
+	if dryRun || len(migratedModules) == 0 {
+		return response, nil
+	}
+
+	savedModules := make([]*models.Module, 0, len(migratedModules))
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		savedModules = savedModules[:0]
+
+		for i, module := range migratedModules {
+			// The settings were migrated from the module at its revision, so a module that changed since is skipped.
+			if txErr := incrementRevisionWithTx(tx, module, module.Revision); errors.Is(txErr, ErrRevisionConflict) {
+				result := &response.Modules[migratedResults[i]]
+				result.Settings = nil
+				result.Errors = map[string]string{moduleSettingsField: "module changed during the migration"}
+				response.Migrated--
+				response.Failed++
+				continue
+			} else if txErr != nil {
+				return txErr
+			}
+
+			if txErr := tx.Model(module).Select("settings", "settings_version", "updated_at").Updates(module).Error; txErr != nil {
+				return txErr
+			}
+			if txErr := touchAppVersionsWithTx(tx, module.AppName); txErr != nil {
+				return txErr
+			}
+			savedModules = append(savedModules, module)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	migratedModules = savedModules
+
+	live := true
+	if modelModuleType, err := GetModuleType(moduleType); err == nil && modelModuleType.Name != "" {
+		live = modelModuleType.Behavior != enums.MODULE_VERSIONED
+	}
+
+	appNames := make([]string, 0)
+	for _, module := range migratedModules {
+		appNames = append(appNames, module.AppName)
+		if live {
+			_ = deleteModuleUsagesByIDFromCache(module.ID)
+		}
+		_ = publishModuleContentEvent(enums.CONTENT_MODULE_UPDATED, module.AppName, module.ID, module.Revision)
+	}
+	for _, appName := range dedupeStrings(appNames) {
+		_ = deleteModulesLookupFromCache(appName)
+	}
+
+	return response, nil
+}
+
+// MigrateModuleSettingsToLatest method to migrate settings of a schema version of a module type to the latest
+// schema version and validate them against it. It returns the migrated settings and their version, or the errors
+// keyed like the ones of ValidateModuleSettings. A module type without a schema accepts any settings.
+func MigrateModuleSettingsToLatest(moduleType string, settings json.RawMessage, settingsVersion uint) (json.RawMessage, uint, map[string]string, error) {
+	schemas := make([]models.ModuleTypeSchema, 0)
+	if result := database.Pg.Where("module_type_name = ?", moduleType).Order("version").Find(&schemas); result.Error != nil {
+		return nil, 0, nil, result.Error
+	} else if len(schemas) == 0 {
+		return settings, 0, nil, nil
+	}
+
+	latest := &schemas[len(schemas)-1]
+	if settingsVersion > latest.Version {
+		return nil, 0, map[string]string{moduleSettingsField: fmt.Sprintf("schema version %d does not exist", settingsVersion)}, nil
+	}
+
+	schema, err := getCompiledModuleTypeSchema(latest)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	migrated, fields, err := migrateModuleSettings(&models.Module{Settings: datatypes.JSON(settings), SettingsVersion: settingsVersion}, schemas, schema)
+	if err != nil || len(fields) > 0 {
+		return nil, 0, fields, err
+	}
+
+	return migrated, latest.Version, nil, nil
+}
+
+// migrateModuleSettings applies the migrations of the schemas after the settings version of the module
+// and validates the result against the compiled target schema. A migration that cannot be applied is reported as error of the settings.
+func migrateModuleSettings(module *models.Module, schemas []models.ModuleTypeSchema, schema *jsonschema.Schema) (json.RawMessage, map[string]string, error) {
+	settings, err := decodeModuleSettingsValue(json.RawMessage(module.Settings))
+	if err != nil {
+		return nil, map[string]string{moduleSettingsField: err.Error()}, nil
+	}
+
+	for i := range schemas {
+		migration := schemas[i].Migration.Data()
+		if schemas[i].Version <= module.SettingsVersion || len(migration) == 0 {
+			continue
+		}
+
+		object, ok := settings.(map[string]any)
+		if !ok {
+			return nil, map[string]string{moduleSettingsField: "settings are not an object"}, nil
+		}
+
+		if err := applyModuleSettingsTransforms(object, migration); err != nil {
+			return nil, map[string]string{moduleSettingsField: fmt.Sprintf("migration to version %d: %s", schemas[i].Version, err.Error())}, nil
+		}
+	}
+
+	migrated, err := json.Marshal(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields, err := validateModuleSettingsWithSchema(schema, migrated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return migrated, fields, nil
+}
+
+// applyModuleSettingsTransforms applies the steps of a migration to decoded settings.
+func applyModuleSettingsTransforms(settings map[string]any, migration []models.ModuleSettingsTransform) error {
+	for i, transform := range migration {
+		var err error
+
+		switch transform.Op {
+		case enums.MODULE_SETTINGS_RENAME:
+			if value, ok := getModuleSettingsPath(settings, transform.From); ok {
+				removeModuleSettingsPath(settings, transform.From)
+				err = setModuleSettingsPath(settings, transform.To, value)
+			}
+		case enums.MODULE_SETTINGS_COPY:
+			if value, ok := getModuleSettingsPath(settings, transform.From); ok {
+				var copied any
+				if copied, err = cloneModuleSettingsValue(value); err == nil {
+					err = setModuleSettingsPath(settings, transform.To, copied)
+				}
+			}
+		case enums.MODULE_SETTINGS_REMOVE:
+			removeModuleSettingsPath(settings, transform.Path)
+		case enums.MODULE_SETTINGS_SET:
+			var value any
+			if value, err = decodeModuleSettingsValue(transform.Value); err == nil {
+				err = setModuleSettingsPath(settings, transform.Path, value)
+			}
+		case enums.MODULE_SETTINGS_DEFAULT:
+			if _, ok := getModuleSettingsPath(settings, transform.Path); !ok {
+				var value any
+				if value, err = decodeModuleSettingsValue(transform.Value); err == nil {
+					err = setModuleSettingsPath(settings, transform.Path, value)
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown operation %s", transform.Op)
+		}
+
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %w", i, transform.Op, err)
+		}
+	}
+
+	return nil
+}
+
+// getModuleSettingsPath gets the value at a dot separated path of nested objects.
+func getModuleSettingsPath(settings map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	current := settings
+
+	for i, key := range keys {
+		value, ok := current[key]
+		if !ok {
+			return nil, false
+		} else if i == len(keys)-1 {
+			return value, true
+		}
+
+		if current, ok = value.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// setModuleSettingsPath sets the value at a dot separated path, creating the missing objects on the way.
+func setModuleSettingsPath(settings map[string]any, path string, value any) error {
+	keys := strings.Split(path, ".")
+	current := settings
+
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key]
+		if !ok {
+			child := map[string]any{}
+			current[key] = child
+			current = child
+			continue
+		}
+
+		if current, ok = next.(map[string]any); !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(keys[:i+1], "."))
+		}
+	}
+
+	current[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// removeModuleSettingsPath removes the value at a dot separated path, if it exists.
+func removeModuleSettingsPath(settings map[string]any, path string) {
+	keys := strings.Split(path, ".")
+	current := settings
+
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return
+		}
+		current = next
+	}
+
+	delete(current, keys[len(keys)-1])
+}
+
+// decodeModuleSettingsValue decodes JSON while keeping numbers exact.
+func decodeModuleSettingsValue(data json.RawMessage) (any, error) {
+	if len(data) == 0 {
+		return nil, errors.New("value is empty")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// cloneModuleSettingsValue deep copies a decoded value, so a copied object does not change with the original.
+func cloneModuleSettingsValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeModuleSettingsValue(data)
+}
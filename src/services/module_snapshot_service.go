@@ -0,0 +1,134 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// snapshotVersionModulesWithTx freezes the settings of every module that the page partial and footer columns
+// of a version refer to. With replace, the existing snapshots of the version are replaced,
+// otherwise only the missing snapshots are added.
+// It performs no transaction lifecycle control and no cache side effects.
+func snapshotVersionModulesWithTx(tx *gorm.DB, versionID uint, replace bool) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	pageModuleIDs := tx.Model(&models.PagePartialRowColumn{}).
+		Select("page_partial_row_columns.module_id").
+		Joins("JOIN page_partial_rows ON page_partial_rows.id = page_partial_row_columns.page_partial_row_id AND page_partial_rows.deleted_at IS NULL").
+		Joins("JOIN page_partials ON page_partials.id = page_partial_rows.page_partial_id AND page_partials.deleted_at IS NULL").
+		Joins("JOIN menu_items ON menu_items.id = page_partials.menu_item_id").
+		Where("menu_items.version_id = ? AND page_partial_row_columns.module_id IS NOT NULL", versionID)
+	footerModuleIDs := tx.Model(&models.FooterRowColumn{}).
+		Select("footer_row_columns.module_id").
+		Joins("JOIN footer_rows ON footer_rows.id = footer_row_columns.footer_row_id AND footer_rows.deleted_at IS NULL").
+		Where("footer_rows.version_id = ? AND footer_row_columns.module_id IS NOT NULL", versionID)
+
+	modules := make([]models.Module, 0)
+	if result := tx.Where("id IN (?) OR id IN (?)", pageModuleIDs, footerModuleIDs).Find(&modules); result.Error != nil {
+		return result.Error
+	}
+
+	if replace {
+		if result := tx.Where("version_id = ?", versionID).Delete(&models.ModuleSnapshot{}); result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if len(modules) == 0 {
+		return nil
+	}
+
+	snapshots := make([]models.ModuleSnapshot, len(modules))
+	for i := range modules {
+		snapshots[i] = models.ModuleSnapshot{
+			VersionID:       versionID,
+			ModuleID:        modules[i].ID,
+			Type:            modules[i].Type,
+			Name:            modules[i].Name,
+			Settings:        modules[i].Settings,
+			SettingsVersion: modules[i].SettingsVersion,
+			Revision:        modules[i].Revision,
+		}
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&snapshots).Error
+}
+
+// getVersionedModuleSnapshots gets the snapshots of a version of the modules whose module type is versioned,
+// as modules keyed by their ID. Modules of a live module type are not included.
+func getVersionedModuleSnapshots(versionID uint) (map[uint]*models.Module, error) {
+	snapshots := make([]models.ModuleSnapshot, 0)
+	if result := database.Pg.
+		Where("version_id = ?", versionID).
+		Where("type IN (?)", database.Pg.Model(&models.ModuleType{}).Select("name").Where("behavior = ?", enums.MODULE_VERSIONED)).
+		Find(&snapshots); result.Error != nil {
+		return nil, result.Error
+	}
+
+	modules := make(map[uint]*models.Module, len(snapshots))
+	for i := range snapshots {
+		modules[snapshots[i].ModuleID] = snapshots[i].ToModule()
+	}
+
+	return modules, nil
+}
+
+// applyModuleSnapshotsToPage replaces the modules of the partial tree of a page with the snapshots of its version.
+func applyModuleSnapshotsToPage(page *models.Page) error {
+	modules, err := getVersionedModuleSnapshots(page.MenuItem.VersionID)
+	if err != nil || len(modules) == 0 {
+		return err
+	}
+
+	for i := range page.Partials {
+		applyModuleSnapshotsToPagePartialRows(page.Partials[i].Rows, modules)
+	}
+
+	return nil
+}
+
+// applyModuleSnapshotsToPagePartialRows replaces the modules of the columns of the rows and their nested rows.
+func applyModuleSnapshotsToPagePartialRows(rows []models.PagePartialRow, modules map[uint]*models.Module) {
+	for i := range rows {
+		for j := range rows[i].Columns {
+			column := &rows[i].Columns[j]
+			if module, ok := modules[column.ModuleID.V]; ok && column.ModuleID.Valid {
+				column.Module = module
+			}
+
+			applyModuleSnapshotsToPagePartialRows(column.PagePartialRows, modules)
+		}
+	}
+}
+
+// applyModuleSnapshotsToFooter replaces the modules of the footer rows of a version with the snapshots of the version.
+func applyModuleSnapshotsToFooter(versionID uint, rows []models.FooterRow) error {
+	modules, err := getVersionedModuleSnapshots(versionID)
+	if err != nil || len(modules) == 0 {
+		return err
+	}
+
+	applyModuleSnapshotsToFooterRows(rows, modules)
+
+	return nil
+}
+
+// applyModuleSnapshotsToFooterRows replaces the modules of the columns of the rows and their nested rows.
+func applyModuleSnapshotsToFooterRows(rows []models.FooterRow, modules map[uint]*models.Module) {
+	for i := range rows {
+		for j := range rows[i].Columns {
+			column := &rows[i].Columns[j]
+			if module, ok := modules[column.ModuleID.V]; ok && column.ModuleID.Valid {
+				column.Module = module
+			}
+
+			applyModuleSnapshotsToFooterRows(column.FooterRows, modules)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// moduleSettingsField is the field under which the errors of module settings are reported.
+const moduleSettingsField = "settings"
+
+// compiledModuleTypeSchemas holds the compiled schemas by module type and version.
+// A schema version never changes, so it is compiled once per process.
+var compiledModuleTypeSchemas sync.Map
+
+// GetModuleTypeSchema method to get a schema version of a module type, or the latest version when version is nil.
+func GetModuleTypeSchema(moduleType string, version *uint) (*models.ModuleTypeSchema, error) {
+	schema := &models.ModuleTypeSchema{}
+
+	query := database.Pg.Where("module_type_name = ?", moduleType)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	if result := query.Order("version DESC").Limit(1).Find(schema); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return schema, nil
+}
+
+// CreateModuleTypeSchema method to add a new schema version to a module type, with the migration
+// of the settings of the previous version. The schema and migration are not checked,
+// use CompileModuleTypeSchema and CheckModuleSettingsMigration first.
+func CreateModuleTypeSchema(moduleType string, request *requests.SetModuleTypeSchema) (*models.ModuleTypeSchema, error) {
+	migration := make([]models.ModuleSettingsTransform, len(request.Migration))
+	for i, transform := range request.Migration {
+		migration[i] = models.ModuleSettingsTransform{
+			Op:    enums.ModuleSettingsTransformOp(transform.Op),
+			From:  transform.From,
+			To:    transform.To,
+			Path:  transform.Path,
+			Value: transform.Value,
+		}
+	}
+
+	moduleTypeSchema := &models.ModuleTypeSchema{
+		ModuleTypeName: moduleType,
+		Schema:         datatypes.JSON(request.Schema),
+		Migration:      datatypes.NewJSONType(migration),
+	}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		// Lock the module type, so concurrent schemas get consecutive versions.
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&models.ModuleType{}, "name = ?", moduleType); result.Error != nil {
+			return result.Error
+		}
+
+		var latestVersion uint
+		if result := tx.Model(&models.ModuleTypeSchema{}).
+			Select("COALESCE(MAX(version), 0)").
+			Where("module_type_name = ?", moduleType).
+			Scan(&latestVersion); result.Error != nil {
+			return result.Error
+		}
+		moduleTypeSchema.Version = latestVersion + 1
+
+		if result := tx.Create(moduleTypeSchema); result.Error != nil {
+			return result.Error
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return moduleTypeSchema, nil
+}
+
+// CheckModuleSettingsMigration method to check that every step of a migration has the paths and value its operation needs.
+// The errors are keyed by the field of the step, e.g. "migration.0.from".
+func CheckModuleSettingsMigration(migration []requests.ModuleSettingsTransform) map[string]string {
+	fields := map[string]string{}
+
+	for i, transform := range migration {
+		required := map[string]bool{}
+		switch enums.ModuleSettingsTransformOp(transform.Op) {
+		case enums.MODULE_SETTINGS_RENAME, enums.MODULE_SETTINGS_COPY:
+			required["from"] = transform.From == ""
+			required["to"] = transform.To == ""
+		case enums.MODULE_SETTINGS_REMOVE:
+			required["path"] = transform.Path == ""
+		case enums.MODULE_SETTINGS_SET, enums.MODULE_SETTINGS_DEFAULT:
+			required["path"] = transform.Path == ""
+			required["value"] = len(transform.Value) == 0
+		}
+
+		for field, missing := range required {
+			if missing {
+				fields[fmt.Sprintf("migration.%d.%s", i, field)] = fmt.Sprintf("%s is required for %s", field, transform.Op)
+			}
+		}
+	}
+
+	return fields
+}
+
+// GetLatestModuleTypeSchemaVersion method to get the latest schema version of a module type, 0 when it has no schema.
+func GetLatestModuleTypeSchemaVersion(moduleType string) (uint, error) {
+	moduleTypeSchema, err := GetModuleTypeSchema(moduleType, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return moduleTypeSchema.Version, nil
+}
+
+// CompileModuleTypeSchema method to check that a schema is a valid JSON Schema.
+func CompileModuleTypeSchema(schema json.RawMessage) error {
+	_, err := compileModuleTypeSchema("urn:module-type:draft", schema)
+	return err
+}
+
+// ValidateModuleSettings method to validate module settings against the latest schema of the module type.
+// The errors are keyed by the path of the invalid setting, e.g. "settings.slides.0.title", in the shape of
+// the validator errors. A module type without a schema accepts any settings.
+func ValidateModuleSettings(moduleType string, settings json.RawMessage) (map[string]string, error) {
+	moduleTypeSchema, err := GetModuleTypeSchema(moduleType, nil)
+	if err != nil {
+		return nil, err
+	} else if moduleTypeSchema.Version == 0 {
+		return nil, nil
+	}
+
+	schema, err := getCompiledModuleTypeSchema(moduleTypeSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateModuleSettingsWithSchema(schema, settings)
+}
+
+// getCompiledModuleTypeSchema gets the compiled schema of a schema version. The creation time is part of the key,
+// so a schema version of a module type that took over the name of a renamed module type is compiled again.
+func getCompiledModuleTypeSchema(moduleTypeSchema *models.ModuleTypeSchema) (*jsonschema.Schema, error) {
+	url := fmt.Sprintf("urn:module-type:%s:%d", moduleTypeSchema.ModuleTypeName, moduleTypeSchema.Version)
+	key := fmt.Sprintf("%s:%d", url, moduleTypeSchema.CreatedAt.UnixNano())
+	if schema, ok := compiledModuleTypeSchemas.Load(key); ok {
+		return schema.(*jsonschema.Schema), nil
+	}
+
+	schema, err := compileModuleTypeSchema(url, json.RawMessage(moduleTypeSchema.Schema))
+	if err != nil {
+		return nil, err
+	}
+	compiledModuleTypeSchemas.Store(key, schema)
+
+	return schema, nil
+}
+
+// compileModuleTypeSchema compiles a schema under the url. Formats are asserted, so a "format" of the schema is enforced.
+func compileModuleTypeSchema(url string, schema json.RawMessage) (*jsonschema.Schema, error) {
+	document, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if err := compiler.AddResource(url, document); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(url)
+}
+
+// validateModuleSettingsWithSchema validates the settings and returns the errors of every invalid setting.
+func validateModuleSettingsWithSchema(schema *jsonschema.Schema, settings json.RawMessage) (map[string]string, error) {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(settings))
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if err := schema.Validate(instance); err == nil {
+		return nil, nil
+	} else if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+
+		field := getModuleSettingsField(unit.InstanceLocation)
+		if message, ok := fields[field]; ok {
+			fields[field] = message + "; " + unit.Error.String()
+		} else {
+			fields[field] = unit.Error.String()
+		}
+	}
+	if len(fields) == 0 {
+		fields[moduleSettingsField] = validationErr.Error()
+	}
+
+	return fields, nil
+}
+
+// getModuleSettingsField turns a JSON pointer into the settings into a field path, e.g. "/slides/0" into "settings.slides.0".
+func getModuleSettingsField(pointer string) string {
+	if pointer == "" {
+		return moduleSettingsField
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+
+	return moduleSettingsField + "." + strings.Join(tokens, ".")
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"strconv"
+
+	"github.com/ArnoldPMolenaar/api-utils/pagination"
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+)
+
+// IsModuleTypeNameAvailable method to check if a module type name is available, including deleted module types.
+func IsModuleTypeNameAvailable(name string, ignore *string) (bool, error) {
+	query := database.Pg.Unscoped().Limit(1)
+	var result *gorm.DB
+	if ignore != nil {
+		result = query.Find(&models.ModuleType{}, "name = ? AND name != ?", name, ignore)
+	} else {
+		result = query.Find(&models.ModuleType{}, "name = ?", name)
+	}
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 0, nil
+}
+
+// IsModuleTypeDeleted method to check if a module type is deleted.
+func IsModuleTypeDeleted(name string) (bool, error) {
+	if result := database.Pg.Unscoped().Limit(1).Find(&models.ModuleType{}, "name = ? AND deleted_at IS NOT NULL", name); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 1, nil
+	}
+}
+
+// GetModuleTypesNotAllowed method to get the module types that are not allowed in a footer or in a page.
+func GetModuleTypesNotAllowed(moduleTypes []string, inFooter bool) ([]string, error) {
+	notAllowed := make([]string, 0)
+	if len(moduleTypes) == 0 {
+		return notAllowed, nil
+	}
+
+	if result := database.Pg.Model(&models.ModuleType{}).
+		Where("name IN ? AND "+getModuleTypeAllowedColumn(inFooter)+" = ?", moduleTypes, false).
+		Order("name").
+		Pluck("name", &notAllowed); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notAllowed, nil
+}
+
+// GetModuleTypesNotAllowedByModuleIDs method to get the module types of the modules that are not allowed
+// in a footer or in a page.
+func GetModuleTypesNotAllowedByModuleIDs(moduleIDs []uint, inFooter bool) ([]string, error) {
+	notAllowed := make([]string, 0)
+	if len(moduleIDs) == 0 {
+		return notAllowed, nil
+	}
+
+	if result := database.Pg.Model(&models.ModuleType{}).
+		Distinct("module_types.name").
+		Joins("JOIN modules ON modules.type = module_types.name").
+		Where("modules.id IN ? AND module_types."+getModuleTypeAllowedColumn(inFooter)+" = ?", moduleIDs, false).
+		Order("module_types.name").
+		Pluck("module_types.name", &notAllowed); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notAllowed, nil
+}
+
+// GetModuleTypesNotAllowedByModuleNames method to get the module types of the modules of an app that are not allowed
+// in a footer or in a page.
+func GetModuleTypesNotAllowedByModuleNames(appName string, names []string, inFooter bool) ([]string, error) {
+	notAllowed := make([]string, 0)
+	if len(names) == 0 {
+		return notAllowed, nil
+	}
+
+	if result := database.Pg.Model(&models.ModuleType{}).
+		Distinct("module_types.name").
+		Joins("JOIN modules ON modules.type = module_types.name").
+		Where("modules.app_name = ? AND modules.name IN ? AND module_types."+getModuleTypeAllowedColumn(inFooter)+" = ?", appName, names, false).
+		Order("module_types.name").
+		Pluck("module_types.name", &notAllowed); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notAllowed, nil
+}
+
+// GetModuleTypes method to get paginated module types.
+func GetModuleTypes(c fiber.Ctx) (*pagination.Model, error) {
+	moduleTypes := make([]models.ModuleType, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"name":         true,
+		"display_name": true,
+		"behavior":     true,
+		"created_at":   true,
+		"updated_at":   true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Limit(limit).
+		Offset(offset)
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.ModuleType{})
+
+	if result := dbResult.Find(&moduleTypes); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedModuleTypes := make([]responses.ModuleType, 0)
+	for i := range moduleTypes {
+		paginatedModuleType := responses.ModuleType{}
+		paginatedModuleType.SetModuleType(&moduleTypes[i])
+		paginatedModuleTypes = append(paginatedModuleTypes, paginatedModuleType)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedModuleTypes)
+
+	return &paginationModel, nil
+}
+
+// GetModuleType method to get a module type by name.
+func GetModuleType(name string) (*models.ModuleType, error) {
+	moduleType := &models.ModuleType{}
+
+	if result := database.Pg.Limit(1).Find(moduleType, "name = ?", name); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return moduleType, nil
+}
+
+// CreateModuleType method to create a module type.
+func CreateModuleType(request *requests.CreateModuleType) (*models.ModuleType, error) {
+	moduleType := &models.ModuleType{
+		Name:            request.Name,
+		DisplayName:     request.DisplayName,
+		Description:     utils.NewNullString(request.Description),
+		Icon:            utils.NewNullString(request.Icon),
+		AllowedInFooter: request.AllowedInFooter == nil || *request.AllowedInFooter,
+		AllowedInPage:   request.AllowedInPage == nil || *request.AllowedInPage,
+		Behavior:        enums.MODULE_LIVE,
+	}
+	if request.Behavior != "" {
+		moduleType.Behavior = enums.ModuleBehavior(request.Behavior)
+	}
+
+	// Every field is inserted, so a false flag does not fall back to the default of the column.
+	if result := database.Pg.Select("*").Create(moduleType); result.Error != nil {
+		return nil, result.Error
+	}
+
+	_ = deleteAllModuleTypesLookupsFromCache()
+
+	return moduleType, nil
+}
+
+// UpdateModuleType method to update a module type. A rename cascades to the modules, schemas and app links
+// of the module type and is applied to the module snapshots, and the modules, pages and footers are deleted from the cache.
+func UpdateModuleType(oldModuleType *models.ModuleType, request *requests.UpdateModuleType) (*models.ModuleType, error) {
+	if oldModuleType == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	oldName := oldModuleType.Name
+	renamed := request.Name != oldName
+
+	oldModuleType.Name = request.Name
+	oldModuleType.DisplayName = request.DisplayName
+	oldModuleType.Description = utils.NewNullString(request.Description)
+	oldModuleType.Icon = utils.NewNullString(request.Icon)
+	oldModuleType.AllowedInFooter = request.AllowedInFooter
+	oldModuleType.AllowedInPage = request.AllowedInPage
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if renamed {
+			if txErr := tx.Model(&models.ModuleType{}).Where("name = ?", oldName).Update("name", request.Name).Error; txErr != nil {
+				return txErr
+			}
+
+			if txErr := tx.Model(&models.ModuleSnapshot{}).Where("type = ?", oldName).Update("type", request.Name).Error; txErr != nil {
+				return txErr
+			}
+
+			// The pages and footers serve the type of their modules.
+			if txErr := touchModuleTypeVersionsWithTx(tx, request.Name); txErr != nil {
+				return txErr
+			}
+		}
+
+		return tx.Model(oldModuleType).
+			Select("display_name", "description", "icon", "allowed_in_footer", "allowed_in_page", "updated_at").
+			Updates(oldModuleType).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = deleteAllModuleTypesLookupsFromCache()
+	if renamed {
+		_ = deleteAllModulesLookupsFromCache()
+		deleteModuleTypeUsagesFromCache(oldModuleType.Name)
+	}
+
+	return oldModuleType, nil
+}
+
+// SetModuleTypeBehavior method to set whether published pages render the live or the versioned settings
+// of the modules of a module type. The pages and footers that use these modules are deleted from the cache.
+func SetModuleTypeBehavior(moduleType *models.ModuleType, behavior enums.ModuleBehavior) (*models.ModuleType, error) {
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Model(moduleType).Update("behavior", behavior); result.Error != nil {
+			return result.Error
+		}
+
+		return touchModuleTypeVersionsWithTx(tx, moduleType.Name)
+	}); err != nil {
+		return nil, err
+	}
+	moduleType.Behavior = behavior
+
+	_ = deleteAllModuleTypesLookupsFromCache()
+	deleteModuleTypeUsagesFromCache(moduleType.Name)
+
+	return moduleType, nil
+}
+
+// DeleteModuleType method to delete a module type. Its modules are left out of the module lists until it is restored.
+func DeleteModuleType(name string) error {
+	err := database.Pg.Delete(&models.ModuleType{}, "name = ?", name).Error
+	if err == nil {
+		_ = deleteAllModuleTypesLookupsFromCache()
+		_ = deleteAllModulesLookupsFromCache()
+	}
+
+	return err
+}
+
+// RestoreModuleType method to restore a deleted module type.
+func RestoreModuleType(name string) error {
+	err := database.Pg.Unscoped().Model(&models.ModuleType{}).Where("name = ?", name).Update("deleted_at", nil).Error
+	if err == nil {
+		_ = deleteAllModuleTypesLookupsFromCache()
+		_ = deleteAllModulesLookupsFromCache()
+	}
+
+	return err
+}
+
+// getModuleTypeAllowedColumn returns the column of the flag that allows a module type in a footer or in a page.
+func getModuleTypeAllowedColumn(inFooter bool) string {
+	if inFooter {
+		return "allowed_in_footer"
+	}
+
+	return "allowed_in_page"
+}
+
+// deleteAllModuleTypesLookupsFromCache deletes the module type lookups of every app from the cache.
+func deleteAllModuleTypesLookupsFromCache() error {
+	return cache.DeleteByPrefix("modules:types")
+}
+
+// deleteAllModulesLookupsFromCache deletes the module lookups of every app from the cache.
+func deleteAllModulesLookupsFromCache() error {
+	return cache.DeleteByPrefix("modules:lookup:")
+}
+
+// deleteModuleTypeUsagesFromCache deletes the pages and footers that use a module of the module type from the cache.
+func deleteModuleTypeUsagesFromCache(name string) {
+	var moduleIDs []uint
+	if result := database.Pg.Unscoped().Model(&models.Module{}).Where("type = ?", name).Pluck("id", &moduleIDs); result.Error != nil {
+		return
+	}
+
+	for _, moduleID := range moduleIDs {
+		if usages, err := GetModuleUsages(moduleID); err == nil {
+			deleteModuleUsagesFromCache(usages)
+		}
+	}
+}
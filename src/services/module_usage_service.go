@@ -0,0 +1,68 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/models"
+)
+
+// GetModuleUsages method to get every page partial column and footer column of every version that uses a module.
+func GetModuleUsages(moduleID uint) (*responses.ModuleUsages, error) {
+	usages := &responses.ModuleUsages{
+		ModuleID: moduleID,
+		Pages:    make([]responses.ModulePageUsage, 0),
+		Footers:  make([]responses.ModuleFooterUsage, 0),
+	}
+
+	if result := database.Pg.Model(&models.PagePartialRowColumn{}).
+		Select("versions.id AS version_id, versions.name AS version_name, menu_items.id AS menu_item_id, page_partials.locale, "+
+			"COALESCE(pages.name, '') AS page_name, page_partials.id AS page_partial_id, page_partials.name AS page_partial_name, "+
+			"page_partial_rows.id AS row_id, page_partial_row_columns.id AS column_id").
+		Joins("JOIN page_partial_rows ON page_partial_rows.id = page_partial_row_columns.page_partial_row_id AND page_partial_rows.deleted_at IS NULL").
+		Joins("JOIN page_partials ON page_partials.id = page_partial_rows.page_partial_id AND page_partials.deleted_at IS NULL").
+		Joins("JOIN menu_items ON menu_items.id = page_partials.menu_item_id AND menu_items.deleted_at IS NULL").
+		Joins("JOIN versions ON versions.id = menu_items.version_id AND versions.deleted_at IS NULL").
+		Joins("LEFT JOIN pages ON pages.menu_item_id = page_partials.menu_item_id AND pages.locale = page_partials.locale AND pages.deleted_at IS NULL").
+		Where("page_partial_row_columns.module_id = ?", moduleID).
+		Order("versions.id, menu_items.id, page_partials.locale, page_partials.id, page_partial_rows.position, page_partial_row_columns.position").
+		Scan(&usages.Pages); result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result := database.Pg.Model(&models.FooterRowColumn{}).
+		Select("versions.id AS version_id, versions.name AS version_name, footer_rows.locale, footer_rows.id AS row_id, footer_row_columns.id AS column_id").
+		Joins("JOIN footer_rows ON footer_rows.id = footer_row_columns.footer_row_id AND footer_rows.deleted_at IS NULL").
+		Joins("JOIN versions ON versions.id = footer_rows.version_id AND versions.deleted_at IS NULL").
+		Where("footer_row_columns.module_id = ?", moduleID).
+		Order("versions.id, footer_rows.locale, footer_rows.position, footer_row_columns.position").
+		Scan(&usages.Footers); result.Error != nil {
+		return nil, result.Error
+	}
+
+	usages.Total = len(usages.Pages) + len(usages.Footers)
+
+	return usages, nil
+}
+
+// deleteModuleUsagesByIDFromCache loads the usages of a module and deletes the pages and footers that use it from the cache.
+func deleteModuleUsagesByIDFromCache(moduleID uint) error {
+	usages, err := GetModuleUsages(moduleID)
+	if err != nil {
+		return err
+	}
+
+	deleteModuleUsagesFromCache(usages)
+
+	return nil
+}
+
+// deleteModuleUsagesFromCache deletes the pages and footers that use a module from the cache.
+func deleteModuleUsagesFromCache(usages *responses.ModuleUsages) {
+	for i := range usages.Pages {
+		_ = deletePageFromCache(usages.Pages[i].MenuItemID, usages.Pages[i].Locale)
+	}
+
+	for i := range usages.Footers {
+		_ = deleteFooterFromCache(usages.Footers[i].VersionID, usages.Footers[i].Locale)
+	}
+}
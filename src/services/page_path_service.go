@@ -0,0 +1,321 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrPageSlugNotAvailable is returned when the slug of a page is taken by another page of the version and locale.
+var ErrPageSlugNotAvailable = errors.New("page slug is not available")
+
+const (
+	// pageSlugIndex is the unique index that keeps the slugs of the pages unique within a version and locale.
+	pageSlugIndex = "idx_page_slug"
+	// uniqueViolationCode is the SQLSTATE code of postgres for a unique violation.
+	uniqueViolationCode = "23505"
+)
+
+// IsPageSlugAvailable method to check if a slug is available for a page within the version and locale of its menu item.
+// The unique slug index decides in the end, so ErrPageSlugNotAvailable is still returned by concurrent updates.
+func IsPageSlugAvailable(menuItemID uint, locale, slug string) (bool, error) {
+	var count int64
+
+	if result := database.Pg.Model(&models.Page{}).
+		Where("version_id = (SELECT version_id FROM menu_items WHERE id = ?)", menuItemID).
+		Where("locale = ? AND slug = ? AND menu_item_id != ?", locale, slug, menuItemID).
+		Count(&count); result.Error != nil {
+		return false, result.Error
+	}
+
+	return count == 0, nil
+}
+
+// translatePageSlugError maps a violation of the unique slug index to ErrPageSlugNotAvailable.
+func translatePageSlugError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == pageSlugIndex {
+		return ErrPageSlugNotAvailable
+	}
+
+	return err
+}
+
+// GetPublishedPageByPath method to get the published page of an app by its full path, e.g. "/about/team".
+// The path is looked up along the locale fallback chain of the app. When the path does not exist
+// but was redirected in the requested locale, the redirect is returned instead.
+func GetPublishedPageByPath(appName, locale, path string) (*models.Page, *models.Redirect, error) {
+	page := &models.Page{}
+
+	version, err := GetPublishedVersionByAppName(appName)
+	if err != nil {
+		return nil, nil, err
+	} else if version.ID == 0 {
+		return page, nil, nil
+	}
+
+	chain, err := GetLocaleChain(appName, locale)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Walk the locale chain, as the path can exist in a fallback locale only.
+	path = normalizePagePath(path)
+	var paths map[string]uint
+	for i := range chain {
+		chainPaths, err := getPublishedPagePaths(version.ID, chain[i])
+		if err != nil {
+			return nil, nil, err
+		} else if i == 0 {
+			paths = chainPaths
+		}
+
+		if menuItemID, ok := chainPaths[path]; ok {
+			page, err = GetPublishedPage(menuItemID, chain[i])
+			return page, nil, err
+		}
+	}
+
+	redirect, err := resolveRedirect(appName, locale, path, paths)
+	if err != nil {
+		return nil, nil, err
+	} else if redirect != nil {
+		recordRedirectHit(redirect.ID, time.Now())
+	}
+
+	return page, redirect, nil
+}
+
+// GetPreviewPageByPath method to get the page of a version by its full path, whether the version is published or not.
+// Draft paths, disabled menu items and disabled pages are included, nothing is read from or written to the cache
+// and redirect hits are not recorded.
+func GetPreviewPageByPath(version *models.Version, locale, path string) (*models.Page, *models.Redirect, error) {
+	chain, err := GetLocaleChain(version.AppName, locale)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path = normalizePagePath(path)
+	var paths map[string]uint
+	for i := range chain {
+		chainPaths, _, err := buildPagePaths(database.Pg, version.ID, chain[i], false)
+		if err != nil {
+			return nil, nil, err
+		} else if i == 0 {
+			paths = chainPaths
+		}
+
+		if menuItemID, ok := chainPaths[path]; ok {
+			page, err := GetPreviewPageByLocales(menuItemID, []string{chain[i]})
+			return page, nil, err
+		}
+	}
+
+	redirect, err := resolveRedirect(version.AppName, locale, path, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &models.Page{}, redirect, nil
+}
+
+// normalizePagePath makes sure a path starts with a slash and has no trailing slash.
+func normalizePagePath(path string) string {
+	path = "/" + strings.Trim(strings.TrimSpace(path), "/")
+
+	return path
+}
+
+// getPublishedPagePaths gets the path lookup of the published content of a version and locale.
+func getPublishedPagePaths(versionID uint, locale string) (map[string]uint, error) {
+	if inCache, err := isPagePathsInCache(versionID); err != nil {
+		return nil, err
+	} else if inCache {
+		if cachePaths, err := getPagePathsFromCache(versionID, locale); err != nil {
+			return nil, err
+		} else if cachePaths != nil {
+			return cachePaths, nil
+		}
+	}
+
+	paths, _, err := buildPagePaths(database.Pg, versionID, locale, true)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = setPagePathsToCache(versionID, locale, paths)
+
+	return paths, nil
+}
+
+// buildPagePaths builds the full path of every menu item of a version in a locale from the menu item relation tree.
+// Each segment is the URL name of the page. When published is true, only enabled items with an enabled page
+// are included and a missing ancestor hides its whole subtree. Otherwise the menu item name is used for items
+// without a page. The first menu item wins when two items share a path.
+func buildPagePaths(db *gorm.DB, versionID uint, locale string, published bool) (map[string]uint, map[uint][]string, error) {
+	relations := make([]models.MenuItemRelation, 0)
+	relationQuery := db.Model(&models.MenuItemRelation{}).
+		Joins("JOIN menus ON menus.id = menu_item_relations.menu_id AND menus.deleted_at IS NULL").
+		Joins("JOIN menu_items ON menu_items.id = menu_item_relations.menu_item_child_id AND menu_items.deleted_at IS NULL").
+		Where("menus.version_id = ?", versionID)
+	if published {
+		relationQuery = relationQuery.Where("menu_items.enabled_at IS NOT NULL")
+	}
+	if result := relationQuery.
+		Order("menu_item_relations.menu_id ASC").
+		Order("menu_item_relations.menu_item_parent_id NULLS FIRST").
+		Order("menu_item_relations.position ASC").
+		Find(&relations); result.Error != nil {
+		return nil, nil, result.Error
+	}
+
+	pages := make([]models.Page, 0)
+	pageQuery := db.Model(&models.Page{}).
+		Select("pages.menu_item_id", "pages.locale", "pages.name", "pages.slug").
+		Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+		Where("menu_items.version_id = ? AND pages.locale = ?", versionID, locale)
+	if published {
+		pageQuery = pageQuery.Where("pages.enabled_at IS NOT NULL")
+	}
+	if result := pageQuery.Find(&pages); result.Error != nil {
+		return nil, nil, result.Error
+	}
+
+	segments := make(map[uint]string, len(pages))
+	for i := range pages {
+		segments[pages[i].MenuItemID] = pages[i].URLName()
+	}
+
+	if !published {
+		menuItems := make([]models.MenuItem, 0)
+		if result := db.Select("id", "name").Find(&menuItems, "version_id = ?", versionID); result.Error != nil {
+			return nil, nil, result.Error
+		}
+		for i := range menuItems {
+			if _, ok := segments[menuItems[i].ID]; !ok {
+				segments[menuItems[i].ID] = utils.URLEncode(menuItems[i].Name)
+			}
+		}
+	}
+
+	relationsByMenu := make(map[uint]map[uint]sql.Null[uint])
+	for i := range relations {
+		rel := relations[i]
+		if _, ok := relationsByMenu[rel.MenuID]; !ok {
+			relationsByMenu[rel.MenuID] = make(map[uint]sql.Null[uint])
+		}
+		relationsByMenu[rel.MenuID][rel.MenuItemChildID] = rel.MenuItemParentID
+	}
+
+	paths := make(map[string]uint)
+	pathsByMenuItem := make(map[uint][]string)
+	for i := range relations {
+		rel := relations[i]
+		parentByChild := relationsByMenu[rel.MenuID]
+
+		parts := make([]string, 0, 4)
+		current := rel.MenuItemChildID
+		complete := true
+		for depth := 0; depth <= len(relations); depth++ {
+			segment, ok := segments[current]
+			if !ok || segment == "" {
+				complete = false
+				break
+			}
+			parts = append(parts, segment)
+
+			parent, ok := parentByChild[current]
+			if !ok {
+				complete = false
+				break
+			} else if !parent.Valid {
+				break
+			}
+			current = parent.V
+		}
+
+		if !complete {
+			continue
+		}
+
+		for l, r := 0, len(parts)-1; l < r; l, r = l+1, r-1 {
+			parts[l], parts[r] = parts[r], parts[l]
+		}
+
+		path := "/" + strings.Join(parts, "/")
+		if _, ok := paths[path]; !ok {
+			paths[path] = rel.MenuItemChildID
+		}
+		pathsByMenuItem[rel.MenuItemChildID] = append(pathsByMenuItem[rel.MenuItemChildID], path)
+	}
+
+	return paths, pathsByMenuItem, nil
+}
+
+// getPagePathsCacheKey gets the key for the cache.
+func getPagePathsCacheKey(versionID uint) string {
+	return fmt.Sprintf("pages:paths:%d:locales", versionID)
+}
+
+// isPagePathsInCache checks if the page paths of a version exists in the cache.
+func isPagePathsInCache(versionID uint) (bool, error) {
+	return cache.Exists(getPagePathsCacheKey(versionID))
+}
+
+// getAllPagePathsFromCache gets the page paths of a version for all locales from the cache.
+func getAllPagePathsFromCache(versionID uint) (map[string]map[string]uint, error) {
+	versionPaths, err := cache.Get[map[string]map[string]uint](getPagePathsCacheKey(versionID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *versionPaths, nil
+}
+
+// getPagePathsFromCache gets the page paths of a version with a locale from the cache.
+func getPagePathsFromCache(versionID uint, locale string) (map[string]uint, error) {
+	versionPaths, err := getAllPagePathsFromCache(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if paths, ok := versionPaths[locale]; ok {
+		return paths, nil
+	}
+
+	return nil, nil
+}
+
+// setPagePathsToCache sets the page paths of a version with a locale to the cache.
+func setPagePathsToCache(versionID uint, locale string, paths map[string]uint) error {
+	var versionPaths map[string]map[string]uint
+
+	if inCache, err := isPagePathsInCache(versionID); err != nil {
+		return err
+	} else if inCache {
+		if versionPaths, err = getAllPagePathsFromCache(versionID); err != nil {
+			return err
+		}
+	}
+
+	if versionPaths == nil {
+		versionPaths = map[string]map[string]uint{}
+	}
+	versionPaths[locale] = paths
+
+	return cache.Set(getPagePathsCacheKey(versionID), versionPaths)
+}
+
+// deletePagePathsFromCache deletes existing page paths of a version for all locales from the cache.
+func deletePagePathsFromCache(versionID uint) error {
+	return cache.Delete(getPagePathsCacheKey(versionID))
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// GetPageRevisions method to get the revisions of a page without their content, the latest first.
+func GetPageRevisions(menuItemID uint, locale string) (*[]models.PageRevision, error) {
+	revisions := make([]models.PageRevision, 0)
+
+	if result := database.Pg.Omit("content").
+		Order("id DESC").
+		Find(&revisions, "menu_item_id = ? AND locale = ?", menuItemID, locale); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &revisions, nil
+}
+
+// GetPageRevisionByID method to get a revision of a page.
+func GetPageRevisionByID(menuItemID uint, locale string, revisionID uint) (*models.PageRevision, error) {
+	revision := &models.PageRevision{}
+
+	if result := database.Pg.Limit(1).Find(revision, "id = ? AND menu_item_id = ? AND locale = ?", revisionID, menuItemID, locale); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return revision, nil
+}
+
+// GetPageRevisionContent method to get the page, with its indexing and partial tree, as it was saved in a revision.
+func GetPageRevisionContent(revision *models.PageRevision) (*responses.Page, error) {
+	page := &responses.Page{}
+	if err := json.Unmarshal(revision.Content, page); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// DiffPageRevisions method to get the changes that turn the content of a revision into the content of another revision.
+// Paths are relative to the page, e.g. "/partials/hero/rows/0".
+func DiffPageRevisions(revision, otherRevision *models.PageRevision) ([]responses.VersionChange, error) {
+	page, err := GetPageRevisionContent(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	otherPage, err := GetPageRevisionContent(otherRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]responses.VersionChange, 0)
+	if err := diffPage(&changes, "", &page.Locale, page, otherPage); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// RestorePageRevision method to restore the content of a revision onto the page.
+// Page partials are matched by name: missing ones are created or restored and the ones the revision does not have are deleted.
+// The restore is saved as a new revision and the webhooks of the app are notified.
+func RestorePageRevision(page *models.Page, revision *models.PageRevision, actor string) (*models.Page, error) {
+	content, err := GetPageRevisionContent(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response and request of a page and its partials share their JSON fields.
+	updatePage := &requests.UpdatePage{}
+	if err := convertPageRevisionContent(content, updatePage); err != nil {
+		return nil, err
+	}
+
+	versionID, err := GetVersionIDByMenuItemID(page.MenuItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := GetVersionByID(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		// Only addresses of the published version are live, a draft registers its redirects when it is published.
+		var oldAddresses map[string]map[uint]*pageAddress
+		if version.PublishedAt.Valid {
+			var txErr error
+			if oldAddresses, txErr = getVersionPageAddressesWithTx(tx, versionID, page.Locale); txErr != nil {
+				return txErr
+			}
+		}
+
+		if _, txErr := UpdatePageWithTx(tx, page, updatePage, page.Revision); txErr != nil {
+			return txErr
+		}
+
+		partials := make([]models.PagePartial, 0)
+		if txErr := preloadPagePartialTree(tx).Find(&partials, "menu_item_id = ? AND locale = ?", page.MenuItemID, page.Locale).Error; txErr != nil {
+			return txErr
+		}
+		partialsByName := make(map[string]*models.PagePartial, len(partials))
+		for i := range partials {
+			partialsByName[partials[i].Name] = &partials[i]
+		}
+
+		for i := range content.Partials {
+			updatePartial := &requests.UpdatePagePartial{}
+			if txErr := convertPageRevisionContent(content.Partials[i], updatePartial); txErr != nil {
+				return txErr
+			}
+
+			partial, ok := partialsByName[updatePartial.Name]
+			if ok {
+				delete(partialsByName, updatePartial.Name)
+			} else {
+				// A deleted page partial keeps its name, so it is restored instead.
+				partial = &models.PagePartial{MenuItemID: page.MenuItemID, Locale: page.Locale, Name: updatePartial.Name}
+				if txErr := tx.Unscoped().Model(&models.PagePartial{}).
+					Where("menu_item_id = ? AND locale = ? AND name = ?", partial.MenuItemID, partial.Locale, partial.Name).
+					Update("deleted_at", nil).Error; txErr != nil {
+					return txErr
+				}
+				if txErr := tx.FirstOrCreate(partial, partial).Error; txErr != nil {
+					return txErr
+				}
+				if txErr := preloadPagePartialTree(tx).First(partial, partial.ID).Error; txErr != nil {
+					return txErr
+				}
+			}
+
+			if _, txErr := UpdatePagePartialWithTx(tx, partial, updatePartial, partial.Revision); txErr != nil {
+				return txErr
+			}
+		}
+
+		for _, partial := range partialsByName {
+			if txErr := tx.Delete(&models.PagePartial{}, partial.ID).Error; txErr != nil {
+				return txErr
+			}
+		}
+
+		if version.PublishedAt.Valid {
+			newAddresses, txErr := getVersionPageAddressesWithTx(tx, versionID, page.Locale)
+			if txErr != nil {
+				return txErr
+			}
+
+			if txErr := registerVersionAddressRedirectsWithTx(tx, version.AppName, oldAddresses, newAddresses, nil); txErr != nil {
+				return txErr
+			}
+		}
+
+		if txErr := enqueuePageEventWithTx(tx, enums.WEBHOOK_PAGE_UPDATED, page.MenuItemID, page.Locale); txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor, enums.AUDIT_RESTORE)
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = deleteVersionMenusFromCache(versionID, page.Locale)
+	_ = deletePageFromCache(page.MenuItemID, page.Locale)
+
+	restoredPage := &models.Page{}
+	if result := database.Pg.
+		Preload("Indexing").
+		Preload("Partials", func(db *gorm.DB) *gorm.DB {
+			return preloadPagePartialTree(db).Order("name ASC")
+		}).
+		First(restoredPage, "menu_item_id = ? AND locale = ?", page.MenuItemID, page.Locale); result.Error != nil {
+		return nil, result.Error
+	}
+
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_UPDATED, restoredPage.MenuItemID, restoredPage.Locale, 0, restoredPage.Revision)
+
+	return restoredPage, nil
+}
+
+// GetPageRevisionRetention method to get the number of revisions that is kept per page. Zero keeps every revision.
+func GetPageRevisionRetention() int {
+	retention, err := strconv.Atoi(os.Getenv("PAGE_REVISION_RETENTION"))
+	if err != nil || retention < 0 {
+		return 0
+	}
+
+	return retention
+}
+
+// pageRevisionKey identifies the page of a revision.
+type pageRevisionKey struct {
+	MenuItemID uint
+	Locale     string
+}
+
+// dedupePageRevisionKeys removes the repeated keys, keeping the order of their first occurrence.
+func dedupePageRevisionKeys(keys []pageRevisionKey) []pageRevisionKey {
+	seen := make(map[pageRevisionKey]struct{}, len(keys))
+	result := make([]pageRevisionKey, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
+
+// ensurePageRevisionWithTx saves the current content of a page without revisions as its first revision,
+// so the content from before the first recorded change can be restored.
+// It performs no transaction lifecycle control and no cache side effects.
+func ensurePageRevisionWithTx(tx *gorm.DB, menuItemID uint, locale, actor string) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	if result := tx.Limit(1).Find(&models.PageRevision{}, "menu_item_id = ? AND locale = ?", menuItemID, locale); result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected > 0 {
+		return nil
+	}
+
+	return createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_CREATE)
+}
+
+// createPageRevisionWithTx saves the current content of a page as a revision of the action and prunes the revisions beyond the retention.
+// It performs no transaction lifecycle control and no cache side effects.
+func createPageRevisionWithTx(tx *gorm.DB, menuItemID uint, locale, actor string, action enums.AuditAction) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	page := &models.Page{}
+	if err := tx.
+		Preload("Indexing", func(db *gorm.DB) *gorm.DB {
+			return db.Order("option ASC")
+		}).
+		Preload("Partials", func(db *gorm.DB) *gorm.DB {
+			return preloadPagePartialTree(db).Order("name ASC")
+		}).
+		First(page, "menu_item_id = ? AND locale = ?", menuItemID, locale).Error; err != nil {
+		return err
+	}
+
+	response := responses.Page{}
+	response.SetPage(page)
+	content, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Create(&models.PageRevision{MenuItemID: menuItemID, Locale: locale, Actor: actor, Action: action, Content: content}).Error; err != nil {
+		return err
+	}
+
+	// Every change of a page is recorded as a revision, so the content of its version changed as well.
+	if err := touchMenuItemVersionWithTx(tx, menuItemID); err != nil {
+		return err
+	}
+
+	retention := GetPageRevisionRetention()
+	if retention == 0 {
+		return nil
+	}
+
+	return tx.
+		Where("menu_item_id = ? AND locale = ?", menuItemID, locale).
+		Where("id NOT IN (?)", tx.Model(&models.PageRevision{}).
+			Select("id").
+			Where("menu_item_id = ? AND locale = ?", menuItemID, locale).
+			Order("id DESC").
+			Limit(retention)).
+		Delete(&models.PageRevision{}).Error
+}
+
+// convertPageRevisionContent converts the content of a revision into a request with the same JSON fields.
+func convertPageRevisionContent(content, request any) error {
+	value, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(value, request)
+}
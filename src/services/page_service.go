@@ -6,14 +6,10 @@ import (
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/enums"
 	"api-page/main/src/models"
-	"context"
-	"encoding/json"
 	"fmt"
-	"os"
-	"time"
+	"strings"
 
 	"github.com/ArnoldPMolenaar/api-utils/utils"
-	"github.com/valkey-io/valkey-go"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -121,57 +117,109 @@ func GetPage(menuItemID uint, locale string) (*models.Page, error) {
 
 // GetPublishedPage retrieves a Page by MenuItemID and Locale only if it is enabled (EnabledAt is not null) and not deleted.
 func GetPublishedPage(menuItemID uint, locale string) (*models.Page, error) {
-	page := &models.Page{}
+	return GetPublishedPageByLocales(menuItemID, []string{locale})
+}
 
-	if inCache, err := isPageInCache(menuItemID, locale); err != nil {
+// GetPublishedPageWithFallback retrieves the published Page of a MenuItem in a locale. When the locale
+// has no published Page, the locale fallback chain of the app is walked. The served locale is the Locale of the Page.
+func GetPublishedPageWithFallback(menuItemID uint, locale string) (*models.Page, error) {
+	chain, err := GetLocaleChainByMenuItemID(menuItemID, locale)
+	if err != nil {
 		return nil, err
-	} else if inCache {
-		if cachePage, err := getPageFromCache(menuItemID, locale); err != nil {
-			return nil, err
-		} else if cachePage != nil {
-			page = cachePage
-		}
 	}
 
-	if page.MenuItemID == 0 {
-		if isPageDeleted, err := IsPageDeleted(menuItemID, locale); err != nil {
-			return nil, err
-		} else if isPageDeleted {
-			// If the page is deleted, we should not retrieve it.
-			return page, nil
-		}
+	return GetPublishedPageByLocales(menuItemID, chain)
+}
+
+// GetPublishedPageByLocales retrieves the published Page of a MenuItem in the first locale of the chain that has one.
+// The Page is cached under the whole chain and concurrent misses load it once. Modules of a versioned module type
+// are served as they were when the version was published.
+func GetPublishedPageByLocales(menuItemID uint, locales []string) (*models.Page, error) {
+	chain := strings.Join(locales, localeChainSeparator)
 
-		if result := database.Pg.
-			Preload("Indexing").
-			Preload("Partials", preloadPagePartialTree).
-			Find(page, "menu_item_id = ? AND locale = ? AND enabled_at IS NOT NULL", menuItemID, locale); result.Error != nil {
-			return nil, result.Error
+	page, err := cache.Fetch(getPageCacheKey(menuItemID, chain), func() (*models.Page, error) {
+		_ = setLocaleChainKeyToCache(getPageCacheKey(menuItemID, ""), locales)
+
+		for _, locale := range locales {
+			localePage, err := findPage(menuItemID, locale, true)
+			if err != nil {
+				return nil, err
+			} else if localePage.MenuItemID != 0 {
+				if err := applyModuleSnapshotsToPage(localePage); err != nil {
+					return nil, err
+				}
+
+				return localePage, nil
+			}
 		}
 
-		if !page.EnabledAt.Valid {
-			// If the page is not enabled, we should not retrieve it.
-			return &models.Page{}, nil
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if page == nil {
+		page = &models.Page{}
+	}
+
+	return page, nil
+}
+
+// GetPreviewPageByLocales retrieves the Page of a MenuItem in the first locale of the chain that has one,
+// whether it is enabled or not. The Page is never read from or written to the cache.
+func GetPreviewPageByLocales(menuItemID uint, locales []string) (*models.Page, error) {
+	for _, locale := range locales {
+		page, err := findPage(menuItemID, locale, false)
+		if err != nil || page.MenuItemID != 0 {
+			return page, err
 		}
+	}
+
+	return &models.Page{}, nil
+}
 
-		if len(page.Indexing) == 0 {
-			menuIndexing := make([]models.MenuItemIndexing, 0)
+// findPage finds the not deleted Page of a MenuItem in a locale, including its partial tree and the Version
+// of the MenuItem, so the cached Page holds the moment its content changed.
+// When published is true, only an enabled Page is found. Without page indexing, the indexing of the MenuItem is used.
+func findPage(menuItemID uint, locale string, published bool) (*models.Page, error) {
+	page := &models.Page{}
 
-			if result := database.Pg.Find(&menuIndexing, "menu_item_id = ?", menuItemID); result.Error != nil {
-				return nil, result.Error
-			}
+	if isPageDeleted, err := IsPageDeleted(menuItemID, locale); err != nil {
+		return nil, err
+	} else if isPageDeleted {
+		// If the page is deleted, we should not retrieve it.
+		return page, nil
+	}
 
-			for i := range menuIndexing {
-				pageIndexing := models.PageIndexing{
-					MenuItemID: menuIndexing[i].MenuItemID,
-					Locale:     locale,
-					Option:     menuIndexing[i].Option,
-					Value:      menuIndexing[i].Value,
-				}
-				page.Indexing = append(page.Indexing, pageIndexing)
+	query := database.Pg.
+		Preload("Indexing").
+		Preload("MenuItem.Indexing").
+		Preload("MenuItem.Version").
+		Preload("Partials", preloadPagePartialTree).
+		Where("menu_item_id = ? AND locale = ?", menuItemID, locale)
+	if published {
+		query = query.Where("enabled_at IS NOT NULL")
+	}
+	if result := query.Find(page); result.Error != nil {
+		return nil, result.Error
+	}
+
+	if published && !page.EnabledAt.Valid {
+		// If the page is not enabled, we should not retrieve it.
+		return &models.Page{}, nil
+	}
+
+	if len(page.Indexing) == 0 {
+		menuIndexing := page.MenuItem.Indexing
+
+		for i := range menuIndexing {
+			pageIndexing := models.PageIndexing{
+				MenuItemID: menuIndexing[i].MenuItemID,
+				Locale:     locale,
+				Option:     menuIndexing[i].Option,
+				Value:      menuIndexing[i].Value,
 			}
+			page.Indexing = append(page.Indexing, pageIndexing)
 		}
-
-		_ = setPageToCache(menuItemID, locale, page)
 	}
 
 	return page, nil
@@ -237,45 +285,103 @@ func GetPartialByID(partialID uint) (*models.PagePartial, error) {
 }
 
 // CreatePagePartial creates a new PagePartial for the given Page using data from the CreatePagePartial request.
-func CreatePagePartial(page *models.Page, request *requests.CreatePagePartial) (*models.PagePartial, error) {
+// The saved page is recorded as a revision of the actor.
+func CreatePagePartial(page *models.Page, request *requests.CreatePagePartial, actor string) (*models.PagePartial, error) {
 	partial := &models.PagePartial{
 		MenuItemID: page.MenuItemID,
 		Locale:     page.Locale,
 		Name:       request.Name,
 	}
 
-	if err := database.Pg.FirstOrCreate(partial, partial).Error; err != nil {
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := ensurePageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor); txErr != nil {
+			return txErr
+		}
+
+		if txErr := tx.FirstOrCreate(partial, partial).Error; txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor, enums.AUDIT_UPDATE)
+	}); err != nil {
 		return nil, err
 	}
 
 	_ = deletePageFromCache(page.MenuItemID, page.Locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_PARTIAL_CREATED, page.MenuItemID, page.Locale, partial.ID, partial.Revision)
 
 	return partial, nil
 }
 
 // UpdatePage updates the given Page with data from the UpdatePage request.
-func UpdatePage(page *models.Page, request *requests.UpdatePage) (*models.Page, error) {
-	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
-		_, txErr := UpdatePageWithTx(tx, page, request)
-		return txErr
-	}); err != nil {
+// When the path of the page changes, a redirect from the old path is registered.
+// The saved page is recorded as a revision of the actor and the webhooks of the app are notified.
+// ErrRevisionConflict is returned when the page is no longer at the given revision.
+func UpdatePage(page *models.Page, request *requests.UpdatePage, revision uint64, actor string) (*models.Page, error) {
+	versionID, err := GetVersionIDByMenuItemID(page.MenuItemID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Invalidate cache for version menus related to this page.
-	versionID, err := GetVersionIDByMenuItemID(page.MenuItemID)
+	version, err := GetVersionByID(versionID)
 	if err != nil {
 		return nil, err
 	}
+
+	// Only addresses of the published version are live, a draft registers its redirects when it is published.
+	addressChanged := version.PublishedAt.Valid && (page.Name != request.Name ||
+		page.Slug != utils.NewNullString(request.Slug) ||
+		page.UrlEnabled != request.UrlEnabled ||
+		page.Url != utils.NewNullString(request.Url))
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := ensurePageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor); txErr != nil {
+			return txErr
+		}
+
+		var oldAddresses map[string]map[uint]*pageAddress
+		if addressChanged {
+			var txErr error
+			if oldAddresses, txErr = getVersionPageAddressesWithTx(tx, versionID, page.Locale); txErr != nil {
+				return txErr
+			}
+		}
+
+		if _, txErr := UpdatePageWithTx(tx, page, request, revision); txErr != nil {
+			return txErr
+		}
+
+		if addressChanged {
+			newAddresses, txErr := getVersionPageAddressesWithTx(tx, versionID, page.Locale)
+			if txErr != nil {
+				return txErr
+			}
+
+			if txErr := registerVersionAddressRedirectsWithTx(tx, version.AppName, oldAddresses, newAddresses, nil); txErr != nil {
+				return txErr
+			}
+		}
+
+		if txErr := enqueuePageEventWithTx(tx, enums.WEBHOOK_PAGE_UPDATED, page.MenuItemID, page.Locale); txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor, enums.AUDIT_UPDATE)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache for version menus related to this page.
 	_ = deleteVersionMenusFromCache(versionID, page.Locale)
 	_ = deletePageFromCache(page.MenuItemID, page.Locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_UPDATED, page.MenuItemID, page.Locale, 0, page.Revision)
 
 	return page, nil
 }
 
-// UpdatePageWithTx updates the given Page using the provided transaction.
+// UpdatePageWithTx updates the given Page at the expected revision using the provided transaction.
 // It performs no transaction lifecycle control and no cache side effects.
-func UpdatePageWithTx(tx *gorm.DB, page *models.Page, request *requests.UpdatePage) (*models.Page, error) {
+func UpdatePageWithTx(tx *gorm.DB, page *models.Page, request *requests.UpdatePage, revision uint64) (*models.Page, error) {
 	if tx == nil {
 		return nil, gorm.ErrInvalidDB
 	}
@@ -287,7 +393,12 @@ func UpdatePageWithTx(tx *gorm.DB, page *models.Page, request *requests.UpdatePa
 		return nil, gorm.ErrRecordNotFound
 	}
 
+	if err := incrementRevisionWithTx(tx, page, revision); err != nil {
+		return nil, err
+	}
+
 	page.Name = request.Name
+	page.Slug = utils.NewNullString(request.Slug)
 	page.NewTabEnabled = request.NewTabEnabled
 	page.UrlEnabled = request.UrlEnabled
 	page.Plugin = utils.NewNullString(request.Plugin)
@@ -301,7 +412,7 @@ func UpdatePageWithTx(tx *gorm.DB, page *models.Page, request *requests.UpdatePa
 	if err := tx.Model(&page).
 		Clauses(clause.Returning{Columns: []clause.Column{{Name: "updated_at"}}}).
 		Updates(page).Error; err != nil {
-		return nil, err
+		return nil, translatePageSlugError(err)
 	}
 
 	existing := make([]models.PageIndexing, 0)
@@ -340,27 +451,44 @@ func UpdatePageWithTx(tx *gorm.DB, page *models.Page, request *requests.UpdatePa
 }
 
 // UpdatePagePartial updates the given PagePartial and its associated rows and columns
-// based on the data provided in the UpdatePagePartial request.
-func UpdatePagePartial(menuItemID uint, locale string, partial *models.PagePartial, dtoPartial *requests.UpdatePagePartial) (*models.PagePartial, error) {
+// based on the data provided in the UpdatePagePartial request. The saved page is recorded as a revision of the actor
+// and the webhooks of the app are notified. ErrRevisionConflict is returned when the partial is no longer at the given revision.
+func UpdatePagePartial(menuItemID uint, locale string, partial *models.PagePartial, dtoPartial *requests.UpdatePagePartial, revision uint64, actor string) (*models.PagePartial, error) {
 	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
-		_, txErr := UpdatePagePartialWithTx(tx, partial, dtoPartial)
-		return txErr
+		if txErr := ensurePageRevisionWithTx(tx, menuItemID, locale, actor); txErr != nil {
+			return txErr
+		}
+
+		if _, txErr := UpdatePagePartialWithTx(tx, partial, dtoPartial, revision); txErr != nil {
+			return txErr
+		}
+
+		if txErr := enqueuePageEventWithTx(tx, enums.WEBHOOK_PAGE_UPDATED, menuItemID, locale); txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_UPDATE)
 	}); err != nil {
 		return nil, err
 	}
 
 	_ = deletePageFromCache(menuItemID, locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_PARTIAL_UPDATED, menuItemID, locale, partial.ID, partial.Revision)
 
 	return partial, nil
 }
 
-// UpdatePagePartialWithTx updates the given PagePartial using the provided transaction.
+// UpdatePagePartialWithTx updates the given PagePartial at the expected revision using the provided transaction.
 // It performs no transaction lifecycle control and no cache side effects.
-func UpdatePagePartialWithTx(tx *gorm.DB, partial *models.PagePartial, dtoPartial *requests.UpdatePagePartial) (*models.PagePartial, error) {
+func UpdatePagePartialWithTx(tx *gorm.DB, partial *models.PagePartial, dtoPartial *requests.UpdatePagePartial, revision uint64) (*models.PagePartial, error) {
 	if tx == nil {
 		return nil, gorm.ErrInvalidDB
 	}
 
+	if err := incrementRevisionWithTx(tx, partial, revision); err != nil {
+		return nil, err
+	}
+
 	partial.Name = dtoPartial.Name
 
 	if err := tx.Model(partial).
@@ -564,45 +692,114 @@ func syncPagePartialColumns(tx *gorm.DB, partialID, rowID uint, existingColumns
 	return desiredColumns, nil
 }
 
-// DeletePage method to delete a page.
-func DeletePage(menuItemID uint, locale string) error {
-	err := database.Pg.Delete(&models.Page{MenuItemID: menuItemID, Locale: locale}).Error
-	if err == nil {
-		_ = deletePageFromCache(menuItemID, locale)
+// DeletePage method to delete a page and notify the webhooks of the app.
+// The content of the deleted page is recorded as a revision of the actor.
+func DeletePage(menuItemID uint, locale, actor string) error {
+	versionID, err := GetVersionIDByMenuItemID(menuItemID)
+	if err != nil {
+		return err
+	}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := ensurePageRevisionWithTx(tx, menuItemID, locale, actor); txErr != nil {
+			return txErr
+		}
+
+		if txErr := createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_DELETE); txErr != nil {
+			return txErr
+		}
+
+		if txErr := tx.Delete(&models.Page{MenuItemID: menuItemID, Locale: locale}).Error; txErr != nil {
+			return txErr
+		}
+
+		return enqueuePageEventWithTx(tx, enums.WEBHOOK_PAGE_DELETED, menuItemID, locale)
+	}); err != nil {
+		return err
 	}
 
-	return err
+	// Invalidate cache for version menus related to this page.
+	_ = deleteVersionMenusFromCache(versionID, locale)
+	_ = deletePageFromCache(menuItemID, locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_DELETED, menuItemID, locale, 0, 0)
+
+	return nil
 }
 
-// DeletePagePartial method to delete a page partial by its ID.
-func DeletePagePartial(menuItemID uint, locale string, partialID uint) error {
-	err := database.Pg.Delete(&models.PagePartial{}, partialID).Error
-	if err == nil {
-		_ = deletePageFromCache(menuItemID, locale)
+// DeletePagePartial method to delete a page partial by its ID. The saved page is recorded as a revision of the actor.
+func DeletePagePartial(menuItemID uint, locale string, partialID uint, actor string) error {
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := ensurePageRevisionWithTx(tx, menuItemID, locale, actor); txErr != nil {
+			return txErr
+		}
+
+		if txErr := tx.Delete(&models.PagePartial{}, partialID).Error; txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_UPDATE)
+	}); err != nil {
+		return err
 	}
 
-	return err
+	_ = deletePageFromCache(menuItemID, locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_PARTIAL_DELETED, menuItemID, locale, partialID, 0)
+
+	return nil
 }
 
-// RestorePage method to restore a deleted page.
-func RestorePage(menuItemID uint, locale string) error {
-	return database.Pg.Unscoped().
-		Model(&models.Page{}).
-		Where("menu_item_id = ? AND locale = ?", menuItemID, locale).
-		Update("deleted_at", nil).Error
+// RestorePage method to restore a deleted page. The restored page is recorded as a revision of the actor.
+// ErrPageSlugNotAvailable is returned when its slug was taken by another page in the meantime.
+func RestorePage(menuItemID uint, locale, actor string) error {
+	versionID, err := GetVersionIDByMenuItemID(menuItemID)
+	if err != nil {
+		return err
+	}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Unscoped().
+			Model(&models.Page{}).
+			Where("menu_item_id = ? AND locale = ?", menuItemID, locale).
+			Update("deleted_at", nil).Error; txErr != nil {
+			return translatePageSlugError(txErr)
+		}
+
+		return createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_RESTORE)
+	}); err != nil {
+		return err
+	}
+
+	// Invalidate cache for version menus related to this page.
+	_ = deleteVersionMenusFromCache(versionID, locale)
+	_ = deletePageFromCache(menuItemID, locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_RESTORED, menuItemID, locale, 0, 0)
+
+	return nil
 }
 
-// RestorePagePartial method to restore a deleted page partial by its ID.
-func RestorePagePartial(menuItemID uint, locale string, partialID uint) error {
-	err := database.Pg.Unscoped().
-		Model(&models.PagePartial{}).
-		Where("id = ?", partialID).
-		Update("deleted_at", nil).Error
-	if err == nil {
-		_ = deletePageFromCache(menuItemID, locale)
+// RestorePagePartial method to restore a deleted page partial by its ID. The saved page is recorded as a revision of the actor.
+func RestorePagePartial(menuItemID uint, locale string, partialID uint, actor string) error {
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := ensurePageRevisionWithTx(tx, menuItemID, locale, actor); txErr != nil {
+			return txErr
+		}
+
+		if txErr := tx.Unscoped().
+			Model(&models.PagePartial{}).
+			Where("id = ?", partialID).
+			Update("deleted_at", nil).Error; txErr != nil {
+			return txErr
+		}
+
+		return createPageRevisionWithTx(tx, menuItemID, locale, actor, enums.AUDIT_UPDATE)
+	}); err != nil {
+		return err
 	}
 
-	return err
+	_ = deletePageFromCache(menuItemID, locale)
+	_ = publishPageContentEvent(enums.CONTENT_PAGE_PARTIAL_RESTORED, menuItemID, locale, partialID, 0)
+
+	return nil
 }
 
 // shared row ordering for root partial rows; exclude rows linked as nested column rows.
@@ -641,75 +838,14 @@ func ensureRowColumnRelation(tx *gorm.DB, columnID, rowID uint) error {
 	return tx.Where("column_id = ? AND row_id = ?", columnID, rowID).FirstOrCreate(&relation).Error
 }
 
-// getPageCacheKey gets the key for the cache.
+// getPageCacheKey gets the key for the cache. The locale can be a locale chain.
 func getPageCacheKey(menuItemID uint, locale string) string {
 	return fmt.Sprintf("pages:%d:%s", menuItemID, locale)
 }
 
-// isPageInCache checks if the page exists in the cache.
-func isPageInCache(menuItemID uint, locale string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getPageCacheKey(menuItemID, locale)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
-}
-
-// getPageFromCache gets the page from the cache.
-func getPageFromCache(menuItemID uint, locale string) (*models.Page, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getPageCacheKey(menuItemID, locale)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var page models.Page
-	if err := json.Unmarshal([]byte(value), &page); err != nil {
-		return nil, err
-	}
-
-	return &page, nil
-}
-
-// setPageToCache sets the page to the cache.
-func setPageToCache(menuItemID uint, locale string, page *models.Page) error {
-	value, err := json.Marshal(page)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getPageCacheKey(menuItemID, locale)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
-}
-
-// deletePageFromCache deletes existing page from the cache.
+// deletePageFromCache deletes existing page from the cache, including every locale chain that contains the locale.
 func deletePageFromCache(menuItemID uint, locale string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getPageCacheKey(menuItemID, locale)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return deleteLocaleChainKeysFromCache(getPageCacheKey(menuItemID, ""), locale)
 }
 
 // deletePagesFromCacheByMenuItemID deletes all pages related to a menu item from the cache by the menu item ID.
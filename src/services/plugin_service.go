@@ -4,16 +4,19 @@ import (
 	"api-page/main/src/cache"
 	"api-page/main/src/database"
 	"api-page/main/src/models"
-	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
-	"time"
-
-	"github.com/valkey-io/valkey-go"
 )
 
+// IsPluginTypeNotAvailable method to check if a plugin type is available.
+func IsPluginTypeNotAvailable(pluginType string) (bool, error) {
+	if result := database.Pg.Limit(1).Find(&models.PluginType{}, "name = ?", pluginType); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 0, nil
+	}
+}
+
 // GetPluginTypeLookup method to get a lookup of plugin types.
 func GetPluginTypeLookup(appName *string) (*[]models.PluginType, error) {
 	pluginTypes := make([]models.PluginType, 0)
@@ -58,66 +61,20 @@ func getPluginTypesLookupCacheKey(appName *string) string {
 
 // isPluginTypesLookupInCache checks if plugin types exist in the cache.
 func isPluginTypesLookupInCache(appName *string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getPluginTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+	return cache.Exists(getPluginTypesLookupCacheKey(appName))
 }
 
 // getPluginTypesLookupFromCache gets plugin types from the cache.
 func getPluginTypesLookupFromCache(appName *string) (*[]models.PluginType, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getPluginTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var pluginTypes []models.PluginType
-	if err := json.Unmarshal([]byte(value), &pluginTypes); err != nil {
-		return nil, err
-	}
-
-	return &pluginTypes, nil
+	return cache.Get[[]models.PluginType](getPluginTypesLookupCacheKey(appName))
 }
 
 // setPluginTypesLookupToCache sets plugin types to the cache.
 func setPluginTypesLookupToCache(appName *string, pluginTypes *[]models.PluginType) error {
-	value, err := json.Marshal(pluginTypes)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getPluginTypesLookupCacheKey(appName)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Set(getPluginTypesLookupCacheKey(appName), pluginTypes)
 }
 
 // deletePluginTypesLookupFromCache deletes existing plugin type lookups from cache.
 func deletePluginTypesLookupFromCache(appName *string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getPluginTypesLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Delete(getPluginTypesLookupCacheKey(appName))
 }
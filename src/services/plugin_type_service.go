@@ -0,0 +1,202 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/models"
+	"strconv"
+
+	"github.com/ArnoldPMolenaar/api-utils/pagination"
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+)
+
+// IsPluginTypeNameAvailable method to check if a plugin type name is available, including deleted plugin types.
+func IsPluginTypeNameAvailable(name string, ignore *string) (bool, error) {
+	query := database.Pg.Unscoped().Limit(1)
+	var result *gorm.DB
+	if ignore != nil {
+		result = query.Find(&models.PluginType{}, "name = ? AND name != ?", name, ignore)
+	} else {
+		result = query.Find(&models.PluginType{}, "name = ?", name)
+	}
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 0, nil
+}
+
+// IsPluginTypeDeleted method to check if a plugin type is deleted.
+func IsPluginTypeDeleted(name string) (bool, error) {
+	if result := database.Pg.Unscoped().Limit(1).Find(&models.PluginType{}, "name = ? AND deleted_at IS NOT NULL", name); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 1, nil
+	}
+}
+
+// GetPluginTypes method to get paginated plugin types.
+func GetPluginTypes(c fiber.Ctx) (*pagination.Model, error) {
+	pluginTypes := make([]models.PluginType, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"name":         true,
+		"display_name": true,
+		"created_at":   true,
+		"updated_at":   true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Limit(limit).
+		Offset(offset)
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.PluginType{})
+
+	if result := dbResult.Find(&pluginTypes); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedPluginTypes := make([]responses.PluginType, 0)
+	for i := range pluginTypes {
+		paginatedPluginType := responses.PluginType{}
+		paginatedPluginType.SetPluginType(&pluginTypes[i])
+		paginatedPluginTypes = append(paginatedPluginTypes, paginatedPluginType)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedPluginTypes)
+
+	return &paginationModel, nil
+}
+
+// GetPluginType method to get a plugin type by name.
+func GetPluginType(name string) (*models.PluginType, error) {
+	pluginType := &models.PluginType{}
+
+	if result := database.Pg.Limit(1).Find(pluginType, "name = ?", name); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return pluginType, nil
+}
+
+// CreatePluginType method to create a plugin type.
+func CreatePluginType(request *requests.CreatePluginType) (*models.PluginType, error) {
+	pluginType := &models.PluginType{
+		Name:        request.Name,
+		DisplayName: request.DisplayName,
+		Description: utils.NewNullString(request.Description),
+		Icon:        utils.NewNullString(request.Icon),
+	}
+
+	if result := database.Pg.Create(pluginType); result.Error != nil {
+		return nil, result.Error
+	}
+
+	_ = deleteAllPluginTypesLookupsFromCache()
+
+	return pluginType, nil
+}
+
+// UpdatePluginType method to update a plugin type. A rename cascades to the pages and app links of the plugin type,
+// and the pages that use it are deleted from the cache.
+func UpdatePluginType(oldPluginType *models.PluginType, request *requests.UpdatePluginType) (*models.PluginType, error) {
+	if oldPluginType == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	oldName := oldPluginType.Name
+	renamed := request.Name != oldName
+
+	oldPluginType.Name = request.Name
+	oldPluginType.DisplayName = request.DisplayName
+	oldPluginType.Description = utils.NewNullString(request.Description)
+	oldPluginType.Icon = utils.NewNullString(request.Icon)
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if renamed {
+			if txErr := tx.Model(&models.PluginType{}).Where("name = ?", oldName).Update("name", request.Name).Error; txErr != nil {
+				return txErr
+			}
+
+			// The pages serve the name of their plugin type.
+			if txErr := touchPluginTypeVersionsWithTx(tx, request.Name); txErr != nil {
+				return txErr
+			}
+		}
+
+		return tx.Model(oldPluginType).
+			Select("display_name", "description", "icon", "updated_at").
+			Updates(oldPluginType).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = deleteAllPluginTypesLookupsFromCache()
+	if renamed {
+		_ = deletePluginTypePagesFromCache(oldPluginType.Name)
+	}
+
+	return oldPluginType, nil
+}
+
+// DeletePluginType method to delete a plugin type.
+func DeletePluginType(name string) error {
+	err := database.Pg.Delete(&models.PluginType{}, "name = ?", name).Error
+	if err == nil {
+		_ = deleteAllPluginTypesLookupsFromCache()
+	}
+
+	return err
+}
+
+// RestorePluginType method to restore a deleted plugin type.
+func RestorePluginType(name string) error {
+	err := database.Pg.Unscoped().Model(&models.PluginType{}).Where("name = ?", name).Update("deleted_at", nil).Error
+	if err == nil {
+		_ = deleteAllPluginTypesLookupsFromCache()
+	}
+
+	return err
+}
+
+// deleteAllPluginTypesLookupsFromCache deletes the plugin type lookups of every app from the cache.
+func deleteAllPluginTypesLookupsFromCache() error {
+	return cache.DeleteByPrefix("plugins:types")
+}
+
+// deletePluginTypePagesFromCache deletes the pages that use the plugin type from the cache.
+func deletePluginTypePagesFromCache(name string) error {
+	pages := make([]models.Page, 0)
+	if result := database.Pg.Unscoped().Model(&models.Page{}).Select("menu_item_id", "locale").Where("plugin = ?", name).Find(&pages); result.Error != nil {
+		return result.Error
+	}
+
+	for i := range pages {
+		if err := deletePageFromCache(pages[i].MenuItemID, pages[i].Locale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"api-page/main/src/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrPreviewTokenSecretNotConfigured is returned when the PREVIEW_TOKEN_SECRET is not set.
+var ErrPreviewTokenSecretNotConfigured = errors.New("preview token secret is not configured")
+
+// ErrPreviewTokenInvalid is returned when a preview token can not be verified.
+var ErrPreviewTokenInvalid = errors.New("preview token is invalid")
+
+// PreviewToken is the signed claim of a preview token. It grants access to the content of one
+// version, optionally limited to one locale, until it expires.
+type PreviewToken struct {
+	VersionID uint    `json:"versionId"`
+	AppName   string  `json:"appName"`
+	Locale    *string `json:"locale,omitempty"`
+	ExpiresAt int64   `json:"exp"`
+}
+
+// CreatePreviewToken method to create a signed preview token for a version.
+// The token is signed with the PREVIEW_TOKEN_SECRET and is valid until expiresAt.
+func CreatePreviewToken(version *models.Version, locale *string, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("PREVIEW_TOKEN_SECRET")
+	if secret == "" {
+		return "", ErrPreviewTokenSecretNotConfigured
+	}
+
+	claim, err := json.Marshal(PreviewToken{
+		VersionID: version.ID,
+		AppName:   version.AppName,
+		Locale:    locale,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(claim)
+
+	return payload + "." + signPreviewToken(secret, payload), nil
+}
+
+// ParsePreviewToken method to verify a preview token and get its claim.
+// An error is returned when the signature does not match or the token has expired.
+func ParsePreviewToken(token string) (*PreviewToken, error) {
+	secret := os.Getenv("PREVIEW_TOKEN_SECRET")
+	if secret == "" {
+		return nil, ErrPreviewTokenSecretNotConfigured
+	}
+
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signPreviewToken(secret, payload))) {
+		return nil, ErrPreviewTokenInvalid
+	}
+
+	claim, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	previewToken := &PreviewToken{}
+	if err := json.Unmarshal(claim, previewToken); err != nil {
+		return nil, err
+	} else if time.Now().Unix() >= previewToken.ExpiresAt {
+		return nil, errors.New("preview token has expired")
+	}
+
+	return previewToken, nil
+}
+
+// GetPreviewTokenExpiration method to get the default lifetime of a preview token.
+func GetPreviewTokenExpiration() (time.Duration, error) {
+	return time.ParseDuration(os.Getenv("PREVIEW_TOKEN_EXPIRATION"))
+}
+
+// AllowsLocale checks if the token grants access to a locale.
+func (t *PreviewToken) AllowsLocale(locale string) bool {
+	return t.Locale == nil || *t.Locale == locale
+}
+
+// signPreviewToken signs the payload of a preview token with HMAC-SHA256.
+func signPreviewToken(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,530 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/models"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/pagination"
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxRedirectHops limits how many redirects are followed when resolving a path.
+const maxRedirectHops = 5
+
+// redirectMove describes an address that moved from one path to another.
+type redirectMove struct {
+	From string
+	To   string
+}
+
+// redirectHit is the buffered hit count of a redirect and the moment of its last hit.
+type redirectHit struct {
+	Count     uint64
+	LastHitAt time.Time
+}
+
+// redirectHits buffers the hits of redirects until FlushRedirectHits writes them to the database.
+var (
+	redirectHitsMu sync.Mutex
+	redirectHits   = make(map[uint]*redirectHit)
+)
+
+// pageAddress holds every address a page can be reached on within a locale.
+type pageAddress struct {
+	Paths []string
+	Url   string
+}
+
+// IsRedirectAvailable method to check if a from path is available for a redirect.
+// Deleted redirects are included, because they still hold the path.
+func IsRedirectAvailable(appName, locale, fromPath string, ignoreID *uint) (bool, error) {
+	query := database.Pg.Unscoped().Limit(1).Where("app_name = ? AND locale = ? AND from_path = ?", appName, locale, fromPath)
+	if ignoreID != nil {
+		query = query.Where("id != ?", *ignoreID)
+	}
+
+	if result := query.Find(&models.Redirect{}); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 0, nil
+	}
+}
+
+// IsRedirectDeleted method to check if a redirect is deleted.
+func IsRedirectDeleted(redirectID uint) (bool, error) {
+	if result := database.Pg.Unscoped().Limit(1).Find(&models.Redirect{}, "id = ? AND deleted_at IS NOT NULL", redirectID); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 1, nil
+	}
+}
+
+// GetRedirects method to get paginated redirects.
+func GetRedirects(c fiber.Ctx) (*pagination.Model, error) {
+	redirects := make([]models.Redirect, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"id":          true,
+		"app_name":    true,
+		"locale":      true,
+		"from_path":   true,
+		"to_path":     true,
+		"status_code": true,
+		"automatic":   true,
+		"hits":        true,
+		"last_hit_at": true,
+		"created_at":  true,
+		"updated_at":  true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Limit(limit).
+		Offset(offset)
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.Redirect{})
+
+	if result := dbResult.Find(&redirects); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedRedirects := make([]responses.PaginatedRedirect, 0)
+	for i := range redirects {
+		paginatedRedirect := responses.PaginatedRedirect{}
+		paginatedRedirect.SetPaginatedRedirect(&redirects[i])
+		paginatedRedirects = append(paginatedRedirects, paginatedRedirect)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedRedirects)
+
+	return &paginationModel, nil
+}
+
+// GetRedirectByID method to get a redirect by ID.
+func GetRedirectByID(redirectID uint) (*models.Redirect, error) {
+	redirect := &models.Redirect{}
+
+	if result := database.Pg.Limit(1).Find(redirect, "id = ?", redirectID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return redirect, nil
+}
+
+// GetPublishedRedirect method to resolve the redirect of a path against the published version of an app.
+// No redirect is returned when the path is a live page, and every resolved redirect counts as a hit.
+// Hits are buffered in memory, so the lookup does not write to the database.
+func GetPublishedRedirect(appName, locale, path string) (*models.Redirect, error) {
+	paths := map[string]uint{}
+
+	version, err := GetPublishedVersionByAppName(appName)
+	if err != nil {
+		return nil, err
+	} else if version.ID != 0 {
+		if paths, err = getPublishedPagePaths(version.ID, locale); err != nil {
+			return nil, err
+		}
+	}
+
+	path = normalizePagePath(path)
+	if _, ok := paths[path]; ok {
+		return nil, nil
+	}
+
+	redirect, err := resolveRedirect(appName, locale, path, paths)
+	if err != nil {
+		return nil, err
+	} else if redirect != nil {
+		recordRedirectHit(redirect.ID, time.Now())
+	}
+
+	return redirect, nil
+}
+
+// CreateRedirect method to create a redirect.
+func CreateRedirect(request *requests.CreateRedirect) (*models.Redirect, error) {
+	redirect := &models.Redirect{
+		AppName:    request.AppName,
+		Locale:     request.Locale,
+		FromPath:   normalizePagePath(request.FromPath),
+		ToPath:     strings.TrimSpace(request.ToPath),
+		StatusCode: request.StatusCode,
+	}
+
+	if result := database.Pg.Create(redirect); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return redirect, nil
+}
+
+// UpdateRedirect method to update a redirect.
+// A manually updated redirect is no longer considered automatic.
+func UpdateRedirect(oldRedirect *models.Redirect, request *requests.UpdateRedirect) (*models.Redirect, error) {
+	if oldRedirect == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	oldRedirect.Locale = request.Locale
+	oldRedirect.FromPath = normalizePagePath(request.FromPath)
+	oldRedirect.ToPath = strings.TrimSpace(request.ToPath)
+	oldRedirect.StatusCode = request.StatusCode
+	oldRedirect.Automatic = false
+
+	if result := database.Pg.Save(oldRedirect); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return oldRedirect, nil
+}
+
+// DeleteRedirect method to delete a redirect.
+func DeleteRedirect(redirectID uint) error {
+	return database.Pg.Delete(&models.Redirect{}, redirectID).Error
+}
+
+// RestoreRedirect method to restore a deleted redirect.
+func RestoreRedirect(redirectID uint) error {
+	return database.Pg.Unscoped().Model(&models.Redirect{}).Where("id = ?", redirectID).Update("deleted_at", nil).Error
+}
+
+// FlushRedirectHits method to add the buffered hits to the hit counters of the redirects and get the number of redirects updated.
+// The update time is left alone, so hits do not count as an edit. Hits that could not be written are buffered again.
+func FlushRedirectHits() (int, error) {
+	redirectHitsMu.Lock()
+	hits := redirectHits
+	redirectHits = make(map[uint]*redirectHit)
+	redirectHitsMu.Unlock()
+
+	flushed := 0
+	for redirectID, hit := range hits {
+		if err := database.Pg.Model(&models.Redirect{}).Where("id = ?", redirectID).UpdateColumns(map[string]any{
+			"hits":        gorm.Expr("hits + ?", hit.Count),
+			"last_hit_at": gorm.Expr("GREATEST(last_hit_at, ?)", sql.NullTime{Time: hit.LastHitAt, Valid: true}),
+		}).Error; err != nil {
+			for redirectID, hit := range hits {
+				recordRedirectHits(redirectID, hit)
+			}
+			return flushed, err
+		}
+
+		delete(hits, redirectID)
+		flushed++
+	}
+
+	return flushed, nil
+}
+
+// recordRedirectHit buffers a hit of a redirect.
+func recordRedirectHit(redirectID uint, hitAt time.Time) {
+	recordRedirectHits(redirectID, &redirectHit{Count: 1, LastHitAt: hitAt})
+}
+
+// recordRedirectHits adds hits to the buffered hits of a redirect.
+func recordRedirectHits(redirectID uint, hit *redirectHit) {
+	redirectHitsMu.Lock()
+	defer redirectHitsMu.Unlock()
+
+	buffered, ok := redirectHits[redirectID]
+	if !ok {
+		redirectHits[redirectID] = &redirectHit{Count: hit.Count, LastHitAt: hit.LastHitAt}
+		return
+	}
+
+	buffered.Count += hit.Count
+	if hit.LastHitAt.After(buffered.LastHitAt) {
+		buffered.LastHitAt = hit.LastHitAt
+	}
+}
+
+// getVersionPageAddressesWithTx gets the published addresses of every menu item of a version as stored in the transaction,
+// for the given locales or every locale with pages. Only site-relative URLs are included, because external URLs are not served by the site.
+func getVersionPageAddressesWithTx(tx *gorm.DB, versionID uint, locales ...string) (map[string]map[uint]*pageAddress, error) {
+	if len(locales) == 0 {
+		if result := tx.Model(&models.Page{}).
+			Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+			Where("menu_items.version_id = ?", versionID).
+			Distinct().
+			Pluck("pages.locale", &locales); result.Error != nil {
+			return nil, result.Error
+		}
+	}
+
+	addresses := make(map[string]map[uint]*pageAddress, len(locales))
+	for i := range locales {
+		_, pathsByMenuItem, err := buildPagePaths(tx, versionID, locales[i], true)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses[locales[i]] = make(map[uint]*pageAddress, len(pathsByMenuItem))
+		for menuItemID, paths := range pathsByMenuItem {
+			addresses[locales[i]][menuItemID] = &pageAddress{Paths: paths}
+		}
+
+		pages := make([]models.Page, 0)
+		if result := tx.Model(&models.Page{}).
+			Select("pages.menu_item_id", "pages.url").
+			Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+			Where("menu_items.version_id = ? AND pages.locale = ?", versionID, locales[i]).
+			Where("pages.enabled_at IS NOT NULL AND pages.url_enabled AND pages.url LIKE ?", "/%").
+			Find(&pages); result.Error != nil {
+			return nil, result.Error
+		}
+
+		for j := range pages {
+			if address, ok := addresses[locales[i]][pages[j].MenuItemID]; ok {
+				address.Url = strings.TrimSpace(pages[j].Url.String)
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+// getPageAddressMoves compares two addresses of a page and returns every address that moved.
+// Anchors are left out, because the fragment of a URL never reaches the server.
+func getPageAddressMoves(oldAddress, newAddress *pageAddress) []redirectMove {
+	moves := getPathMoves(oldAddress.Paths, newAddress.Paths)
+
+	// A site-relative link target moves to the new link target or to the page itself.
+	if oldAddress.Url != "" {
+		to := newAddress.Url
+		if to == "" && len(newAddress.Paths) > 0 {
+			to = newAddress.Paths[0]
+		}
+		if to != "" {
+			moves = append(moves, redirectMove{From: oldAddress.Url, To: to})
+		}
+	}
+
+	return moves
+}
+
+// registerVersionAddressRedirectsWithTx stores a redirect for every published address that moved between two snapshots
+// of getVersionPageAddressesWithTx. The menu items of the old snapshot are matched by the pairs, or by their ID when
+// no pairs are given. Menu items that were removed or are no longer published get no redirect.
+// It performs no transaction lifecycle control and no cache side effects.
+func registerVersionAddressRedirectsWithTx(tx *gorm.DB, appName string, oldAddresses, newAddresses map[string]map[uint]*pageAddress, pairs map[uint]uint) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	for locale, oldAddressesByMenuItem := range oldAddresses {
+		moves := make([]redirectMove, 0)
+		for menuItemID, oldAddress := range oldAddressesByMenuItem {
+			newMenuItemID := menuItemID
+			if pairs != nil {
+				var ok bool
+				if newMenuItemID, ok = pairs[menuItemID]; !ok {
+					continue
+				}
+			}
+
+			if newAddress, ok := newAddresses[locale][newMenuItemID]; ok {
+				moves = append(moves, getPageAddressMoves(oldAddress, newAddress)...)
+			}
+		}
+
+		if err := registerRedirectsWithTx(tx, appName, locale, moves); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerPublicationRedirectsWithTx stores a redirect for every published address of the previously published version
+// that moved in the version that is published now. Menu items are matched the way DiffVersions matches them.
+// It performs no transaction lifecycle control and no cache side effects.
+func registerPublicationRedirectsWithTx(tx *gorm.DB, appName string, previousVersionID, versionID uint) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	previous := &versionContent{}
+	if err := previous.loadMenus(tx, previousVersionID); err != nil {
+		return err
+	}
+
+	current := &versionContent{}
+	if err := current.loadMenus(tx, versionID); err != nil {
+		return err
+	}
+
+	changes := make([]responses.VersionChange, 0)
+	pairs, err := diffMenuItems(&changes, previous, current)
+	if err != nil {
+		return err
+	}
+
+	oldAddresses, err := getVersionPageAddressesWithTx(tx, previousVersionID)
+	if err != nil {
+		return err
+	}
+
+	newAddresses, err := getVersionPageAddressesWithTx(tx, versionID)
+	if err != nil {
+		return err
+	}
+
+	return registerVersionAddressRedirectsWithTx(tx, appName, oldAddresses, newAddresses, pairs)
+}
+
+// getPathMoves pairs the old and new paths of a menu item by position and returns the ones that changed.
+func getPathMoves(oldPaths, newPaths []string) []redirectMove {
+	moves := make([]redirectMove, 0)
+	for i := range oldPaths {
+		if i >= len(newPaths) {
+			break
+		}
+		moves = append(moves, redirectMove{From: oldPaths[i], To: newPaths[i]})
+	}
+
+	return moves
+}
+
+// registerRedirectsWithTx stores an automatic permanent redirect for every address that moved.
+// Automatic redirects pointing at an old address are moved to the new address, and automatic redirects from
+// an address that is in use again are removed. Manual redirects are left alone, including the one from an old
+// address. It performs no transaction lifecycle control and no cache side effects.
+func registerRedirectsWithTx(tx *gorm.DB, appName, locale string, moves []redirectMove) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	for i := range moves {
+		if moves[i].From == moves[i].To {
+			continue
+		}
+
+		if err := tx.Unscoped().
+			Where("app_name = ? AND locale = ? AND from_path = ? AND automatic", appName, locale, moves[i].To).
+			Delete(&models.Redirect{}).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range moves {
+		if moves[i].From == moves[i].To {
+			continue
+		}
+
+		if err := tx.Model(&models.Redirect{}).
+			Where("app_name = ? AND locale = ? AND to_path = ? AND automatic", appName, locale, moves[i].From).
+			Update("to_path", moves[i].To).Error; err != nil {
+			return err
+		}
+
+		redirect := &models.Redirect{
+			AppName:    appName,
+			Locale:     locale,
+			FromPath:   moves[i].From,
+			ToPath:     moves[i].To,
+			StatusCode: http.StatusMovedPermanently,
+			Automatic:  true,
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "app_name"}, {Name: "locale"}, {Name: "from_path"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"to_path":     moves[i].To,
+				"status_code": http.StatusMovedPermanently,
+				"automatic":   true,
+				"deleted_at":  nil,
+				"updated_at":  time.Now(),
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "redirects.automatic"}}},
+		}).Create(redirect).Error; err != nil {
+			return err
+		}
+	}
+
+	// A moved redirect can end up pointing at itself.
+	return tx.Unscoped().
+		Where("app_name = ? AND locale = ? AND from_path = to_path AND automatic", appName, locale).
+		Delete(&models.Redirect{}).Error
+}
+
+// resolveRedirect finds the redirect for a path. When no redirect exists for the full path,
+// the longest redirected parent path is used and the remaining segments are appended.
+// Chains are followed until a live path or an external URL is reached, and the chain
+// is only permanent when every redirect in it is permanent.
+func resolveRedirect(appName, locale, path string, paths map[string]uint) (*models.Redirect, error) {
+	var resolved *models.Redirect
+	current := path
+
+	for hop := 0; hop < maxRedirectHops; hop++ {
+		redirect, err := findRedirectByPathPrefix(appName, locale, current)
+		if err != nil {
+			return nil, err
+		} else if redirect == nil {
+			break
+		}
+
+		if resolved == nil {
+			resolved = redirect
+		} else if redirect.StatusCode != http.StatusMovedPermanently {
+			resolved.StatusCode = redirect.StatusCode
+		}
+		resolved.ToPath = redirect.ToPath
+		current = redirect.ToPath
+
+		if _, ok := paths[current]; ok || !strings.HasPrefix(current, "/") {
+			break
+		}
+	}
+
+	return resolved, nil
+}
+
+// findRedirectByPathPrefix finds the redirect of a path or of its longest parent path.
+// The returned redirect has its target rewritten to include the remaining segments.
+func findRedirectByPathPrefix(appName, locale, path string) (*models.Redirect, error) {
+	prefix := path
+	remainder := ""
+
+	for prefix != "" {
+		redirect := &models.Redirect{}
+		if result := database.Pg.Limit(1).Find(redirect, "app_name = ? AND locale = ? AND from_path = ?", appName, locale, prefix); result.Error != nil {
+			return nil, result.Error
+		} else if result.RowsAffected == 1 {
+			redirect.ToPath += remainder
+			return redirect, nil
+		}
+
+		index := strings.LastIndex(prefix, "/")
+		if index <= 0 {
+			break
+		}
+		remainder = prefix[index:] + remainder
+		prefix = prefix[:index]
+	}
+
+	return nil, nil
+}
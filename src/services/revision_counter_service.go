@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRevisionConflict is returned when a model was changed by someone else since the expected revision was read.
+var ErrRevisionConflict = errors.New("revision conflict")
+
+// incrementRevisionWithTx increments the revision counter of a model by its primary key when the model is still at
+// the expected revision, and sets the new revision on the model. The row stays locked until the transaction ends,
+// so it must run before the model is written. ErrRevisionConflict is returned when the revision has moved on.
+// It performs no transaction lifecycle control and no cache side effects.
+func incrementRevisionWithTx(tx *gorm.DB, model any, revision uint64) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	result := tx.Model(model).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "revision"}}}).
+		Where("revision = ?", revision).
+		UpdateColumn("revision", gorm.Expr("revision + 1"))
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrRevisionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"strings"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+)
+
+// robotsFlags are the directives without a value, in the order they are rendered.
+var robotsFlags = []enums.Indexing{
+	enums.NOARCHIVE,
+	enums.NOCACHE,
+	enums.NOSNIPPET,
+	enums.NOIMAGEINDEX,
+	enums.NOTRANSLATE,
+	enums.NOAI,
+	enums.NOIMAGEAI,
+	enums.NOODP,
+	enums.NOYDIR,
+}
+
+// robotsValues are the directives with a value, in the order they are rendered.
+var robotsValues = []enums.Indexing{
+	enums.MAX_SNIPPET,
+	enums.MAX_IMAGE_PREVIEW,
+	enums.MAX_VIDEO_PREVIEW,
+	enums.UNAVAILABLE_AFTER,
+}
+
+// robotsLevel holds the directives of one level (menu item or page).
+// A nil toggle means the level does not set it.
+type robotsLevel struct {
+	Index    *bool
+	Follow   *bool
+	Embedded *bool
+	Flags    map[enums.Indexing]bool
+	Values   map[enums.Indexing]string
+}
+
+// GetPageRobots method to get the robots directive string of a page.
+// The indexing of the menu item is merged with the indexing of the page, where the page wins
+// on conflicting directives such as index and noindex. Within one level the most restrictive
+// directive wins. Directives with an invalid value are ignored.
+func GetPageRobots(page *models.Page) string {
+	menuItemIndexing := make([]models.PageIndexing, len(page.MenuItem.Indexing))
+	for i := range page.MenuItem.Indexing {
+		menuItemIndexing[i] = models.PageIndexing{
+			Option: page.MenuItem.Indexing[i].Option,
+			Value:  page.MenuItem.Indexing[i].Value,
+		}
+	}
+
+	return buildRobots(newRobotsLevel(menuItemIndexing), newRobotsLevel(page.Indexing))
+}
+
+// isRobotsIndexable checks if the merged levels, from low to high priority, allow indexing.
+func isRobotsIndexable(levels ...*robotsLevel) bool {
+	index := true
+	for _, level := range levels {
+		if level.Index != nil {
+			index = *level.Index
+		}
+	}
+
+	return index
+}
+
+// newRobotsLevel collects the directives of one level.
+func newRobotsLevel(indexing []models.PageIndexing) *robotsLevel {
+	level := &robotsLevel{
+		Flags:  make(map[enums.Indexing]bool),
+		Values: make(map[enums.Indexing]string),
+	}
+
+	for i := range indexing {
+		option := indexing[i].Option
+		value := utils.PtrFromNullString(indexing[i].Value)
+		if !option.IsValid() || !option.IsValidValue(value) {
+			continue
+		}
+
+		switch option {
+		case enums.ALL:
+			setRobotsToggle(&level.Index, true)
+			setRobotsToggle(&level.Follow, true)
+		case enums.NONE:
+			setRobotsToggle(&level.Index, false)
+			setRobotsToggle(&level.Follow, false)
+		case enums.INDEX, enums.NOINDEX:
+			setRobotsToggle(&level.Index, option == enums.INDEX)
+		case enums.FOLLOW, enums.NOFOLLOW:
+			setRobotsToggle(&level.Follow, option == enums.FOLLOW)
+		case enums.INDEXIFEMBEDDED, enums.NOINDEXIFEMBEDDED:
+			setRobotsToggle(&level.Embedded, option == enums.INDEXIFEMBEDDED)
+		default:
+			if option.HasValue() {
+				level.Values[option] = strings.TrimSpace(*value)
+			} else {
+				level.Flags[option] = true
+			}
+		}
+	}
+
+	return level
+}
+
+// setRobotsToggle sets a toggle of a level. A restrictive (false) toggle is never overwritten.
+func setRobotsToggle(toggle **bool, value bool) {
+	if *toggle != nil && !**toggle {
+		return
+	}
+
+	*toggle = &value
+}
+
+// buildRobots merges levels from low to high priority and renders the directive string.
+func buildRobots(levels ...*robotsLevel) string {
+	index, follow := true, true
+	var embedded *bool
+	flags := make(map[enums.Indexing]bool)
+	values := make(map[enums.Indexing]string)
+
+	for _, level := range levels {
+		if level.Index != nil {
+			index = *level.Index
+		}
+		if level.Follow != nil {
+			follow = *level.Follow
+		}
+		if level.Embedded != nil {
+			embedded = level.Embedded
+		}
+		for option := range level.Flags {
+			flags[option] = true
+		}
+		for option, value := range level.Values {
+			values[option] = value
+		}
+	}
+
+	// nosnippet is stricter than any snippet length.
+	if flags[enums.NOSNIPPET] {
+		delete(values, enums.MAX_SNIPPET)
+	}
+
+	directives := make([]string, 0, 4)
+	if index {
+		directives = append(directives, enums.INDEX.String())
+	} else {
+		directives = append(directives, enums.NOINDEX.String())
+	}
+	if follow {
+		directives = append(directives, enums.FOLLOW.String())
+	} else {
+		directives = append(directives, enums.NOFOLLOW.String())
+	}
+	if embedded != nil {
+		if *embedded {
+			directives = append(directives, enums.INDEXIFEMBEDDED.String())
+		} else {
+			directives = append(directives, enums.NOINDEXIFEMBEDDED.String())
+		}
+	}
+	for _, option := range robotsFlags {
+		if flags[option] {
+			directives = append(directives, option.String())
+		}
+	}
+	for _, option := range robotsValues {
+		if value, ok := values[option]; ok {
+			if option == enums.UNAVAILABLE_AFTER {
+				directives = append(directives, option.String()+": "+value)
+			} else {
+				directives = append(directives, option.String()+":"+value)
+			}
+		}
+	}
+
+	return strings.Join(directives, ", ")
+}
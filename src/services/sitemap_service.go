@@ -0,0 +1,194 @@
+package services
+
+import (
+	"api-page/main/src/cache"
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/models"
+	"fmt"
+	"time"
+)
+
+// sitemapPage is a page of a menu item that is listed in the sitemap of its locale.
+type sitemapPage struct {
+	Path    string
+	LastMod time.Time
+}
+
+// GetSitemaps method to get the sitemap URLs of a published version for every locale.
+// The URLs hold site-relative paths. Link pages and pages whose merged robots directives
+// of the menu item and the page do not allow indexing are left out. Each URL lists the
+// same menu item in the other locales as hreflang alternates.
+func GetSitemaps(versionID uint) (map[string][]responses.SitemapURL, error) {
+	if inCache, err := isSitemapsInCache(versionID); err != nil {
+		return nil, err
+	} else if inCache {
+		if cacheSitemaps, err := getSitemapsFromCache(versionID); err != nil {
+			return nil, err
+		} else if cacheSitemaps != nil {
+			return cacheSitemaps, nil
+		}
+	}
+
+	sitemaps, err := buildSitemaps(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = setSitemapsToCache(versionID, sitemaps)
+
+	return sitemaps, nil
+}
+
+// buildSitemaps builds the sitemap URLs of a published version for every locale with enabled pages.
+func buildSitemaps(versionID uint) (map[string][]responses.SitemapURL, error) {
+	var locales []string
+	if result := database.Pg.Model(&models.Page{}).
+		Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+		Where("menu_items.version_id = ? AND pages.enabled_at IS NOT NULL", versionID).
+		Distinct().
+		Order("pages.locale").
+		Pluck("pages.locale", &locales); result.Error != nil {
+		return nil, result.Error
+	}
+
+	menuItemIndexing := make([]models.MenuItemIndexing, 0)
+	if result := database.Pg.Model(&models.MenuItemIndexing{}).
+		Joins("JOIN menu_items ON menu_items.id = menu_item_indexings.menu_item_id").
+		Where("menu_items.version_id = ?", versionID).
+		Find(&menuItemIndexing); result.Error != nil {
+		return nil, result.Error
+	}
+	menuItemLevels := make(map[uint][]models.PageIndexing)
+	for i := range menuItemIndexing {
+		menuItemLevels[menuItemIndexing[i].MenuItemID] = append(menuItemLevels[menuItemIndexing[i].MenuItemID], models.PageIndexing{
+			Option: menuItemIndexing[i].Option,
+			Value:  menuItemIndexing[i].Value,
+		})
+	}
+
+	pageIndexing := make([]models.PageIndexing, 0)
+	if result := database.Pg.Model(&models.PageIndexing{}).
+		Joins("JOIN menu_items ON menu_items.id = page_indexings.menu_item_id").
+		Where("menu_items.version_id = ?", versionID).
+		Find(&pageIndexing); result.Error != nil {
+		return nil, result.Error
+	}
+	pageLevels := make(map[string][]models.PageIndexing)
+	for i := range pageIndexing {
+		key := fmt.Sprintf("%d:%s", pageIndexing[i].MenuItemID, pageIndexing[i].Locale)
+		pageLevels[key] = append(pageLevels[key], pageIndexing[i])
+	}
+
+	pages := make(map[uint]map[string]sitemapPage)
+	menuItemIDs := make(map[string][]uint, len(locales))
+	for _, locale := range locales {
+		menus, err := GetMenusByVersionID(versionID, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		paths, err := getPublishedPagePaths(versionID, locale)
+		if err != nil {
+			return nil, err
+		}
+
+		// Use the shortest path when a menu item is placed in several menus.
+		pathByMenuItem := make(map[uint]string, len(paths))
+		for path, menuItemID := range paths {
+			if current, ok := pathByMenuItem[menuItemID]; !ok || len(path) < len(current) || (len(path) == len(current) && path < current) {
+				pathByMenuItem[menuItemID] = path
+			}
+		}
+
+		for i := range *menus {
+			for j := range (*menus)[i].MenuItemRelations {
+				menuItem := &(*menus)[i].MenuItemRelations[j].MenuItemChild
+				if menuItem.ID == 0 || len(menuItem.Pages) == 0 || menuItem.Pages[0].UrlEnabled {
+					continue
+				} else if !isRobotsIndexable(
+					newRobotsLevel(menuItemLevels[menuItem.ID]),
+					newRobotsLevel(pageLevels[fmt.Sprintf("%d:%s", menuItem.ID, locale)]),
+				) {
+					continue
+				}
+
+				path, ok := pathByMenuItem[menuItem.ID]
+				if !ok {
+					continue
+				}
+
+				if _, ok := pages[menuItem.ID]; !ok {
+					pages[menuItem.ID] = make(map[string]sitemapPage)
+				}
+				if _, ok := pages[menuItem.ID][locale]; ok {
+					continue
+				}
+
+				pages[menuItem.ID][locale] = sitemapPage{Path: path, LastMod: menuItem.Pages[0].UpdatedAt}
+				menuItemIDs[locale] = append(menuItemIDs[locale], menuItem.ID)
+			}
+		}
+	}
+
+	sitemaps := make(map[string][]responses.SitemapURL, len(locales))
+	for _, locale := range locales {
+		urls := make([]responses.SitemapURL, 0, len(menuItemIDs[locale]))
+		for _, menuItemID := range menuItemIDs[locale] {
+			page := pages[menuItemID][locale]
+			url := responses.SitemapURL{
+				Loc:        page.Path,
+				LastMod:    page.LastMod.UTC().Format(time.RFC3339),
+				Alternates: make([]responses.SitemapAlternate, 0),
+			}
+
+			if len(pages[menuItemID]) > 1 {
+				for _, alternateLocale := range locales {
+					if alternate, ok := pages[menuItemID][alternateLocale]; ok {
+						url.Alternates = append(url.Alternates, responses.SitemapAlternate{
+							Rel:      "alternate",
+							Hreflang: alternateLocale,
+							Href:     alternate.Path,
+						})
+					}
+				}
+			}
+
+			urls = append(urls, url)
+		}
+
+		sitemaps[locale] = urls
+	}
+
+	return sitemaps, nil
+}
+
+// getSitemapsCacheKey gets the key for the cache.
+func getSitemapsCacheKey(versionID uint) string {
+	return fmt.Sprintf("sitemaps:%d", versionID)
+}
+
+// isSitemapsInCache checks if the sitemaps of a version exists in the cache.
+func isSitemapsInCache(versionID uint) (bool, error) {
+	return cache.Exists(getSitemapsCacheKey(versionID))
+}
+
+// getSitemapsFromCache gets the sitemaps of a version for all locales from the cache.
+func getSitemapsFromCache(versionID uint) (map[string][]responses.SitemapURL, error) {
+	sitemaps, err := cache.Get[map[string][]responses.SitemapURL](getSitemapsCacheKey(versionID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *sitemaps, nil
+}
+
+// setSitemapsToCache sets the sitemaps of a version for all locales to the cache.
+func setSitemapsToCache(versionID uint, sitemaps map[string][]responses.SitemapURL) error {
+	return cache.Set(getSitemapsCacheKey(versionID), sitemaps)
+}
+
+// deleteSitemapsFromCache deletes existing sitemaps of a version for all locales from the cache.
+func deleteSitemapsFromCache(versionID uint) error {
+	return cache.Delete(getSitemapsCacheKey(versionID))
+}
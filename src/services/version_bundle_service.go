@@ -0,0 +1,287 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"fmt"
+	"strconv"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// VersionExport holds the content of a version as it is exported.
+// Pages are keyed by menu item ID, footer rows by locale and the modules the columns refer to by ID.
+type VersionExport struct {
+	Menus      []models.Menu
+	Pages      map[uint][]models.Page
+	FooterRows map[string][]models.FooterRow
+	Modules    map[uint]models.Module
+}
+
+// GetVersionExport method to get the menus, pages, footer rows and modules of a version, including disabled ones.
+func GetVersionExport(versionID uint) (*VersionExport, error) {
+	content, err := getVersionContent(database.Pg, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &VersionExport{
+		Menus:      make([]models.Menu, 0, len(content.Menus)),
+		Pages:      make(map[uint][]models.Page, len(content.Pages)),
+		FooterRows: content.FooterRows,
+		Modules:    make(map[uint]models.Module),
+	}
+
+	for _, name := range unionKeys(content.Menus, nil) {
+		export.Menus = append(export.Menus, *content.Menus[name])
+	}
+
+	moduleIDs := make(map[uint]struct{})
+	for menuItemID, pages := range content.Pages {
+		for _, locale := range unionKeys(pages, nil) {
+			page := pages[locale]
+			for i := range page.Partials {
+				collectPagePartialRowModuleIDs(page.Partials[i].Rows, moduleIDs)
+			}
+			export.Pages[menuItemID] = append(export.Pages[menuItemID], *page)
+		}
+	}
+	for _, rows := range content.FooterRows {
+		collectFooterRowModuleIDs(rows, moduleIDs)
+	}
+
+	if len(moduleIDs) > 0 {
+		ids := make([]uint, 0, len(moduleIDs))
+		for id := range moduleIDs {
+			ids = append(ids, id)
+		}
+
+		// Deleted modules are exported as well, as columns keep referring to them.
+		modules := make([]models.Module, 0, len(ids))
+		if result := database.Pg.Unscoped().Find(&modules, "id IN ?", ids); result.Error != nil {
+			return nil, result.Error
+		}
+		for i := range modules {
+			export.Modules[modules[i].ID] = modules[i]
+		}
+	}
+
+	return export, nil
+}
+
+// collectPagePartialRowModuleIDs collects the IDs of the modules the columns of the rows refer to.
+func collectPagePartialRowModuleIDs(rows []models.PagePartialRow, moduleIDs map[uint]struct{}) {
+	for i := range rows {
+		for j := range rows[i].Columns {
+			if rows[i].Columns[j].ModuleID.Valid {
+				moduleIDs[rows[i].Columns[j].ModuleID.V] = struct{}{}
+			}
+			collectPagePartialRowModuleIDs(rows[i].Columns[j].PagePartialRows, moduleIDs)
+		}
+	}
+}
+
+// collectFooterRowModuleIDs collects the IDs of the modules the columns of the rows refer to.
+func collectFooterRowModuleIDs(rows []models.FooterRow, moduleIDs map[uint]struct{}) {
+	for i := range rows {
+		for j := range rows[i].Columns {
+			if rows[i].Columns[j].ModuleID.Valid {
+				moduleIDs[rows[i].Columns[j].ModuleID.V] = struct{}{}
+			}
+			collectFooterRowModuleIDs(rows[i].Columns[j].FooterRows, moduleIDs)
+		}
+	}
+}
+
+// ImportVersion method to create a version under an app from a version bundle, all within a transaction.
+// Modules of the bundle that exist in the app by name are reused as they are, the others are created.
+// The webhooks of the app are notified of the imported version, and every imported page is recorded as a revision of the actor.
+func ImportVersion(bundle *requests.ImportVersion, actor string) (*models.Version, error) {
+	version := &models.Version{AppName: bundle.AppName, Name: bundle.Name, EnabledAt: utils.NewNullTime(bundle.EnabledAt)}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+
+		moduleIDs, err := importVersionModulesWithTx(tx, bundle)
+		if err != nil {
+			return err
+		}
+
+		for i := range bundle.Menus {
+			createMenu := &requests.CreateMenu{
+				VersionID: version.ID,
+				Name:      bundle.Menus[i].Name,
+				Depth:     bundle.Menus[i].Depth,
+				Items:     make([]requests.CreateMenuItem, len(bundle.Menus[i].Items)),
+			}
+			for j := range bundle.Menus[i].Items {
+				createMenu.Items[j].SetImportVersionMenuItem(&bundle.Menus[i].Items[j])
+			}
+
+			menu, err := CreateMenuWithTx(tx, createMenu)
+			if err != nil {
+				return err
+			}
+
+			if err := importVersionPagesWithTx(tx, bundle.Menus[i].Items, "", buildMenuItemPathMap(menu.MenuItemRelations), moduleIDs); err != nil {
+				return err
+			}
+		}
+
+		pages := make([]pageRevisionKey, 0)
+		if err := tx.Model(&models.Page{}).
+			Select("pages.menu_item_id, pages.locale").
+			Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+			Where("menu_items.version_id = ?", version.ID).
+			Order("pages.menu_item_id, pages.locale").
+			Scan(&pages).Error; err != nil {
+			return err
+		}
+		for _, page := range pages {
+			if err := createPageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor, enums.AUDIT_IMPORT); err != nil {
+				return err
+			}
+		}
+
+		for i := range bundle.Footers {
+			footer := &bundle.Footers[i]
+			dtoFooter := &requests.UpdateFooter{Rows: make([]requests.UpdateFooterRow, len(footer.Rows))}
+			for j := range footer.Rows {
+				dtoFooter.Rows[j].SetImportVersionRow(&footer.Rows[j], version.ID, footer.Locale, moduleIDs)
+			}
+
+			revision, err := getFooterRevisionWithTx(tx, version.ID, footer.Locale)
+			if err != nil {
+				return err
+			}
+			if _, err := UpdateFooterWithTx(tx, version.ID, footer.Locale, &[]models.FooterRow{}, dtoFooter, revision); err != nil {
+				return err
+			}
+		}
+
+		return enqueueWebhookEventWithTx(tx, version.AppName, enums.WEBHOOK_VERSION_IMPORTED, map[string]any{"versionId": version.ID})
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = deleteVersionsLookupFromCache(version.AppName)
+	if len(bundle.Modules) > 0 {
+		_ = deleteModulesLookupFromCache(version.AppName)
+	}
+
+	return version, nil
+}
+
+// importVersionModulesWithTx creates the modules of a bundle that do not exist in the app and returns the module IDs by name,
+// including the ones of existing modules the bundle refers to. A deleted module with the name of a bundle module is restored.
+func importVersionModulesWithTx(tx *gorm.DB, bundle *requests.ImportVersion) (map[string]uint, error) {
+	moduleIDs := make(map[string]uint, len(bundle.Modules))
+
+	for i := range bundle.Modules {
+		bundleModule := &bundle.Modules[i]
+		module := &models.Module{}
+		result := tx.Unscoped().Limit(1).Find(module, "app_name = ? AND name = ?", bundle.AppName, bundleModule.Name)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			module = &models.Module{
+				AppName:         bundle.AppName,
+				Type:            bundleModule.Type,
+				Name:            bundleModule.Name,
+				Settings:        datatypes.JSON(bundleModule.Settings),
+				SettingsVersion: bundleModule.SettingsVersion,
+			}
+			if err := tx.Create(module).Error; err != nil {
+				return nil, err
+			}
+		} else if module.DeletedAt.Valid {
+			if err := tx.Unscoped().Model(module).Updates(map[string]interface{}{
+				"deleted_at":       nil,
+				"type":             bundleModule.Type,
+				"settings":         datatypes.JSON(bundleModule.Settings),
+				"settings_version": bundleModule.SettingsVersion,
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		moduleIDs[module.Name] = module.ID
+	}
+
+	for _, name := range bundle.GetModuleNames() {
+		if _, ok := moduleIDs[name]; ok {
+			continue
+		}
+
+		module := &models.Module{}
+		if result := tx.Limit(1).Find(module, "app_name = ? AND name = ?", bundle.AppName, name); result.Error != nil {
+			return nil, result.Error
+		} else if result.RowsAffected == 0 {
+			return nil, fmt.Errorf("module %s does not exist", name)
+		}
+
+		moduleIDs[name] = module.ID
+	}
+
+	return moduleIDs, nil
+}
+
+// importVersionPagesWithTx creates the pages and page partials of the menu items of a bundle menu.
+// The menu item IDs are found by the position path of the created menu items.
+func importVersionPagesWithTx(tx *gorm.DB, items []requests.ImportVersionMenuItem, parentPath string, menuItemIDs map[string]uint, moduleIDs map[string]uint) error {
+	for i := range items {
+		path := strconv.FormatUint(uint64(*items[i].Position), 10)
+		if parentPath != "" {
+			path = parentPath + "/" + path
+		}
+
+		// Menu items with the same fields are created once, so their pages and page partials may exist already.
+		for j := range items[i].Pages {
+			bundlePage := &items[i].Pages[j]
+			page := &models.Page{}
+			if err := tx.Where(models.Page{MenuItemID: menuItemIDs[path], Locale: bundlePage.Locale}).
+				Attrs(models.Page{Name: bundlePage.Name}).
+				FirstOrCreate(page).Error; err != nil {
+				return err
+			}
+
+			updatePage := requests.UpdatePage{}
+			updatePage.SetImportVersionPage(bundlePage)
+			if _, err := UpdatePageWithTx(tx, page, &updatePage, page.Revision); err != nil {
+				return err
+			}
+
+			for k := range bundlePage.Partials {
+				partial := &models.PagePartial{MenuItemID: page.MenuItemID, Locale: page.Locale, Name: bundlePage.Partials[k].Name}
+				if err := tx.FirstOrCreate(partial, partial).Error; err != nil {
+					return err
+				}
+				if err := preloadPagePartialTree(tx).First(partial, partial.ID).Error; err != nil {
+					return err
+				}
+
+				updatePartial := requests.UpdatePagePartial{Name: partial.Name, Rows: make([]requests.UpdatePagePartialRow, len(bundlePage.Partials[k].Rows))}
+				for l := range bundlePage.Partials[k].Rows {
+					updatePartial.Rows[l].SetImportVersionRow(&bundlePage.Partials[k].Rows[l], partial.ID, moduleIDs)
+				}
+				if _, err := UpdatePagePartialWithTx(tx, partial, &updatePartial, partial.Revision); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := importVersionPagesWithTx(tx, items[i].Items, path, menuItemIDs, moduleIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,716 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// versionDiffIgnoredFields are identifiers, positions, timestamps and child collections.
+// They are not compared as fields, as they are part of the path of an entry or differ between versions by design.
+var versionDiffIgnoredFields = map[string]struct{}{
+	"id":         {},
+	"versionId":  {},
+	"menuItemId": {},
+	"partialId":  {},
+	"rowId":      {},
+	"locale":     {},
+	"position":   {},
+	"createdAt":  {},
+	"updatedAt":  {},
+	"items":      {},
+	"partials":   {},
+	"rows":       {},
+	"columns":    {},
+}
+
+// versionDiffPartialsSegment separates the path of a menu item and the name of a page partial.
+const versionDiffPartialsSegment = "/partials/"
+
+// versionContent holds the content of a version as it is compared.
+// Menu items are addressed by the menu name and their position path, e.g. "main/0/2".
+type versionContent struct {
+	Menus      map[string]*models.Menu
+	Relations  map[string]*models.MenuItemRelation
+	ItemPaths  map[uint]string
+	Pages      map[uint]map[string]*models.Page
+	FooterRows map[string][]models.FooterRow
+}
+
+// DiffVersions method to get the changes that turn a version into another version.
+// Menus are matched by name, menu items by their position path within the menu tree, the way
+// DuplicateVersion matches them, and menu items left over on both sides by name, which makes them moved.
+// Pages are compared per locale for matched menu items, page partials by name and partial and footer
+// rows and columns by their position path.
+func DiffVersions(versionID, otherVersionID uint) ([]responses.VersionChange, error) {
+	content, err := getVersionContent(database.Pg, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	otherContent, err := getVersionContent(database.Pg, otherVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, _, err := diffVersionContents(content, otherContent)
+
+	return changes, err
+}
+
+// diffVersionContents gets the changes between the contents of two versions and the pairs of matched menu item IDs.
+func diffVersionContents(content, otherContent *versionContent) ([]responses.VersionChange, map[uint]uint, error) {
+	changes := make([]responses.VersionChange, 0)
+	if err := diffMenus(&changes, content, otherContent); err != nil {
+		return nil, nil, err
+	}
+
+	menuItemPairs, err := diffMenuItems(&changes, content, otherContent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := diffPages(&changes, content, otherContent, menuItemPairs); err != nil {
+		return nil, nil, err
+	}
+
+	if err := diffFooters(&changes, content, otherContent); err != nil {
+		return nil, nil, err
+	}
+
+	return changes, menuItemPairs, nil
+}
+
+// getVersionContent gets the not deleted menus, menu items, pages and footer rows of a version, including disabled ones.
+func getVersionContent(db *gorm.DB, versionID uint) (*versionContent, error) {
+	content := &versionContent{
+		Pages:      make(map[uint]map[string]*models.Page),
+		FooterRows: make(map[string][]models.FooterRow),
+	}
+
+	if err := content.loadMenus(db, versionID); err != nil {
+		return nil, err
+	}
+
+	pages := make([]models.Page, 0)
+	if result := preloadVersionContentPage(db).
+		Where("menu_items.version_id = ?", versionID).
+		Find(&pages); result.Error != nil {
+		return nil, result.Error
+	}
+
+	for i := range pages {
+		content.setPage(pages[i].MenuItemID, pages[i].Locale, &pages[i])
+	}
+
+	footerRows := make([]models.FooterRow, 0)
+	if result := preloadVersionContentFooterRows(db).
+		Find(&footerRows, "version_id = ?", versionID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	for i := range footerRows {
+		content.FooterRows[footerRows[i].Locale] = append(content.FooterRows[footerRows[i].Locale], footerRows[i])
+	}
+
+	return content, nil
+}
+
+// loadMenus (re)loads the not deleted menus and menu items of a version into the content.
+func (c *versionContent) loadMenus(db *gorm.DB, versionID uint) error {
+	c.Menus = make(map[string]*models.Menu)
+	c.Relations = make(map[string]*models.MenuItemRelation)
+	c.ItemPaths = make(map[uint]string)
+
+	menus := make([]models.Menu, 0)
+	if result := db.
+		Preload("MenuItemRelations", func(db *gorm.DB) *gorm.DB {
+			return db.Preload("MenuItemChild").
+				Preload("MenuItemChild.Indexing", func(db2 *gorm.DB) *gorm.DB {
+					return db2.Order("option ASC")
+				}).
+				Order("menu_item_parent_id NULLS FIRST").
+				Order("position ASC")
+		}).
+		Order("name ASC").
+		Find(&menus, "version_id = ?", versionID); result.Error != nil {
+		return result.Error
+	}
+
+	for i := range menus {
+		menu := &menus[i]
+		c.Menus[menu.Name] = menu
+
+		parentByChild := make(map[uint]sql.Null[uint], len(menu.MenuItemRelations))
+		relationByChild := make(map[uint]models.MenuItemRelation, len(menu.MenuItemRelations))
+		for j := range menu.MenuItemRelations {
+			rel := menu.MenuItemRelations[j]
+			parentByChild[rel.MenuItemChildID] = rel.MenuItemParentID
+			relationByChild[rel.MenuItemChildID] = rel
+		}
+
+		for j := range menu.MenuItemRelations {
+			rel := &menu.MenuItemRelations[j]
+			if rel.MenuItemChild.ID == 0 {
+				// The menu item is deleted.
+				continue
+			}
+
+			path := menu.Name + "/" + buildMenuItemPath(rel, relationByChild, parentByChild)
+			c.Relations[path] = rel
+			if current, ok := c.ItemPaths[rel.MenuItemChildID]; !ok || path < current {
+				c.ItemPaths[rel.MenuItemChildID] = path
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadPage (re)loads the page of a menu item in a locale into the content, or removes it when it no longer exists.
+func (c *versionContent) loadPage(db *gorm.DB, menuItemID uint, locale string) error {
+	page := &models.Page{}
+	result := preloadVersionContentPage(db).
+		Where("pages.menu_item_id = ? AND pages.locale = ?", menuItemID, locale).
+		Limit(1).
+		Find(page)
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		delete(c.Pages[menuItemID], locale)
+		return nil
+	}
+
+	c.setPage(menuItemID, locale, page)
+
+	return nil
+}
+
+// setPage sets the page of a menu item in a locale in the content.
+func (c *versionContent) setPage(menuItemID uint, locale string, page *models.Page) {
+	if _, ok := c.Pages[menuItemID]; !ok {
+		c.Pages[menuItemID] = make(map[string]*models.Page)
+	}
+	c.Pages[menuItemID][locale] = page
+}
+
+// loadFooterRows (re)loads the root footer rows of a version in a locale into the content.
+func (c *versionContent) loadFooterRows(db *gorm.DB, versionID uint, locale string) error {
+	footerRows := make([]models.FooterRow, 0)
+	if result := preloadVersionContentFooterRows(db).
+		Find(&footerRows, "version_id = ? AND locale = ?", versionID, locale); result.Error != nil {
+		return result.Error
+	}
+
+	if len(footerRows) == 0 {
+		delete(c.FooterRows, locale)
+	} else {
+		c.FooterRows[locale] = footerRows
+	}
+
+	return nil
+}
+
+// preloadVersionContentPage prepares the query of the pages of not deleted menu items with their indexing and partial tree.
+func preloadVersionContentPage(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Indexing", func(db *gorm.DB) *gorm.DB {
+			return db.Order("option ASC")
+		}).
+		Preload("Partials", func(db *gorm.DB) *gorm.DB {
+			return preloadPagePartialTree(db).Order("name ASC")
+		}).
+		Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id AND menu_items.deleted_at IS NULL")
+}
+
+// preloadVersionContentFooterRows prepares the query of the root footer rows with their tree, in order.
+func preloadVersionContentFooterRows(db *gorm.DB) *gorm.DB {
+	return preloadFooterTree(db).
+		Where("NOT EXISTS (SELECT 1 FROM footer_row_column_rows frcr WHERE frcr.row_id = footer_rows.id)").
+		Order("position asc")
+}
+
+// diffMenus adds the menus that are added, removed or modified.
+func diffMenus(changes *[]responses.VersionChange, content, otherContent *versionContent) error {
+	for _, name := range unionKeys(content.Menus, otherContent.Menus) {
+		menu, ok := content.Menus[name]
+		otherMenu, otherOk := otherContent.Menus[name]
+
+		var fields, otherFields map[string]any
+		var err error
+		if ok {
+			response := responses.Menu{}
+			response.SetMenu(menu)
+			if fields, err = toVersionDiffFields(response); err != nil {
+				return err
+			}
+		}
+		if otherOk {
+			response := responses.Menu{}
+			response.SetMenu(otherMenu)
+			if otherFields, err = toVersionDiffFields(response); err != nil {
+				return err
+			}
+		}
+
+		appendVersionChange(changes, enums.MENU, name, nil, fields, otherFields)
+	}
+
+	return nil
+}
+
+// diffMenuItems adds the menu items that are added, removed, moved or modified and
+// returns the pairs of matched menu item IDs.
+func diffMenuItems(changes *[]responses.VersionChange, content, otherContent *versionContent) (map[uint]uint, error) {
+	pairs := make(map[uint]uint)
+	pairedOther := make(map[uint]struct{})
+	removedPaths := make([]string, 0)
+	addedPaths := make([]string, 0)
+
+	itemFields := func(rel *models.MenuItemRelation) (map[string]any, error) {
+		response := responses.MenuItem{}
+		response.SetMenuItem(&rel.MenuItemChild, rel.Position)
+		return toVersionDiffFields(response)
+	}
+
+	pair := func(id, otherID uint) {
+		if _, ok := pairs[id]; ok {
+			return
+		} else if _, ok := pairedOther[otherID]; ok {
+			return
+		}
+		pairs[id] = otherID
+		pairedOther[otherID] = struct{}{}
+	}
+
+	for _, path := range unionKeys(content.Relations, otherContent.Relations) {
+		rel, ok := content.Relations[path]
+		otherRel, otherOk := otherContent.Relations[path]
+		if !otherOk {
+			removedPaths = append(removedPaths, path)
+			continue
+		} else if !ok {
+			addedPaths = append(addedPaths, path)
+			continue
+		} else if rel.MenuItemChild.Name != otherRel.MenuItemChild.Name && isMenuItemNameMovedTo(rel.MenuItemChild.Name, content, otherContent) {
+			// The item was shifted by an insert or removal of a sibling, so it is matched by name below.
+			removedPaths = append(removedPaths, path)
+			addedPaths = append(addedPaths, path)
+			continue
+		}
+
+		fields, err := itemFields(rel)
+		if err != nil {
+			return nil, err
+		}
+		otherFields, err := itemFields(otherRel)
+		if err != nil {
+			return nil, err
+		}
+
+		pair(rel.MenuItemChildID, otherRel.MenuItemChildID)
+		appendVersionChange(changes, enums.MENU_ITEM, path, nil, fields, otherFields)
+	}
+
+	// Items left over on both sides with the same name are moved.
+	moved := make(map[string]struct{})
+	for _, path := range removedPaths {
+		rel := content.Relations[path]
+		fields, err := itemFields(rel)
+		if err != nil {
+			return nil, err
+		}
+
+		var otherPath string
+		for _, addedPath := range addedPaths {
+			if _, ok := moved[addedPath]; !ok && otherContent.Relations[addedPath].MenuItemChild.Name == rel.MenuItemChild.Name {
+				otherPath = addedPath
+				break
+			}
+		}
+
+		if otherPath == "" {
+			appendVersionChange(changes, enums.MENU_ITEM, path, nil, fields, nil)
+			continue
+		}
+
+		otherRel := otherContent.Relations[otherPath]
+		otherFields, err := itemFields(otherRel)
+		if err != nil {
+			return nil, err
+		}
+
+		moved[otherPath] = struct{}{}
+		pair(rel.MenuItemChildID, otherRel.MenuItemChildID)
+
+		fromPath := path
+		change := newVersionChange(enums.MENU_ITEM, enums.MOVED, otherPath, nil, fields, otherFields)
+		change.FromPath = &fromPath
+		change.Fields = diffVersionFields(fields, otherFields)
+		*changes = append(*changes, change)
+	}
+
+	for _, path := range addedPaths {
+		if _, ok := moved[path]; ok {
+			continue
+		}
+
+		otherFields, err := itemFields(otherContent.Relations[path])
+		if err != nil {
+			return nil, err
+		}
+
+		appendVersionChange(changes, enums.MENU_ITEM, path, nil, nil, otherFields)
+	}
+
+	return pairs, nil
+}
+
+// isMenuItemNameMovedTo checks if the other version has an item with the name at a path
+// where the version does not have an item with the same name.
+func isMenuItemNameMovedTo(name string, content, otherContent *versionContent) bool {
+	for path, otherRel := range otherContent.Relations {
+		if otherRel.MenuItemChild.Name != name {
+			continue
+		} else if rel, ok := content.Relations[path]; !ok || rel.MenuItemChild.Name != name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffPages adds the pages, page partials, rows and columns that are added, removed or modified.
+// Pages of matched menu items are compared per locale. Pages of menu items that only exist in one of
+// both versions are added or removed together with their page partials.
+func diffPages(changes *[]responses.VersionChange, content, otherContent *versionContent, menuItemPairs map[uint]uint) error {
+	type pagePair struct {
+		Path        string
+		MenuItemID  uint
+		OtherItemID uint
+	}
+
+	pairs := make([]pagePair, 0, len(content.ItemPaths)+len(otherContent.ItemPaths))
+	pairedOther := make(map[uint]struct{}, len(menuItemPairs))
+	for menuItemID, path := range content.ItemPaths {
+		if otherMenuItemID, ok := menuItemPairs[menuItemID]; ok {
+			pairs = append(pairs, pagePair{Path: otherContent.ItemPaths[otherMenuItemID], MenuItemID: menuItemID, OtherItemID: otherMenuItemID})
+			pairedOther[otherMenuItemID] = struct{}{}
+		} else {
+			pairs = append(pairs, pagePair{Path: path, MenuItemID: menuItemID})
+		}
+	}
+	for otherMenuItemID, path := range otherContent.ItemPaths {
+		if _, ok := pairedOther[otherMenuItemID]; !ok {
+			pairs = append(pairs, pagePair{Path: path, OtherItemID: otherMenuItemID})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Path < pairs[j].Path
+	})
+
+	for _, pair := range pairs {
+		pages := content.Pages[pair.MenuItemID]
+		otherPages := otherContent.Pages[pair.OtherItemID]
+
+		for _, locale := range unionKeys(pages, otherPages) {
+			pageLocale := locale
+			if err := diffPage(changes, pair.Path, &pageLocale, toVersionDiffPage(pages[locale]), toVersionDiffPage(otherPages[locale])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffPage adds the changes of a page and its page partials, rows and columns. A nil page does not exist.
+func diffPage(changes *[]responses.VersionChange, path string, locale *string, page, otherPage *responses.Page) error {
+	var fields, otherFields map[string]any
+	var err error
+	partials := make(map[string]responses.PagePartial)
+	otherPartials := make(map[string]responses.PagePartial)
+	if page != nil {
+		if fields, err = toVersionDiffFields(page); err != nil {
+			return err
+		}
+		for i := range page.Partials {
+			partials[page.Partials[i].Name] = page.Partials[i]
+		}
+	}
+	if otherPage != nil {
+		if otherFields, err = toVersionDiffFields(otherPage); err != nil {
+			return err
+		}
+		for i := range otherPage.Partials {
+			otherPartials[otherPage.Partials[i].Name] = otherPage.Partials[i]
+		}
+	}
+
+	appendVersionChange(changes, enums.PAGE, path, locale, fields, otherFields)
+
+	for _, name := range unionKeys(partials, otherPartials) {
+		partial, ok := partials[name]
+		otherPartial, otherOk := otherPartials[name]
+		partialPath := path + versionDiffPartialsSegment + name
+
+		var tree, otherTree map[string]any
+		if ok {
+			if tree, err = toVersionDiffTree(partial); err != nil {
+				return err
+			}
+		}
+		if otherOk {
+			if otherTree, err = toVersionDiffTree(otherPartial); err != nil {
+				return err
+			}
+		}
+
+		appendVersionChange(changes, enums.PAGE_PARTIAL, partialPath, locale, versionDiffFields(tree), versionDiffFields(otherTree))
+		if ok && otherOk {
+			diffLayoutRows(changes, enums.PAGE_PARTIAL_ROW, enums.PAGE_PARTIAL_ROW_COLUMN, partialPath, locale, tree["rows"], otherTree["rows"])
+		}
+	}
+
+	return nil
+}
+
+// toVersionDiffPage converts a page into its response, or nil when the page does not exist.
+func toVersionDiffPage(page *models.Page) *responses.Page {
+	if page == nil {
+		return nil
+	}
+
+	response := &responses.Page{}
+	response.SetPage(page)
+
+	return response
+}
+
+// diffFooters adds the footer rows and columns per locale that are added, removed or modified.
+func diffFooters(changes *[]responses.VersionChange, content, otherContent *versionContent) error {
+	for _, locale := range unionKeys(content.FooterRows, otherContent.FooterRows) {
+		footerLocale := locale
+
+		rows := make([]responses.FooterRow, len(content.FooterRows[locale]))
+		for i := range content.FooterRows[locale] {
+			rows[i].SetFooterRow(&content.FooterRows[locale][i])
+		}
+		otherRows := make([]responses.FooterRow, len(otherContent.FooterRows[locale]))
+		for i := range otherContent.FooterRows[locale] {
+			otherRows[i].SetFooterRow(&otherContent.FooterRows[locale][i])
+		}
+
+		tree, err := toVersionDiffTree(map[string]any{"rows": rows})
+		if err != nil {
+			return err
+		}
+		otherTree, err := toVersionDiffTree(map[string]any{"rows": otherRows})
+		if err != nil {
+			return err
+		}
+
+		diffLayoutRows(changes, enums.FOOTER_ROW, enums.FOOTER_ROW_COLUMN, "footer", &footerLocale, tree["rows"], otherTree["rows"])
+	}
+
+	return nil
+}
+
+// diffLayoutRows adds the rows and columns of a layout tree that are added, removed or modified.
+// Rows and columns are matched by position, so their path is e.g. "<path>/rows/0/columns/1/rows/0".
+func diffLayoutRows(changes *[]responses.VersionChange, rowEntity, columnEntity enums.VersionEntity, path string, locale *string, rows, otherRows any) {
+	rowsByPosition := versionDiffByPosition(rows)
+	otherRowsByPosition := versionDiffByPosition(otherRows)
+
+	for _, position := range unionPositions(rowsByPosition, otherRowsByPosition) {
+		rowPath := fmt.Sprintf("%s/rows/%d", path, position)
+		row, ok := rowsByPosition[position]
+		otherRow, otherOk := otherRowsByPosition[position]
+
+		appendVersionChange(changes, rowEntity, rowPath, locale, versionDiffFields(row), versionDiffFields(otherRow))
+		if !ok || !otherOk {
+			continue
+		}
+
+		columnsByPosition := versionDiffByPosition(row["columns"])
+		otherColumnsByPosition := versionDiffByPosition(otherRow["columns"])
+
+		for _, columnPosition := range unionPositions(columnsByPosition, otherColumnsByPosition) {
+			columnPath := fmt.Sprintf("%s/columns/%d", rowPath, columnPosition)
+			column, ok := columnsByPosition[columnPosition]
+			otherColumn, otherOk := otherColumnsByPosition[columnPosition]
+
+			appendVersionChange(changes, columnEntity, columnPath, locale, versionDiffFields(column), versionDiffFields(otherColumn))
+			if ok && otherOk {
+				diffLayoutRows(changes, rowEntity, columnEntity, columnPath, locale, column["rows"], otherColumn["rows"])
+			}
+		}
+	}
+}
+
+// appendVersionChange adds an added, removed or modified change. A nil fields map means the entity
+// does not exist in that version. Nothing is added when both exist with equal fields.
+func appendVersionChange(changes *[]responses.VersionChange, entity enums.VersionEntity, path string, locale *string, fields, otherFields map[string]any) {
+	switch {
+	case fields == nil && otherFields == nil:
+		return
+	case fields == nil:
+		*changes = append(*changes, newVersionChange(entity, enums.ADDED, path, locale, fields, otherFields))
+	case otherFields == nil:
+		*changes = append(*changes, newVersionChange(entity, enums.REMOVED, path, locale, fields, otherFields))
+	default:
+		fieldChanges := diffVersionFields(fields, otherFields)
+		if len(fieldChanges) == 0 {
+			return
+		}
+
+		change := newVersionChange(entity, enums.MODIFIED, path, locale, fields, otherFields)
+		change.Fields = fieldChanges
+		*changes = append(*changes, change)
+	}
+}
+
+// newVersionChange creates a change without field changes. The IDs are taken from the "id" or "menuItemId" field.
+func newVersionChange(entity enums.VersionEntity, change enums.VersionChange, path string, locale *string, fields, otherFields map[string]any) responses.VersionChange {
+	return responses.VersionChange{
+		Entity:  entity.String(),
+		Change:  change.String(),
+		Path:    path,
+		Locale:  locale,
+		ID:      versionDiffID(fields),
+		OtherID: versionDiffID(otherFields),
+		Fields:  make([]responses.VersionFieldChange, 0),
+	}
+}
+
+// diffVersionFields compares the fields of an entity in both versions, sorted by field name.
+func diffVersionFields(fields, otherFields map[string]any) []responses.VersionFieldChange {
+	fieldChanges := make([]responses.VersionFieldChange, 0)
+
+	for _, field := range unionKeys(fields, otherFields) {
+		if _, ok := versionDiffIgnoredFields[field]; ok {
+			continue
+		} else if reflect.DeepEqual(fields[field], otherFields[field]) {
+			continue
+		}
+
+		fieldChanges = append(fieldChanges, responses.VersionFieldChange{
+			Field: field,
+			From:  fields[field],
+			To:    otherFields[field],
+		})
+	}
+
+	return fieldChanges
+}
+
+// toVersionDiffTree converts a response into a generic JSON tree, so entities are compared by their API fields.
+func toVersionDiffTree(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string]any)
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// toVersionDiffFields converts a response into its comparable fields.
+func toVersionDiffFields(value any) (map[string]any, error) {
+	tree, err := toVersionDiffTree(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return versionDiffFields(tree), nil
+}
+
+// versionDiffFields replaces the enabledAt moment by an enabled flag, as the moment differs between versions by design.
+func versionDiffFields(tree map[string]any) map[string]any {
+	if tree == nil {
+		return nil
+	}
+
+	if enabledAt, ok := tree["enabledAt"]; ok {
+		tree["enabled"] = enabledAt != nil
+		delete(tree, "enabledAt")
+	}
+
+	return tree
+}
+
+// versionDiffID gets the ID of an entity from its fields.
+func versionDiffID(fields map[string]any) *uint {
+	for _, key := range []string{"id", "menuItemId"} {
+		if value, ok := fields[key].(float64); ok {
+			id := uint(value)
+			return &id
+		}
+	}
+
+	return nil
+}
+
+// versionDiffByPosition indexes a list of rows or columns of a generic JSON tree by their position.
+func versionDiffByPosition(value any) map[uint]map[string]any {
+	byPosition := make(map[uint]map[string]any)
+
+	list, _ := value.([]any)
+	for i := range list {
+		entity, ok := list[i].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		position, _ := entity["position"].(float64)
+		if _, ok := byPosition[uint(position)]; !ok {
+			byPosition[uint(position)] = entity
+		}
+	}
+
+	return byPosition
+}
+
+// unionKeys gets the sorted keys of two maps.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// unionPositions gets the sorted positions of two position indexes.
+func unionPositions(a, b map[uint]map[string]any) []uint {
+	positions := make([]uint, 0, len(a)+len(b))
+	for position := range a {
+		positions = append(positions, position)
+	}
+	for position := range b {
+		if _, ok := a[position]; !ok {
+			positions = append(positions, position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	return positions
+}
@@ -0,0 +1,745 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"gorm.io/gorm"
+)
+
+// MergeSelectionError is returned by MergeVersion when a selected change can not be merged.
+type MergeSelectionError struct {
+	Selection requests.MergeVersionChange
+	Reason    string
+}
+
+func (e *MergeSelectionError) Error() string {
+	return e.Selection.Entity + " " + e.Selection.Path + ": " + e.Reason
+}
+
+// VersionMergeResult holds the changes of a merge that are applied and the ones that conflict.
+type VersionMergeResult struct {
+	Applied   []responses.VersionChange
+	Conflicts []responses.VersionChange
+}
+
+// versionMergeUnit is the entity a selected change is merged with. Rows and columns are merged
+// with their page partial or footer, and the menu items of a menu are merged with their menu.
+type versionMergeUnit struct {
+	Selection requests.MergeVersionChange
+	Entity    enums.VersionEntity
+	Path      string
+	Locale    *string
+	Changes   []responses.VersionChange
+}
+
+// versionMerge holds the contents of the target and source version while a merge is applied.
+type versionMerge struct {
+	Target         *versionContent
+	Source         *versionContent
+	SourceToTarget map[uint]uint
+}
+
+// MergeVersion method to apply the selected changes of the source version onto the version in one transaction.
+// The changes are the ones of DiffVersions from the version to the source version, so paths are those of the source.
+// A change conflicts when the entity it is merged with was changed in both versions since the version was
+// duplicated, or created when it is not a duplicate. Nothing is written when there are conflicts, unless forced.
+// The webhooks of the app are notified when changes were applied, and every merged page is recorded as a revision of the actor.
+func MergeVersion(version, sourceVersion *models.Version, request *requests.MergeVersion, actor string) (*VersionMergeResult, error) {
+	result := &VersionMergeResult{
+		Applied:   make([]responses.VersionChange, 0),
+		Conflicts: make([]responses.VersionChange, 0),
+	}
+
+	since := version.CreatedAt
+	if version.DuplicatedAt.Valid {
+		since = version.DuplicatedAt.Time
+	}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		merge, changes, err := getVersionMerge(tx, version.ID, sourceVersion.ID)
+		if err != nil {
+			return err
+		}
+
+		units, err := getVersionMergeUnits(request.Changes, changes)
+		if err != nil {
+			return err
+		}
+
+		for i := range units {
+			conflict, err := merge.isConflict(&units[i], since)
+			if err != nil {
+				return err
+			}
+
+			if conflict {
+				result.Conflicts = append(result.Conflicts, units[i].Changes...)
+			}
+			result.Applied = append(result.Applied, units[i].Changes...)
+		}
+
+		if len(result.Conflicts) > 0 && !request.Force {
+			result.Applied = make([]responses.VersionChange, 0)
+			return nil
+		}
+
+		// Only addresses of the published version are live, a draft registers its redirects when it is published.
+		var oldAddresses map[string]map[uint]*pageAddress
+		if version.PublishedAt.Valid {
+			if oldAddresses, err = getVersionPageAddressesWithTx(tx, version.ID); err != nil {
+				return err
+			}
+		}
+
+		// Menus go first, so the pages of added menu items have a menu item to be merged onto.
+		mergedPages := make([]pageRevisionKey, 0)
+		for _, entity := range []enums.VersionEntity{enums.MENU, enums.MENU_ITEM, enums.PAGE, enums.PAGE_PARTIAL, enums.FOOTER} {
+			for i := range units {
+				if units[i].Entity != entity {
+					continue
+				}
+
+				isPageUnit := entity == enums.PAGE || entity == enums.PAGE_PARTIAL
+				if isPageUnit {
+					if err := merge.recordPageRevisionWithTx(tx, &units[i], actor); err != nil {
+						return err
+					}
+				}
+
+				if err := merge.apply(tx, version.ID, &units[i]); err != nil {
+					return err
+				}
+
+				if err := merge.refresh(tx, version.ID, &units[i]); err != nil {
+					return err
+				}
+
+				if page, _ := merge.getPages(&units[i]); isPageUnit && page != nil {
+					mergedPages = append(mergedPages, pageRevisionKey{MenuItemID: page.MenuItemID, Locale: page.Locale})
+				}
+			}
+		}
+
+		for _, key := range dedupePageRevisionKeys(mergedPages) {
+			if err := createPageRevisionWithTx(tx, key.MenuItemID, key.Locale, actor, enums.AUDIT_MERGE); err != nil {
+				return err
+			}
+		}
+
+		if version.PublishedAt.Valid {
+			newAddresses, err := getVersionPageAddressesWithTx(tx, version.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := registerVersionAddressRedirectsWithTx(tx, version.AppName, oldAddresses, newAddresses, nil); err != nil {
+				return err
+			}
+		}
+
+		if len(result.Applied) == 0 {
+			return nil
+		}
+
+		if err := touchVersionWithTx(tx, version.ID); err != nil {
+			return err
+		}
+
+		return enqueueWebhookEventWithTx(tx, version.AppName, enums.WEBHOOK_VERSION_MERGED, map[string]any{
+			"versionId":       version.ID,
+			"sourceVersionId": sourceVersion.ID,
+			"changes":         len(result.Applied),
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(result.Applied) > 0 {
+		_ = deletePublishedVersionFromCache(version.AppName, version.ID)
+		_ = deleteMenusLookupFromCache(version.ID)
+	}
+
+	return result, nil
+}
+
+// getVersionMerge gets the contents of both versions and the changes from the version to the source version.
+func getVersionMerge(tx *gorm.DB, versionID, sourceVersionID uint) (*versionMerge, []responses.VersionChange, error) {
+	target, err := getVersionContent(tx, versionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, err := getVersionContent(tx, sourceVersionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, pairs, err := diffVersionContents(target, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merge := &versionMerge{Target: target, Source: source, SourceToTarget: make(map[uint]uint, len(pairs))}
+	for targetID, sourceID := range pairs {
+		merge.SourceToTarget[sourceID] = targetID
+	}
+
+	return merge, changes, nil
+}
+
+// getVersionMergeUnits groups the changes by the unit they are merged with, in the order of the selection.
+func getVersionMergeUnits(selections []requests.MergeVersionChange, changes []responses.VersionChange) ([]versionMergeUnit, error) {
+	units := make([]versionMergeUnit, 0, len(selections))
+	selected := make(map[versionMergeUnitKey]struct{}, len(selections))
+
+	for i := range selections {
+		unit := newVersionMergeUnit(selections[i])
+
+		key := versionMergeUnitKey{Entity: unit.Entity, Path: unit.Path}
+		if unit.Locale != nil {
+			key.Locale = *unit.Locale
+		}
+		if _, ok := selected[key]; ok {
+			continue
+		}
+		selected[key] = struct{}{}
+
+		if unit.Entity != enums.MENU && unit.Entity != enums.MENU_ITEM && unit.Locale == nil {
+			return nil, &MergeSelectionError{Selection: selections[i], Reason: "a locale is required"}
+		}
+
+		for j := range changes {
+			if unit.selects(&changes[j]) {
+				unit.Changes = append(unit.Changes, changes[j])
+			}
+		}
+
+		if len(unit.Changes) == 0 {
+			return nil, &MergeSelectionError{Selection: selections[i], Reason: "there is no change"}
+		} else if unit.Entity == enums.MENU_ITEM && unit.Changes[0].Change != enums.MODIFIED.String() {
+			return nil, &MergeSelectionError{Selection: selections[i], Reason: "only a modified menu item can be merged, other menu item changes are merged with their menu"}
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+// versionMergeUnitKey identifies a unit, so a unit that is selected more than once is merged once.
+type versionMergeUnitKey struct {
+	Entity enums.VersionEntity
+	Path   string
+	Locale string
+}
+
+// newVersionMergeUnit creates the unit a selection is merged with.
+func newVersionMergeUnit(selection requests.MergeVersionChange) versionMergeUnit {
+	unit := versionMergeUnit{
+		Selection: selection,
+		Entity:    enums.VersionEntity(selection.Entity),
+		Path:      selection.Path,
+		Locale:    selection.Locale,
+		Changes:   make([]responses.VersionChange, 0),
+	}
+
+	switch unit.Entity {
+	case enums.MENU, enums.MENU_ITEM:
+		unit.Locale = nil
+	case enums.PAGE_PARTIAL_ROW, enums.PAGE_PARTIAL_ROW_COLUMN:
+		unit.Entity = enums.PAGE_PARTIAL
+		unit.Path = versionMergePartialPath(unit.Path)
+	case enums.FOOTER, enums.FOOTER_ROW, enums.FOOTER_ROW_COLUMN:
+		unit.Entity = enums.FOOTER
+		unit.Path = "footer"
+	}
+
+	return unit
+}
+
+// versionMergePartialPath gets the path of the page partial of a row or column path.
+func versionMergePartialPath(path string) string {
+	i := strings.Index(path, versionDiffPartialsSegment)
+	if i < 0 {
+		return path
+	}
+
+	if j := strings.Index(path[i+len(versionDiffPartialsSegment):], "/rows/"); j >= 0 {
+		return path[:i+len(versionDiffPartialsSegment)+j]
+	}
+
+	return path
+}
+
+// selects checks if a change is merged with the unit.
+func (u *versionMergeUnit) selects(change *responses.VersionChange) bool {
+	if u.Locale != nil && (change.Locale == nil || *change.Locale != *u.Locale) {
+		return false
+	}
+
+	switch u.Entity {
+	case enums.MENU:
+		if change.Entity == enums.MENU.String() {
+			return change.Path == u.Path
+		}
+
+		return change.Entity == enums.MENU_ITEM.String() &&
+			(strings.HasPrefix(change.Path, u.Path+"/") || (change.FromPath != nil && strings.HasPrefix(*change.FromPath, u.Path+"/")))
+	case enums.MENU_ITEM, enums.PAGE:
+		return change.Entity == u.Entity.String() && change.Path == u.Path
+	case enums.PAGE_PARTIAL:
+		switch change.Entity {
+		case enums.PAGE_PARTIAL.String(), enums.PAGE_PARTIAL_ROW.String(), enums.PAGE_PARTIAL_ROW_COLUMN.String():
+			return change.Path == u.Path || strings.HasPrefix(change.Path, u.Path+"/rows/")
+		}
+	case enums.FOOTER:
+		return change.Entity == enums.FOOTER_ROW.String() || change.Entity == enums.FOOTER_ROW_COLUMN.String()
+	}
+
+	return false
+}
+
+// isConflict checks if the unit was changed in both versions since the given moment.
+// A unit that does not exist in the source version counts as changed there, one that does not exist in the version does not.
+func (m *versionMerge) isConflict(unit *versionMergeUnit, since time.Time) (bool, error) {
+	target, source := m.getUnitContent(unit)
+	if target == nil {
+		return false, nil
+	} else if source == nil {
+		return isVersionMergeChangedSince(target, since)
+	}
+
+	changed, err := isVersionMergeChangedSince(target, since)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	return isVersionMergeChangedSince(source, since)
+}
+
+// getUnitContent gets the content of the unit in the version and the source version, or nil when it does not exist.
+func (m *versionMerge) getUnitContent(unit *versionMergeUnit) (any, any) {
+	var target, source any
+
+	switch unit.Entity {
+	case enums.MENU:
+		if menu, ok := m.Target.Menus[unit.Path]; ok {
+			target = menu
+		}
+		if menu, ok := m.Source.Menus[unit.Path]; ok {
+			source = menu
+		}
+	case enums.MENU_ITEM:
+		if rel, ok := m.Target.Relations[unit.Path]; ok {
+			target = rel.MenuItemChild
+		}
+		if rel, ok := m.Source.Relations[unit.Path]; ok {
+			source = rel.MenuItemChild
+		}
+	case enums.PAGE, enums.PAGE_PARTIAL:
+		targetPage, sourcePage := m.getPages(unit)
+		if unit.Entity == enums.PAGE {
+			// The page partials are merged on their own.
+			if targetPage != nil {
+				page := *targetPage
+				page.Partials = nil
+				target = page
+			}
+			if sourcePage != nil {
+				page := *sourcePage
+				page.Partials = nil
+				source = page
+			}
+		} else {
+			if partial := getVersionMergePartial(targetPage, unit.Path); partial != nil {
+				target = partial
+			}
+			if partial := getVersionMergePartial(sourcePage, unit.Path); partial != nil {
+				source = partial
+			}
+		}
+	case enums.FOOTER:
+		if rows, ok := m.Target.FooterRows[*unit.Locale]; ok {
+			target = rows
+		}
+		if rows, ok := m.Source.FooterRows[*unit.Locale]; ok {
+			source = rows
+		}
+	}
+
+	return target, source
+}
+
+// getMenuItemIDs gets the ID of the menu item of a path in the version and the source version, or 0 when it does not exist.
+// The path is the one of the source version, unless the menu item only exists in the version.
+func (m *versionMerge) getMenuItemIDs(path string) (uint, uint) {
+	if rel, ok := m.Source.Relations[path]; ok {
+		return m.SourceToTarget[rel.MenuItemChildID], rel.MenuItemChildID
+	} else if rel, ok := m.Target.Relations[path]; ok {
+		return rel.MenuItemChildID, 0
+	}
+
+	return 0, 0
+}
+
+// getPages gets the page of the unit in the version and the source version, or nil when it does not exist.
+func (m *versionMerge) getPages(unit *versionMergeUnit) (*models.Page, *models.Page) {
+	path := unit.Path
+	if i := strings.Index(path, versionDiffPartialsSegment); i >= 0 {
+		path = path[:i]
+	}
+
+	targetID, sourceID := m.getMenuItemIDs(path)
+
+	return m.Target.Pages[targetID][*unit.Locale], m.Source.Pages[sourceID][*unit.Locale]
+}
+
+// recordPageRevisionWithTx records the page of a unit before it is merged: the content from before its first
+// recorded change, and the content of a page the unit deletes.
+func (m *versionMerge) recordPageRevisionWithTx(tx *gorm.DB, unit *versionMergeUnit, actor string) error {
+	page, sourcePage := m.getPages(unit)
+	if page == nil {
+		return nil
+	}
+
+	if err := ensurePageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor); err != nil {
+		return err
+	}
+
+	if unit.Entity == enums.PAGE && sourcePage == nil {
+		return createPageRevisionWithTx(tx, page.MenuItemID, page.Locale, actor, enums.AUDIT_DELETE)
+	}
+
+	return nil
+}
+
+// getVersionMergePartial gets the page partial of a page partial path, or nil when it does not exist.
+func getVersionMergePartial(page *models.Page, path string) *models.PagePartial {
+	if page == nil {
+		return nil
+	}
+
+	i := strings.Index(path, versionDiffPartialsSegment)
+	if i < 0 {
+		return nil
+	}
+
+	name := path[i+len(versionDiffPartialsSegment):]
+	for j := range page.Partials {
+		if page.Partials[j].Name == name {
+			return &page.Partials[j]
+		}
+	}
+
+	return nil
+}
+
+// isVersionMergeChangedSince checks if the content, or anything within it, was updated after the given moment.
+func isVersionMergeChangedSince(content any, since time.Time) (bool, error) {
+	value, err := json.Marshal(content)
+	if err != nil {
+		return false, err
+	}
+
+	var tree any
+	if err := json.Unmarshal(value, &tree); err != nil {
+		return false, err
+	}
+
+	return getLatestUpdatedAt(tree).After(since), nil
+}
+
+// getLatestUpdatedAt walks a JSON tree for the latest UpdatedAt.
+func getLatestUpdatedAt(tree any) time.Time {
+	latest := time.Time{}
+
+	switch value := tree.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if key == "UpdatedAt" {
+				if updatedAt, ok := child.(string); ok {
+					if t, err := time.Parse(time.RFC3339Nano, updatedAt); err == nil && t.After(latest) {
+						latest = t
+					}
+				}
+			} else if t := getLatestUpdatedAt(child); t.After(latest) {
+				latest = t
+			}
+		}
+	case []any:
+		for i := range value {
+			if t := getLatestUpdatedAt(value[i]); t.After(latest) {
+				latest = t
+			}
+		}
+	}
+
+	return latest
+}
+
+// refresh reloads the content of the version that the unit was applied to. After a menu or menu item unit,
+// the menus are reloaded and their items matched again, as the matching may have changed.
+func (m *versionMerge) refresh(tx *gorm.DB, versionID uint, unit *versionMergeUnit) error {
+	switch unit.Entity {
+	case enums.MENU, enums.MENU_ITEM:
+		if err := m.Target.loadMenus(tx, versionID); err != nil {
+			return err
+		}
+
+		pairs, err := diffMenuItems(&[]responses.VersionChange{}, m.Target, m.Source)
+		if err != nil {
+			return err
+		}
+
+		m.SourceToTarget = make(map[uint]uint, len(pairs))
+		for targetID, sourceID := range pairs {
+			m.SourceToTarget[sourceID] = targetID
+		}
+	case enums.PAGE, enums.PAGE_PARTIAL:
+		path := strings.SplitN(unit.Path, versionDiffPartialsSegment, 2)[0]
+		if menuItemID, _ := m.getMenuItemIDs(path); menuItemID != 0 {
+			return m.Target.loadPage(tx, menuItemID, *unit.Locale)
+		}
+	case enums.FOOTER:
+		return m.Target.loadFooterRows(tx, versionID, *unit.Locale)
+	}
+
+	return nil
+}
+
+// apply merges the unit of the source version onto the version.
+func (m *versionMerge) apply(tx *gorm.DB, versionID uint, unit *versionMergeUnit) error {
+	switch unit.Entity {
+	case enums.MENU:
+		return m.applyMenu(tx, versionID, unit)
+	case enums.MENU_ITEM:
+		return m.applyMenuItem(tx, unit)
+	case enums.PAGE:
+		return m.applyPage(tx, unit)
+	case enums.PAGE_PARTIAL:
+		return m.applyPagePartial(tx, unit)
+	case enums.FOOTER:
+		return m.applyFooter(tx, versionID, unit)
+	}
+
+	return nil
+}
+
+// applyMenu replaces the menu and its menu items with the ones of the source version.
+// Menu items that are matched with a menu item of the same menu keep their ID, and so their pages.
+func (m *versionMerge) applyMenu(tx *gorm.DB, versionID uint, unit *versionMergeUnit) error {
+	menu, ok := m.Target.Menus[unit.Path]
+	sourceMenu, sourceOk := m.Source.Menus[unit.Path]
+	if !sourceOk {
+		return tx.Delete(menu).Error
+	} else if !ok {
+		// A deleted menu keeps its name, so it is restored instead.
+		result := tx.Unscoped().Model(&models.Menu{}).
+			Where("version_id = ? AND name = ?", versionID, sourceMenu.Name).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		menu = &models.Menu{}
+		if result.RowsAffected > 0 {
+			if err := tx.First(menu, "version_id = ? AND name = ?", versionID, sourceMenu.Name).Error; err != nil {
+				return err
+			}
+		} else {
+			var err error
+			if menu, err = CreateMenuWithTx(tx, &requests.CreateMenu{VersionID: versionID, Name: sourceMenu.Name, Depth: utils.PtrFromNull(sourceMenu.Depth)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	items := buildUpdateMenuItemsFromRelations(sourceMenu, sql.Null[uint]{}, func(rel *models.MenuItemRelation) (*models.MenuItemRelation, *uint) {
+		if id, ok := m.SourceToTarget[rel.MenuItemChildID]; ok && strings.HasPrefix(m.Target.ItemPaths[id], unit.Path+"/") {
+			return rel, &id
+		}
+
+		return rel, nil
+	})
+
+	_, err := UpdateMenuWithTx(tx, menu, &requests.UpdateMenu{
+		Name:  sourceMenu.Name,
+		Depth: utils.PtrFromNull(sourceMenu.Depth),
+		Items: items,
+	}, menu.Revision)
+
+	return err
+}
+
+// applyMenuItem replaces the fields of a menu item with the ones of the source version.
+func (m *versionMerge) applyMenuItem(tx *gorm.DB, unit *versionMergeUnit) error {
+	rel, ok := m.Target.Relations[unit.Path]
+	sourceRel, sourceOk := m.Source.Relations[unit.Path]
+	if !ok || !sourceOk {
+		return &MergeSelectionError{Selection: unit.Selection, Reason: "the menu item is changed by the merge of its menu"}
+	}
+
+	var menu *models.Menu
+	for _, targetMenu := range m.Target.Menus {
+		if targetMenu.ID == rel.MenuID {
+			menu = targetMenu
+		}
+	}
+
+	items := buildUpdateMenuItemsFromRelations(menu, sql.Null[uint]{}, func(r *models.MenuItemRelation) (*models.MenuItemRelation, *uint) {
+		id := r.MenuItemChildID
+		if id == rel.MenuItemChildID {
+			return sourceRel, &id
+		}
+
+		return r, &id
+	})
+
+	_, err := UpdateMenuWithTx(tx, menu, &requests.UpdateMenu{
+		Name:  menu.Name,
+		Depth: utils.PtrFromNull(menu.Depth),
+		Items: items,
+	}, menu.Revision)
+
+	return err
+}
+
+// buildUpdateMenuItemsFromRelations builds the update tree of the menu items under a parent.
+// The relation callback gets the relation to take the fields from and the ID to update, or nil to create the menu item.
+func buildUpdateMenuItemsFromRelations(menu *models.Menu, parentID sql.Null[uint], relation func(rel *models.MenuItemRelation) (*models.MenuItemRelation, *uint)) []requests.UpdateMenuItem {
+	items := make([]requests.UpdateMenuItem, 0)
+	for i := range menu.MenuItemRelations {
+		rel := &menu.MenuItemRelations[i]
+		if rel.MenuItemChild.ID == 0 || rel.MenuItemParentID != parentID {
+			continue
+		}
+
+		fields, id := relation(rel)
+		item := requests.UpdateMenuItem{}
+		item.SetMenuItemRelation(fields, id)
+		item.Position = &rel.Position
+		item.Items = buildUpdateMenuItemsFromRelations(menu, sql.Null[uint]{V: rel.MenuItemChildID, Valid: true}, relation)
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// applyPage replaces the fields of a page with the ones of the source version, creates or deletes it.
+func (m *versionMerge) applyPage(tx *gorm.DB, unit *versionMergeUnit) error {
+	page, sourcePage := m.getPages(unit)
+	if sourcePage == nil {
+		if page == nil {
+			return nil
+		}
+
+		return tx.Delete(&models.Page{MenuItemID: page.MenuItemID, Locale: page.Locale}).Error
+	}
+
+	if page == nil {
+		var err error
+		if page, err = m.createPage(tx, unit, sourcePage); err != nil {
+			return err
+		}
+	}
+
+	updatePage := requests.UpdatePage{}
+	updatePage.SetPage(sourcePage)
+	_, err := UpdatePageWithTx(tx, page, &updatePage, page.Revision)
+
+	return err
+}
+
+// createPage creates the page of a unit in the version, for the menu item that is matched with the one of the source page.
+func (m *versionMerge) createPage(tx *gorm.DB, unit *versionMergeUnit, sourcePage *models.Page) (*models.Page, error) {
+	menuItemID, _ := m.getMenuItemIDs(strings.SplitN(unit.Path, versionDiffPartialsSegment, 2)[0])
+	if menuItemID == 0 {
+		return nil, &MergeSelectionError{Selection: unit.Selection, Reason: "the menu item does not exist in the version, merge its menu first"}
+	}
+
+	var count int64
+	if err := tx.Unscoped().Model(&models.Page{}).Where("menu_item_id = ? AND locale = ?", menuItemID, sourcePage.Locale).Count(&count).Error; err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, &MergeSelectionError{Selection: unit.Selection, Reason: "the page is deleted in the version, restore it first"}
+	}
+
+	page := &models.Page{MenuItemID: menuItemID, Locale: sourcePage.Locale, Name: sourcePage.Name}
+	if err := tx.Create(page).Error; err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// applyPagePartial replaces the rows and columns of a page partial with the ones of the source version, creates or deletes it.
+func (m *versionMerge) applyPagePartial(tx *gorm.DB, unit *versionMergeUnit) error {
+	page, sourcePage := m.getPages(unit)
+	partial := getVersionMergePartial(page, unit.Path)
+	sourcePartial := getVersionMergePartial(sourcePage, unit.Path)
+	if sourcePartial == nil {
+		if partial == nil {
+			return nil
+		}
+
+		return tx.Delete(&models.PagePartial{}, partial.ID).Error
+	} else if page == nil {
+		return &MergeSelectionError{Selection: unit.Selection, Reason: "the page does not exist in the version, merge the page first"}
+	}
+
+	if partial == nil {
+		// A deleted page partial keeps its name, so it is restored instead.
+		partial = &models.PagePartial{MenuItemID: page.MenuItemID, Locale: page.Locale, Name: sourcePartial.Name}
+		if err := tx.Unscoped().Model(&models.PagePartial{}).
+			Where("menu_item_id = ? AND locale = ? AND name = ?", partial.MenuItemID, partial.Locale, partial.Name).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.FirstOrCreate(partial, partial).Error; err != nil {
+			return err
+		}
+		if err := preloadPagePartialTree(tx).First(partial, partial.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	updatePartial := requests.UpdatePagePartial{}
+	updatePartial.SetPagePartial(sourcePartial, partial.ID)
+	_, err := UpdatePagePartialWithTx(tx, partial, &updatePartial, partial.Revision)
+
+	return err
+}
+
+// applyFooter replaces the footer rows of a locale with the ones of the source version.
+func (m *versionMerge) applyFooter(tx *gorm.DB, versionID uint, unit *versionMergeUnit) error {
+	locale := *unit.Locale
+	rows := m.Target.FooterRows[locale]
+	sourceRows := m.Source.FooterRows[locale]
+
+	dtoRows := make([]requests.UpdateFooterRow, 0, len(sourceRows))
+	for i := range sourceRows {
+		dtoRow := requests.UpdateFooterRow{}
+		dtoRow.SetFooterRow(&sourceRows[i], versionID, locale)
+		dtoRows = append(dtoRows, dtoRow)
+	}
+
+	if rows == nil {
+		rows = make([]models.FooterRow, 0)
+	}
+	revision, err := getFooterRevisionWithTx(tx, versionID, locale)
+	if err != nil {
+		return err
+	}
+	_, err = UpdateFooterWithTx(tx, versionID, locale, &rows, &requests.UpdateFooter{Rows: dtoRows}, revision)
+
+	return err
+}
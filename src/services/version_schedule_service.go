@@ -0,0 +1,294 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchedulerActor is the actor recorded for publications executed by the scheduler.
+const SchedulerActor = "scheduler"
+
+// defaultVersionScheduleRetryBackoff is used when VERSION_SCHEDULE_RETRY_BACKOFF is not set or invalid.
+const defaultVersionScheduleRetryBackoff = time.Minute
+
+// maxVersionScheduleRetryDelay caps the exponential backoff between attempts of a failing schedule.
+const maxVersionScheduleRetryDelay = time.Hour
+
+// GetVersionSchedule method to get the schedule of a version.
+func GetVersionSchedule(versionID uint) (*models.VersionSchedule, error) {
+	schedule := &models.VersionSchedule{}
+
+	if result := database.Pg.Limit(1).Find(schedule, "version_id = ?", versionID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return schedule, nil
+}
+
+// SetVersionSchedule method to create or replace the schedule of a version.
+// Replacing a schedule resets its execution and failure state, so it runs again at the new moment.
+func SetVersionSchedule(version *models.Version, request *requests.SetVersionSchedule) (*models.VersionSchedule, error) {
+	schedule := &models.VersionSchedule{
+		VersionID:   version.ID,
+		AppName:     version.AppName,
+		PublishAt:   request.PublishAt,
+		UnpublishAt: utils.NewNullTime(request.UnpublishAt),
+	}
+
+	if result := database.Pg.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "version_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"app_name",
+			"publish_at",
+			"unpublish_at",
+			"fallback_version_id",
+			"published_at",
+			"unpublished_at",
+			"attempts",
+			"last_error",
+			"retry_at",
+			"updated_at",
+		}),
+	}).Create(schedule); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return GetVersionSchedule(version.ID)
+}
+
+// DeleteVersionSchedule method to delete the schedule of a version.
+func DeleteVersionSchedule(versionID uint) error {
+	return database.Pg.Delete(&models.VersionSchedule{}, "version_id = ?", versionID).Error
+}
+
+// ProcessDueVersionSchedules method to publish and unpublish all versions whose schedule is due.
+// Each schedule is claimed in its own transaction with a row lock that skips rows
+// already claimed by another replica, so every schedule runs exactly once.
+// A schedule that fails is recorded with its error and retried after a backoff, and the remaining
+// schedules are processed. The errors of the failed schedules are returned together.
+func ProcessDueVersionSchedules(now time.Time) (int, error) {
+	processed := 0
+	var errs []error
+
+	for {
+		schedule, err := processNextVersionSchedule(now)
+		if err != nil && schedule != nil && schedule.VersionID != 0 {
+			errs = append(errs, fmt.Errorf("version %d: %w", schedule.VersionID, err))
+			if recordErr := recordVersionScheduleFailure(schedule, err, now); recordErr != nil {
+				return processed, errors.Join(append(errs, recordErr)...)
+			}
+			continue
+		} else if err != nil {
+			return processed, errors.Join(append(errs, err)...)
+		} else if schedule == nil {
+			return processed, errors.Join(errs...)
+		}
+
+		processed++
+	}
+}
+
+// GetVersionScheduleRetryDelay method to get the delay before the next attempt of a schedule that failed attempts times.
+// The delay doubles every attempt, starting at VERSION_SCHEDULE_RETRY_BACKOFF.
+func GetVersionScheduleRetryDelay(attempts uint) time.Duration {
+	delay, err := time.ParseDuration(os.Getenv("VERSION_SCHEDULE_RETRY_BACKOFF"))
+	if err != nil || delay <= 0 {
+		delay = defaultVersionScheduleRetryBackoff
+	}
+
+	for i := uint(1); i < attempts && delay < maxVersionScheduleRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxVersionScheduleRetryDelay)
+}
+
+// recordVersionScheduleFailure records the error of a failed schedule and postpones its next attempt.
+func recordVersionScheduleFailure(schedule *models.VersionSchedule, err error, now time.Time) error {
+	attempts := schedule.Attempts + 1
+
+	return database.Pg.Model(&models.VersionSchedule{}).Where("version_id = ?", schedule.VersionID).Updates(map[string]any{
+		"attempts":   attempts,
+		"last_error": sql.NullString{String: err.Error(), Valid: true},
+		"retry_at":   sql.NullTime{Time: now.Add(GetVersionScheduleRetryDelay(attempts)), Valid: true},
+	}).Error
+}
+
+// versionScheduleAudit is a publication of the scheduler that is recorded as an audit of the version.
+type versionScheduleAudit struct {
+	VersionID uint
+	Action    enums.AuditAction
+	Before    *responses.Version
+}
+
+// getVersionAuditStateWithTx gets the state of a version as recorded in an audit.
+func getVersionAuditStateWithTx(tx *gorm.DB, versionID uint) (*responses.Version, error) {
+	version := &models.Version{}
+	if result := tx.Limit(1).Find(version, "id = ?", versionID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	state := &responses.Version{}
+	state.SetVersion(version)
+
+	return state, nil
+}
+
+// processNextVersionSchedule claims and runs the next due schedule. Its publications are audited with the scheduler actor.
+// It returns nil when no schedule is due, and the claimed schedule together with the error when it failed.
+func processNextVersionSchedule(now time.Time) (*models.VersionSchedule, error) {
+	schedule := &models.VersionSchedule{}
+	var affectedVersionIDs []uint
+	audits := make([]versionScheduleAudit, 0, 2)
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("(retry_at IS NULL OR retry_at <= ?)", now).
+			Where("((published_at IS NULL AND publish_at <= ? AND EXISTS (SELECT 1 FROM versions v WHERE v.id = version_schedules.version_id AND v.deleted_at IS NULL AND v.enabled_at IS NOT NULL))"+
+				" OR (published_at IS NOT NULL AND unpublished_at IS NULL AND unpublish_at <= ?))", now, now).
+			Order("publish_at ASC").
+			Limit(1).
+			Find(schedule)
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var publishedVersionIDs []uint
+		if result := tx.Model(&models.Version{}).Where("app_name = ? AND published_at IS NOT NULL", schedule.AppName).Pluck("id", &publishedVersionIDs); result.Error != nil {
+			return result.Error
+		}
+		affectedVersionIDs = append(publishedVersionIDs, schedule.VersionID)
+
+		before, err := getVersionAuditStateWithTx(tx, schedule.VersionID)
+		if err != nil {
+			return err
+		}
+
+		if !schedule.PublishedAt.Valid {
+			audits = append(audits, versionScheduleAudit{VersionID: schedule.VersionID, Action: enums.AUDIT_PUBLISH, Before: before})
+
+			return publishScheduledVersionWithTx(tx, schedule, publishedVersionIDs, now)
+		}
+
+		var fallbackBefore *responses.Version
+		if schedule.FallbackVersionID.Valid {
+			if fallbackBefore, err = getVersionAuditStateWithTx(tx, schedule.FallbackVersionID.V); err != nil {
+				return err
+			}
+		}
+
+		fallbackVersionID, err := unpublishScheduledVersionWithTx(tx, schedule, publishedVersionIDs, now)
+		if err != nil {
+			return err
+		}
+		if fallbackVersionID.Valid {
+			affectedVersionIDs = append(affectedVersionIDs, fallbackVersionID.V)
+			audits = append(audits, versionScheduleAudit{VersionID: fallbackVersionID.V, Action: enums.AUDIT_PUBLISH, Before: fallbackBefore})
+		}
+		if slices.Contains(publishedVersionIDs, schedule.VersionID) {
+			audits = append(audits, versionScheduleAudit{VersionID: schedule.VersionID, Action: enums.AUDIT_UNPUBLISH, Before: before})
+		}
+
+		return nil
+	}); err != nil {
+		return schedule, err
+	}
+
+	if schedule.VersionID == 0 {
+		return nil, nil
+	}
+
+	// The publications already succeeded, so the audit records are written after the commit.
+	for i := range audits {
+		after, err := getVersionAuditStateWithTx(database.Pg, audits[i].VersionID)
+		if err == nil {
+			_ = CreateAudit(SchedulerActor, enums.AUDIT_VERSION, strconv.FormatUint(uint64(audits[i].VersionID), 10), audits[i].Action, audits[i].Before, after)
+		}
+	}
+
+	_ = deletePublishedVersionFromCache(schedule.AppName, affectedVersionIDs...)
+	startPublishedVersionCacheWarmUp(schedule.AppName)
+
+	return schedule, nil
+}
+
+// publishScheduledVersionWithTx publishes the version of a schedule and remembers
+// the version it replaced as fallback for the unpublish moment.
+func publishScheduledVersionWithTx(tx *gorm.DB, schedule *models.VersionSchedule, publishedVersionIDs []uint, now time.Time) error {
+	schedule.FallbackVersionID = sql.Null[uint]{}
+	for i := range publishedVersionIDs {
+		if publishedVersionIDs[i] != schedule.VersionID {
+			schedule.FallbackVersionID = sql.Null[uint]{V: publishedVersionIDs[i], Valid: true}
+			break
+		}
+	}
+
+	if err := PublishVersionWithTx(tx, schedule.AppName, schedule.VersionID, SchedulerActor); err != nil {
+		return err
+	}
+
+	return tx.Model(schedule).Updates(map[string]any{
+		"fallback_version_id": schedule.FallbackVersionID,
+		"published_at":        sql.NullTime{Time: now, Valid: true},
+		"attempts":            0,
+		"last_error":          sql.NullString{},
+		"retry_at":            sql.NullTime{},
+	}).Error
+}
+
+// unpublishScheduledVersionWithTx unpublishes the version of a schedule when it is still live
+// and publishes the fallback version again when that one is still available.
+// It returns the fallback version when it was published.
+func unpublishScheduledVersionWithTx(tx *gorm.DB, schedule *models.VersionSchedule, publishedVersionIDs []uint, now time.Time) (sql.Null[uint], error) {
+	fallbackVersionID := sql.Null[uint]{}
+
+	isLive := false
+	for i := range publishedVersionIDs {
+		if publishedVersionIDs[i] == schedule.VersionID {
+			isLive = true
+			break
+		}
+	}
+
+	if isLive {
+		if schedule.FallbackVersionID.Valid {
+			fallback := &models.Version{}
+			if result := tx.Limit(1).Find(fallback, "id = ? AND enabled_at IS NOT NULL", schedule.FallbackVersionID.V); result.Error != nil {
+				return fallbackVersionID, result.Error
+			} else if result.RowsAffected == 1 {
+				fallbackVersionID = schedule.FallbackVersionID
+			}
+		}
+
+		if fallbackVersionID.Valid {
+			if err := PublishVersionWithTx(tx, schedule.AppName, fallbackVersionID.V, SchedulerActor); err != nil {
+				return fallbackVersionID, err
+			}
+		} else if err := UnpublishVersionWithTx(tx, schedule.VersionID, SchedulerActor); err != nil {
+			return fallbackVersionID, err
+		}
+	}
+
+	return fallbackVersionID, tx.Model(schedule).Updates(map[string]any{
+		"unpublished_at": sql.NullTime{Time: now, Valid: true},
+		"attempts":       0,
+		"last_error":     sql.NullString{},
+		"retry_at":       sql.NullTime{},
+	}).Error
+}
@@ -5,13 +5,11 @@ import (
 	"api-page/main/src/database"
 	"api-page/main/src/dto/requests"
 	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
 	"api-page/main/src/models"
-	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -19,8 +17,8 @@ import (
 	"github.com/ArnoldPMolenaar/api-utils/pagination"
 	"github.com/ArnoldPMolenaar/api-utils/utils"
 	"github.com/gofiber/fiber/v3"
-	"github.com/valkey-io/valkey-go"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // IsVersionPublished method to check if a version is published.
@@ -194,7 +192,8 @@ func CreateVersion(version *requests.CreateVersion) (*models.Version, error) {
 }
 
 // UpdateVersion method to update a version.
-func UpdateVersion(oldVersion *models.Version, version *requests.UpdateVersion) (*models.Version, error) {
+// ErrRevisionConflict is returned when the version is no longer at the given revision.
+func UpdateVersion(oldVersion *models.Version, version *requests.UpdateVersion, revision uint64) (*models.Version, error) {
 	if oldVersion == nil {
 		return nil, gorm.ErrRecordNotFound
 	}
@@ -206,8 +205,14 @@ func UpdateVersion(oldVersion *models.Version, version *requests.UpdateVersion)
 		oldVersion.EnabledAt = sql.NullTime{Valid: false}
 	}
 
-	if result := database.Pg.Save(oldVersion); result.Error != nil {
-		return nil, result.Error
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := incrementRevisionWithTx(tx, oldVersion, revision); txErr != nil {
+			return txErr
+		}
+
+		return tx.Save(oldVersion).Error
+	}); err != nil {
+		return nil, err
 	}
 
 	_ = deleteVersionsLookupFromCache(oldVersion.AppName)
@@ -296,7 +301,8 @@ func DuplicateVersion(oldVersion *models.Version, settings *requests.CreateDupli
 			}
 		}
 
-		return nil
+		// Content changed after this moment is a change of one of both versions, see MergeVersion.
+		return tx.Model(newVersion).Update("duplicated_at", time.Now()).Error
 	}); err != nil {
 		return nil, err
 	}
@@ -319,31 +325,334 @@ func DuplicateVersion(oldVersion *models.Version, settings *requests.CreateDupli
 	return newVersion, nil
 }
 
-// PublishVersion method to publish a version.
-func PublishVersion(appName string, versionID uint) error {
-	// Start a new transaction
-	tx := database.Pg.Begin()
-	if tx.Error != nil {
-		return tx.Error
+// PublishVersion method to publish a version. The cache of the version is warmed up in the background.
+func PublishVersion(appName string, versionID uint, actor string) error {
+	var previousVersionIDs []uint
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Model(&models.Version{}).Where("app_name = ? AND published_at IS NOT NULL", appName).Pluck("id", &previousVersionIDs); result.Error != nil {
+			return result.Error
+		}
+
+		return PublishVersionWithTx(tx, appName, versionID, actor)
+	}); err != nil {
+		return err
+	}
+
+	_ = deletePublishedVersionFromCache(appName, append(previousVersionIDs, versionID)...)
+	_, _ = StartVersionCacheWarmUp(versionID)
+
+	return nil
+}
+
+// PublishVersionWithTx publishes a version using the provided transaction.
+// All other versions of the app are unpublished, the publication is recorded and the webhooks of the app are notified.
+// It performs no transaction lifecycle control and no cache side effects.
+func PublishVersionWithTx(tx *gorm.DB, appName string, versionID uint, actor string) error {
+	return publishVersionWithTx(tx, appName, versionID, enums.PUBLISH, actor)
+}
+
+// UnpublishVersionWithTx unpublishes a version using the provided transaction, records the publication
+// and notifies the webhooks of the app.
+// It performs no transaction lifecycle control and no cache side effects.
+func UnpublishVersionWithTx(tx *gorm.DB, versionID uint, actor string) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	version := &models.Version{}
+	if result := tx.Limit(1).Find(version, "id = ?", versionID); result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err := lockAppVersionsWithTx(tx, version.AppName); err != nil {
+		return err
+	}
+
+	if result := tx.Model(&models.Version{}).Where("id = ?", versionID).Update("published_at", sql.NullTime{Valid: false}); result.Error != nil {
+		return result.Error
+	}
+
+	publication := &models.VersionPublication{
+		AppName:   version.AppName,
+		VersionID: version.ID,
+		Action:    enums.UNPUBLISH,
+		Actor:     actor,
+	}
+
+	if err := tx.Create(publication).Error; err != nil {
+		return err
+	}
+
+	if err := touchVersionWithTx(tx, version.ID); err != nil {
+		return err
+	}
+
+	return enqueueWebhookEventWithTx(tx, version.AppName, enums.WEBHOOK_VERSION_UNPUBLISHED, map[string]any{"versionId": version.ID})
+}
+
+// ErrVersionRollbackNotFound is returned when an app has no previously published version to roll back to.
+var ErrVersionRollbackNotFound = errors.New("version to roll back to not found")
+
+// ErrVersionNotEnabled is returned when the version to roll back to is not enabled.
+var ErrVersionNotEnabled = errors.New("version is not enabled")
+
+// getRollbackVersionWithTx gets the version that was live before the currently published version of an app.
+// A version reached by a rollback is traced back to its earlier publication, so repeated rollbacks walk back through the history.
+// When the app has no published version, the last unpublished version is returned.
+func getRollbackVersionWithTx(tx *gorm.DB, appName string) (*models.Version, error) {
+	version := &models.Version{}
+
+	published := &models.Version{}
+	if result := tx.Limit(1).Find(published, "app_name = ? AND published_at IS NOT NULL", appName); result.Error != nil {
+		return nil, result.Error
+	}
+
+	publication := &models.VersionPublication{}
+	query := tx.Where("app_name = ?", appName).Order("created_at DESC").Order("id DESC").Limit(1)
+	if published.ID != 0 {
+		query = query.Where("version_id = ? AND action IN ?", published.ID, []enums.PublicationAction{enums.PUBLISH, enums.ROLLBACK})
+	} else {
+		query = query.Where("action = ?", enums.UNPUBLISH)
+	}
+
+	if result := query.Find(publication); result.Error != nil {
+		return nil, result.Error
+	} else if result.RowsAffected == 0 {
+		return version, nil
+	}
+
+	targetVersionID := publication.VersionID
+	if published.ID != 0 {
+		for publication.Action == enums.ROLLBACK {
+			earlier := &models.VersionPublication{}
+			if result := tx.Where("app_name = ? AND version_id = ? AND action IN ? AND id < ?", appName, publication.VersionID, []enums.PublicationAction{enums.PUBLISH, enums.ROLLBACK}, publication.ID).Order("id DESC").Limit(1).Find(earlier); result.Error != nil {
+				return nil, result.Error
+			} else if result.RowsAffected == 0 {
+				return version, nil
+			}
+			publication = earlier
+		}
+
+		if !publication.PreviousVersionID.Valid {
+			return version, nil
+		}
+		targetVersionID = publication.PreviousVersionID.V
+	}
+
+	if result := tx.Limit(1).Find(version, "id = ?", targetVersionID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return version, nil
+}
+
+// RollbackVersion method to publish the version that was live before the currently published version of an app again.
+// The versions of the app are locked while the version is resolved and published, so concurrent rollbacks and publishes
+// are applied one after the other. The returned version holds its state before the rollback.
+// ErrVersionRollbackNotFound or ErrVersionNotEnabled is returned when there is no version to roll back to.
+// The cache of the version is warmed up in the background.
+func RollbackVersion(appName, actor string) (*models.Version, error) {
+	var version *models.Version
+	var previousVersionIDs []uint
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		if txErr := lockAppVersionsWithTx(tx, appName); txErr != nil {
+			return txErr
+		}
+
+		var txErr error
+		if version, txErr = getRollbackVersionWithTx(tx, appName); txErr != nil {
+			return txErr
+		} else if version.ID == 0 {
+			return ErrVersionRollbackNotFound
+		} else if !version.EnabledAt.Valid {
+			return ErrVersionNotEnabled
+		}
+
+		if result := tx.Model(&models.Version{}).Where("app_name = ? AND published_at IS NOT NULL", appName).Pluck("id", &previousVersionIDs); result.Error != nil {
+			return result.Error
+		}
+
+		return publishVersionWithTx(tx, appName, version.ID, enums.ROLLBACK, actor)
+	}); err != nil {
+		return nil, err
+	}
+
+	_ = deletePublishedVersionFromCache(appName, append(previousVersionIDs, version.ID)...)
+	_, _ = StartVersionCacheWarmUp(version.ID)
+
+	return version, nil
+}
+
+// lockAppVersionsWithTx locks the versions of an app until the transaction ends, so the published version is changed by one transaction at a time.
+// It performs no transaction lifecycle control and no cache side effects.
+func lockAppVersionsWithTx(tx *gorm.DB, appName string) error {
+	var versionIDs []uint
+
+	return tx.Model(&models.Version{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("app_name = ?", appName).
+		Order("id ASC").
+		Pluck("id", &versionIDs).Error
+}
+
+// GetVersionPublications method to get the paginated publication history of an app.
+func GetVersionPublications(c fiber.Ctx, appName string) (*pagination.Model, error) {
+	publications := make([]models.VersionPublication, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"id":                  true,
+		"version_id":          true,
+		"action":              true,
+		"previous_version_id": true,
+		"actor":               true,
+		"created_at":          true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Preload("Version", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("PreviousVersion", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Where("app_name = ?", appName).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset)
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.VersionPublication{}).
+		Where("app_name = ?", appName)
+
+	if result := dbResult.Find(&publications); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedPublications := make([]responses.PaginatedVersionPublication, 0)
+	for i := range publications {
+		paginatedPublication := responses.PaginatedVersionPublication{}
+		paginatedPublication.SetPaginatedVersionPublication(&publications[i])
+		paginatedPublications = append(paginatedPublications, paginatedPublication)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedPublications)
+
+	return &paginationModel, nil
+}
+
+// publishVersionWithTx publishes a version, unpublishes all other versions of the app,
+// records the publication together with the version it replaced and notifies the webhooks of the app.
+func publishVersionWithTx(tx *gorm.DB, appName string, versionID uint, action enums.PublicationAction, actor string) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	if err := lockAppVersionsWithTx(tx, appName); err != nil {
+		return err
+	}
+
+	var previousVersionIDs []uint
+	if result := tx.Model(&models.Version{}).Where("app_name = ? AND published_at IS NOT NULL AND id != ?", appName, versionID).Pluck("id", &previousVersionIDs); result.Error != nil {
+		return result.Error
 	}
 
 	if result := tx.Model(&models.Version{}).Where("app_name = ?", appName).Update("published_at", sql.NullTime{Valid: false}); result.Error != nil {
-		tx.Rollback()
 		return result.Error
 	}
 
 	if result := tx.Model(&models.Version{}).Where("id = ?", versionID).Update("published_at", sql.NullTime{Time: time.Now(), Valid: true}); result.Error != nil {
-		tx.Rollback()
 		return result.Error
 	}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
+	// A rollback serves the modules as they were when the version was published before.
+	if err := snapshotVersionModulesWithTx(tx, versionID, action == enums.PUBLISH); err != nil {
 		return err
 	}
 
-	return nil
+	publication := &models.VersionPublication{
+		AppName:   appName,
+		VersionID: versionID,
+		Action:    action,
+		Actor:     actor,
+	}
+	if len(previousVersionIDs) > 0 {
+		publication.PreviousVersionID = sql.Null[uint]{V: previousVersionIDs[0], Valid: true}
+	}
+
+	if err := tx.Create(publication).Error; err != nil {
+		return err
+	}
+
+	if publication.PreviousVersionID.Valid {
+		if err := registerPublicationRedirectsWithTx(tx, appName, publication.PreviousVersionID.V, versionID); err != nil {
+			return err
+		}
+	}
+
+	if err := touchVersionWithTx(tx, versionID); err != nil {
+		return err
+	}
+
+	data := map[string]any{"versionId": versionID}
+	if publication.PreviousVersionID.Valid {
+		data["previousVersionId"] = publication.PreviousVersionID.V
+	}
+
+	return enqueueWebhookEventWithTx(tx, appName, enums.WEBHOOK_VERSION_PUBLISHED, data)
+}
+
+// touchVersionWithTx sets the moment the content of a version changed, which its published content is served with as Last-Modified.
+// It performs no transaction lifecycle control and no cache side effects.
+func touchVersionWithTx(tx *gorm.DB, versionID uint) error {
+	return tx.Model(&models.Version{}).Where("id = ?", versionID).UpdateColumn("content_changed_at", time.Now()).Error
+}
+
+// touchMenuItemVersionWithTx sets the moment the content of the version of a menu item changed.
+// It performs no transaction lifecycle control and no cache side effects.
+func touchMenuItemVersionWithTx(tx *gorm.DB, menuItemID uint) error {
+	return tx.Model(&models.Version{}).
+		Where("id = (SELECT version_id FROM menu_items WHERE id = ?)", menuItemID).
+		UpdateColumn("content_changed_at", time.Now()).Error
+}
+
+// touchAppVersionsWithTx sets the moment the content of every version of an app changed, as the modules of the app
+// are shared by its versions. It performs no transaction lifecycle control and no cache side effects.
+func touchAppVersionsWithTx(tx *gorm.DB, appName string) error {
+	return tx.Model(&models.Version{}).Where("app_name = ?", appName).UpdateColumn("content_changed_at", time.Now()).Error
+}
+
+// touchModuleTypeVersionsWithTx sets the moment the content of every version of the apps with modules
+// of a module type changed. It performs no transaction lifecycle control and no cache side effects.
+func touchModuleTypeVersionsWithTx(tx *gorm.DB, moduleType string) error {
+	return tx.Model(&models.Version{}).
+		Where("app_name IN (SELECT app_name FROM modules WHERE type = ?)", moduleType).
+		UpdateColumn("content_changed_at", time.Now()).Error
+}
+
+// touchPluginTypeVersionsWithTx sets the moment the content of every version with pages of a plugin type changed.
+// It performs no transaction lifecycle control and no cache side effects.
+func touchPluginTypeVersionsWithTx(tx *gorm.DB, pluginType string) error {
+	return tx.Model(&models.Version{}).
+		Where("id IN (SELECT version_id FROM pages WHERE plugin = ?)", pluginType).
+		UpdateColumn("content_changed_at", time.Now()).Error
 }
 
 // DeleteVersion method to delete a version.
@@ -587,7 +896,7 @@ func duplicatePages(tx *gorm.DB, menuItemMapping map[uint]uint, locales []string
 
 			updatePage := requests.UpdatePage{}
 			updatePage.SetPage(sourcePage)
-			if _, err := UpdatePageWithTx(tx, targetPage, &updatePage); err != nil {
+			if _, err := UpdatePageWithTx(tx, targetPage, &updatePage, targetPage.Revision); err != nil {
 				return err
 			}
 
@@ -605,7 +914,7 @@ func duplicatePages(tx *gorm.DB, menuItemMapping map[uint]uint, locales []string
 
 				updatePartial := requests.UpdatePagePartial{}
 				updatePartial.SetPagePartial(&sourcePartial, targetPartial.ID)
-				if _, err := UpdatePagePartialWithTx(tx, targetPartial, &updatePartial); err != nil {
+				if _, err := UpdatePagePartialWithTx(tx, targetPartial, &updatePartial, targetPartial.Revision); err != nil {
 					return err
 				}
 			}
@@ -640,9 +949,53 @@ func duplicateFooter(tx *gorm.DB, sourceVersionID, targetVersionID uint, locales
 		}
 
 		existingRows := make([]models.FooterRow, 0)
-		if _, err := UpdateFooterWithTx(tx, targetVersionID, locale, &existingRows, &requests.UpdateFooter{Rows: dtoRows}); err != nil {
+		revision, err := getFooterRevisionWithTx(tx, targetVersionID, locale)
+		if err != nil {
+			return err
+		}
+		if _, err := UpdateFooterWithTx(tx, targetVersionID, locale, &existingRows, &requests.UpdateFooter{Rows: dtoRows}, revision); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deletePublishedVersionFromCache deletes the version lookup of an app and the menus, footers
+// and pages of the given versions from the cache, so a (un)publish is served immediately.
+func deletePublishedVersionFromCache(appName string, versionIDs ...uint) error {
+	if err := deleteVersionsLookupFromCache(appName); err != nil {
+		return err
+	}
+
+	for _, versionID := range versionIDs {
+		if err := deleteAllVersionMenusFromCache(versionID); err != nil {
 			return err
 		}
+
+		var footerLocales []string
+		if result := database.Pg.Model(&models.FooterRow{}).Distinct("locale").Where("version_id = ?", versionID).Pluck("locale", &footerLocales); result.Error != nil {
+			return result.Error
+		}
+		for i := range footerLocales {
+			if err := deleteFooterFromCache(versionID, footerLocales[i]); err != nil {
+				return err
+			}
+		}
+
+		pages := make([]models.Page, 0)
+		if result := database.Pg.Model(&models.Page{}).
+			Select("pages.menu_item_id", "pages.locale").
+			Joins("JOIN menu_items ON menu_items.id = pages.menu_item_id").
+			Where("menu_items.version_id = ?", versionID).
+			Find(&pages); result.Error != nil {
+			return result.Error
+		}
+		for i := range pages {
+			if err := deletePageFromCache(pages[i].MenuItemID, pages[i].Locale); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -655,66 +1008,20 @@ func getVersionsLookupCacheKey(appName string) string {
 
 // isVersionsLookupInCache checks if the versions exists in the cache.
 func isVersionsLookupInCache(appName string) (bool, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Exists().Key(getVersionsLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return false, result.Error()
-	}
-
-	value, err := result.ToInt64()
-	if err != nil {
-		return false, err
-	}
-
-	return value == 1, nil
+	return cache.Exists(getVersionsLookupCacheKey(appName))
 }
 
 // getVersionsLookupFromCache gets the versions from the cache.
 func getVersionsLookupFromCache(appName string) (*[]models.Version, error) {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Get().Key(getVersionsLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return nil, result.Error()
-	}
-
-	value, err := result.ToString()
-	if err != nil {
-		return nil, err
-	}
-
-	var versions []models.Version
-	if err := json.Unmarshal([]byte(value), &versions); err != nil {
-		return nil, err
-	}
-
-	return &versions, nil
+	return cache.Get[[]models.Version](getVersionsLookupCacheKey(appName))
 }
 
 // setVersionsLookupToCache sets the versions to the cache.
 func setVersionsLookupToCache(appName string, versions *[]models.Version) error {
-	value, err := json.Marshal(versions)
-	if err != nil {
-		return err
-	}
-
-	expiration := os.Getenv("VALKEY_EXPIRATION")
-	duration, err := time.ParseDuration(expiration)
-	if err != nil {
-		return err
-	}
-
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Set().Key(getVersionsLookupCacheKey(appName)).Value(valkey.BinaryString(value)).Ex(duration).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Set(getVersionsLookupCacheKey(appName), versions)
 }
 
 // deleteVersionsLookupFromCache deletes existing versions from the cache.
 func deleteVersionsLookupFromCache(appName string) error {
-	result := cache.Valkey.Do(context.Background(), cache.Valkey.B().Del().Key(getVersionsLookupCacheKey(appName)).Build())
-	if result.Error() != nil {
-		return result.Error()
-	}
-
-	return nil
+	return cache.Delete(getVersionsLookupCacheKey(appName))
 }
@@ -0,0 +1,483 @@
+package services
+
+import (
+	"api-page/main/src/database"
+	"api-page/main/src/dto/requests"
+	"api-page/main/src/dto/responses"
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ArnoldPMolenaar/api-utils/pagination"
+	"github.com/ArnoldPMolenaar/api-utils/utils"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultWebhookTimeout is used when WEBHOOK_TIMEOUT is not set or invalid.
+const defaultWebhookTimeout = 10 * time.Second
+
+// defaultWebhookMaxAttempts is used when WEBHOOK_MAX_ATTEMPTS is not set or invalid.
+const defaultWebhookMaxAttempts = 8
+
+// defaultWebhookRetryBackoff is used when WEBHOOK_RETRY_BACKOFF is not set or invalid.
+const defaultWebhookRetryBackoff = 30 * time.Second
+
+// maxWebhookRetryDelay caps the exponential backoff between two attempts.
+const maxWebhookRetryDelay = 6 * time.Hour
+
+// webhookPayload is the body that is posted to a webhook.
+type webhookPayload struct {
+	ID         string             `json:"id"`
+	Event      enums.WebhookEvent `json:"event"`
+	AppName    string             `json:"appName"`
+	OccurredAt time.Time          `json:"occurredAt"`
+	Data       map[string]any     `json:"data"`
+}
+
+// IsWebhookDeleted method to check if a webhook is deleted.
+func IsWebhookDeleted(webhookID uint) (bool, error) {
+	if result := database.Pg.Unscoped().Limit(1).Find(&models.Webhook{}, "id = ? AND deleted_at IS NOT NULL", webhookID); result.Error != nil {
+		return false, result.Error
+	} else {
+		return result.RowsAffected == 1, nil
+	}
+}
+
+// GetWebhooks method to get paginated webhooks.
+func GetWebhooks(c fiber.Ctx) (*pagination.Model, error) {
+	webhooks := make([]models.Webhook, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"id":         true,
+		"app_name":   true,
+		"url":        true,
+		"enabled_at": true,
+		"created_at": true,
+		"updated_at": true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Limit(limit).
+		Offset(offset)
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.Webhook{})
+
+	if result := dbResult.Find(&webhooks); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedWebhooks := make([]responses.PaginatedWebhook, 0)
+	for i := range webhooks {
+		paginatedWebhook := responses.PaginatedWebhook{}
+		paginatedWebhook.SetPaginatedWebhook(&webhooks[i])
+		paginatedWebhooks = append(paginatedWebhooks, paginatedWebhook)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedWebhooks)
+
+	return &paginationModel, nil
+}
+
+// GetWebhookByID method to get a webhook by ID.
+func GetWebhookByID(webhookID uint) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+
+	if result := database.Pg.Limit(1).Find(webhook, "id = ?", webhookID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return webhook, nil
+}
+
+// GetWebhookDeliveries method to get the paginated delivery log of a webhook.
+func GetWebhookDeliveries(c fiber.Ctx, webhookID uint) (*pagination.Model, error) {
+	deliveries := make([]models.WebhookDelivery, 0)
+	values := c.Request().URI().QueryArgs()
+	allowedColumns := map[string]bool{
+		"id":              true,
+		"event":           true,
+		"status":          true,
+		"attempts":        true,
+		"next_attempt_at": true,
+		"last_attempt_at": true,
+		"response_status": true,
+		"created_at":      true,
+		"updated_at":      true,
+	}
+
+	queryFunc := pagination.Query(values, allowedColumns)
+	sortFunc := pagination.Sort(values, allowedColumns)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset := pagination.Offset(page, limit)
+	dbResult := database.Pg.Scopes(queryFunc, sortFunc).
+		Where("webhook_id = ?", webhookID).
+		Limit(limit).
+		Offset(offset)
+
+	// Without an explicit sort the newest deliveries come first.
+	if len(values.Peek("sortBy")) == 0 {
+		dbResult = dbResult.Order("id DESC")
+	}
+
+	total := int64(0)
+	dbCount := database.Pg.Scopes(queryFunc).
+		Model(&models.WebhookDelivery{}).
+		Where("webhook_id = ?", webhookID)
+
+	if result := dbResult.Find(&deliveries); result.Error != nil {
+		return nil, result.Error
+	}
+
+	dbCount.Count(&total)
+	pageCount := pagination.Count(int(total), limit)
+
+	paginatedDeliveries := make([]responses.PaginatedWebhookDelivery, 0)
+	for i := range deliveries {
+		paginatedDelivery := responses.PaginatedWebhookDelivery{}
+		paginatedDelivery.SetPaginatedWebhookDelivery(&deliveries[i])
+		paginatedDeliveries = append(paginatedDeliveries, paginatedDelivery)
+	}
+
+	paginationModel := pagination.CreatePaginationModel(limit, page, pageCount, int(total), paginatedDeliveries)
+
+	return &paginationModel, nil
+}
+
+// CreateWebhook method to create a webhook. A random secret is generated when the request has none.
+func CreateWebhook(request *requests.CreateWebhook) (*models.Webhook, error) {
+	webhook := &models.Webhook{
+		AppName:   request.AppName,
+		Url:       request.Url,
+		Events:    datatypes.NewJSONType(toWebhookEvents(request.Events)),
+		EnabledAt: utils.NewNullTime(request.EnabledAt),
+	}
+
+	if request.Secret != nil {
+		webhook.Secret = *request.Secret
+	} else {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+
+	if result := database.Pg.Create(webhook); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook method to update a webhook. The secret is kept when the request has none.
+func UpdateWebhook(oldWebhook *models.Webhook, request *requests.UpdateWebhook) (*models.Webhook, error) {
+	if oldWebhook == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	oldWebhook.Url = request.Url
+	oldWebhook.Events = datatypes.NewJSONType(toWebhookEvents(request.Events))
+	oldWebhook.EnabledAt = utils.NewNullTime(request.EnabledAt)
+	if request.Secret != nil {
+		oldWebhook.Secret = *request.Secret
+	}
+
+	if result := database.Pg.Save(oldWebhook); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return oldWebhook, nil
+}
+
+// DeleteWebhook method to delete a webhook.
+func DeleteWebhook(webhookID uint) error {
+	return database.Pg.Delete(&models.Webhook{}, webhookID).Error
+}
+
+// RestoreWebhook method to restore a deleted webhook.
+func RestoreWebhook(webhookID uint) error {
+	return database.Pg.Unscoped().Model(&models.Webhook{}).Where("id = ?", webhookID).Update("deleted_at", nil).Error
+}
+
+// GetWebhookTimeout method to get how long a webhook may take to respond.
+func GetWebhookTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return defaultWebhookTimeout
+	}
+
+	return timeout
+}
+
+// GetWebhookMaxAttempts method to get how often a delivery is attempted before it fails.
+func GetWebhookMaxAttempts() uint {
+	attempts, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_ATTEMPTS"), 10, 32)
+	if err != nil || attempts == 0 {
+		return defaultWebhookMaxAttempts
+	}
+
+	return uint(attempts)
+}
+
+// GetWebhookRetryDelay method to get the delay before the next attempt of a delivery.
+// The delay starts at WEBHOOK_RETRY_BACKOFF and doubles with every failed attempt.
+func GetWebhookRetryDelay(attempts uint) time.Duration {
+	delay, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_BACKOFF"))
+	if err != nil || delay <= 0 {
+		delay = defaultWebhookRetryBackoff
+	}
+
+	for i := uint(1); i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxWebhookRetryDelay)
+}
+
+// ProcessDueWebhookDeliveries method to send all deliveries whose attempt is due.
+// Each delivery is claimed in its own transaction with a row lock that skips rows
+// already claimed by another replica, and leased for the duration of the attempt.
+func ProcessDueWebhookDeliveries(now time.Time) (int, error) {
+	processed := 0
+
+	for {
+		delivery, err := claimNextWebhookDelivery(now)
+		if err != nil {
+			return processed, err
+		} else if delivery == nil {
+			return processed, nil
+		}
+
+		if err := attemptWebhookDelivery(delivery); err != nil {
+			return processed, err
+		}
+
+		processed++
+	}
+}
+
+// SignWebhookPayload method to sign the body of a delivery as it is sent in the X-Webhook-Signature header.
+// The signature is the hex encoded HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret of the webhook.
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// enqueueWebhookEventWithTx queues a delivery of the event for every enabled webhook of the app that is subscribed to it.
+// It performs no transaction lifecycle control and no cache side effects.
+func enqueueWebhookEventWithTx(tx *gorm.DB, appName string, event enums.WebhookEvent, data map[string]any) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	webhooks := make([]models.Webhook, 0)
+	if result := tx.Find(&webhooks, "app_name = ? AND enabled_at IS NOT NULL", appName); result.Error != nil {
+		return result.Error
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for i := range webhooks {
+		if webhooks[i].IsSubscribed(event) {
+			deliveries = append(deliveries, models.WebhookDelivery{WebhookID: webhooks[i].ID, Event: event, Status: enums.WEBHOOK_PENDING})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(webhookPayload{ID: id.String(), Event: event, AppName: appName, OccurredAt: now, Data: data})
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		deliveries[i].Payload = payload
+		deliveries[i].NextAttemptAt = now
+	}
+
+	return tx.Create(&deliveries).Error
+}
+
+// enqueuePageEventWithTx queues a page event of a page for the webhooks of its app.
+// It performs no transaction lifecycle control and no cache side effects.
+func enqueuePageEventWithTx(tx *gorm.DB, event enums.WebhookEvent, menuItemID uint, locale string) error {
+	if tx == nil {
+		return gorm.ErrInvalidDB
+	}
+
+	version := &models.Version{}
+	if result := tx.Limit(1).Find(version, "id = (SELECT version_id FROM menu_items WHERE id = ?)", menuItemID); result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return enqueueWebhookEventWithTx(tx, version.AppName, event, map[string]any{
+		"versionId":  version.ID,
+		"menuItemId": menuItemID,
+		"locale":     locale,
+	})
+}
+
+// claimNextWebhookDelivery claims the next due delivery of an enabled webhook.
+// It returns nil when no delivery is due.
+func claimNextWebhookDelivery(now time.Time) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+
+	if err := database.Pg.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND next_attempt_at <= ? AND EXISTS (SELECT 1 FROM webhooks w WHERE w.id = webhook_deliveries.webhook_id AND w.deleted_at IS NULL AND w.enabled_at IS NOT NULL)", enums.WEBHOOK_PENDING, now).
+			Order("next_attempt_at ASC").
+			Limit(1).
+			Find(delivery)
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// The lease outlasts the attempt, so a delivery of a crashed replica is retried.
+		return tx.Model(delivery).Update("next_attempt_at", now.Add(2*GetWebhookTimeout())).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	if delivery.ID == 0 {
+		return nil, nil
+	}
+
+	if result := database.Pg.Limit(1).Find(&delivery.Webhook, "id = ?", delivery.WebhookID); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return delivery, nil
+}
+
+// attemptWebhookDelivery sends a delivery and records the outcome of the attempt.
+func attemptWebhookDelivery(delivery *models.WebhookDelivery) error {
+	statusCode, err := sendWebhookDelivery(delivery)
+	setWebhookDeliveryAttempt(delivery, statusCode, err, time.Now())
+
+	return database.Pg.Model(delivery).Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "error", "updated_at").Updates(delivery).Error
+}
+
+// setWebhookDeliveryAttempt sets the outcome of an attempt on a delivery.
+// A failed attempt is retried with an exponential backoff until the maximum attempts are reached.
+func setWebhookDeliveryAttempt(delivery *models.WebhookDelivery, statusCode int, err error, now time.Time) {
+	delivery.Attempts++
+	delivery.LastAttemptAt = sql.NullTime{Time: now, Valid: true}
+	delivery.ResponseStatus = sql.Null[int]{V: statusCode, Valid: statusCode != 0}
+	delivery.Error = sql.NullString{}
+
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.Status = enums.WEBHOOK_SUCCEEDED
+	} else {
+		if err != nil {
+			delivery.Error = sql.NullString{String: err.Error(), Valid: true}
+		} else {
+			delivery.Error = sql.NullString{String: fmt.Sprintf("unexpected status code %d", statusCode), Valid: true}
+		}
+
+		if delivery.Attempts >= GetWebhookMaxAttempts() {
+			delivery.Status = enums.WEBHOOK_FAILED
+		} else {
+			delivery.NextAttemptAt = now.Add(GetWebhookRetryDelay(delivery.Attempts))
+		}
+	}
+}
+
+// sendWebhookDelivery posts the signed payload of a delivery to its webhook and returns the status code of the response.
+func sendWebhookDelivery(delivery *models.WebhookDelivery) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, delivery.Webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	request.Header.Set(fiber.HeaderUserAgent, "api-page-webhook")
+	request.Header.Set("X-Webhook-Event", delivery.Event.String())
+	request.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", SignWebhookPayload(delivery.Webhook.Secret, timestamp, delivery.Payload))
+
+	client := &http.Client{Timeout: GetWebhookTimeout()}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Drain a bounded part of the body, so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	return response.StatusCode, nil
+}
+
+// generateWebhookSecret generates a random secret to sign the deliveries of a webhook.
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// toWebhookEvents converts the events of a request.
+func toWebhookEvents(events []string) []enums.WebhookEvent {
+	result := make([]enums.WebhookEvent, 0, len(events))
+	for i := range events {
+		result = append(result, enums.WebhookEvent(events[i]))
+	}
+
+	return result
+}
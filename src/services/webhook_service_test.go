@@ -0,0 +1,143 @@
+package services
+
+import (
+	"api-page/main/src/enums"
+	"api-page/main/src/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newWebhookReceiver starts a receiver that verifies the signature of every delivery
+// with the secret and responds with the status code.
+func newWebhookReceiver(t *testing.T, secret string, statusCode int) (*httptest.Server, *[]bool) {
+	t.Helper()
+
+	verified := make([]bool, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+
+		signature := SignWebhookPayload(secret, r.Header.Get("X-Webhook-Timestamp"), body)
+		verified = append(verified, r.Header.Get("X-Webhook-Signature") == signature)
+
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &verified
+}
+
+// newWebhookDelivery creates a pending delivery to the url signed with the secret.
+func newWebhookDelivery(url, secret string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        1,
+		WebhookID: 1,
+		Event:     enums.WEBHOOK_PAGE_UPDATED,
+		Payload:   []byte(`{"id":"1","event":"page.updated","appName":"app","data":{"menuItemId":1,"locale":"en"}}`),
+		Status:    enums.WEBHOOK_PENDING,
+		Webhook:   models.Webhook{Url: url, Secret: secret},
+	}
+}
+
+func TestSendWebhookDeliverySignature(t *testing.T) {
+	server, verified := newWebhookReceiver(t, "secret", http.StatusNoContent)
+	delivery := newWebhookDelivery(server.URL, "secret")
+
+	statusCode, err := sendWebhookDelivery(delivery)
+	if err != nil {
+		t.Fatalf("sendWebhookDelivery: %v", err)
+	}
+	if statusCode != http.StatusNoContent {
+		t.Fatalf("status code = %d, want %d", statusCode, http.StatusNoContent)
+	}
+	if len(*verified) != 1 || !(*verified)[0] {
+		t.Fatalf("signature not verified by the receiver: %v", *verified)
+	}
+
+	setWebhookDeliveryAttempt(delivery, statusCode, err, time.Now())
+	if delivery.Status != enums.WEBHOOK_SUCCEEDED {
+		t.Fatalf("status = %s, want %s", delivery.Status, enums.WEBHOOK_SUCCEEDED)
+	}
+}
+
+func TestSendWebhookDeliveryWrongSecret(t *testing.T) {
+	server, verified := newWebhookReceiver(t, "secret", http.StatusOK)
+
+	if _, err := sendWebhookDelivery(newWebhookDelivery(server.URL, "other")); err != nil {
+		t.Fatalf("sendWebhookDelivery: %v", err)
+	}
+	if len(*verified) != 1 || (*verified)[0] {
+		t.Fatalf("signature of another secret verified by the receiver: %v", *verified)
+	}
+}
+
+func TestSetWebhookDeliveryAttemptRetry(t *testing.T) {
+	t.Setenv("WEBHOOK_RETRY_BACKOFF", "10s")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+
+	server, _ := newWebhookReceiver(t, "secret", http.StatusInternalServerError)
+	delivery := newWebhookDelivery(server.URL, "secret")
+
+	statusCode, err := sendWebhookDelivery(delivery)
+	if err != nil {
+		t.Fatalf("sendWebhookDelivery: %v", err)
+	}
+
+	now := time.Now()
+	setWebhookDeliveryAttempt(delivery, statusCode, err, now)
+
+	if delivery.Status != enums.WEBHOOK_PENDING {
+		t.Fatalf("status = %s, want %s", delivery.Status, enums.WEBHOOK_PENDING)
+	}
+	if delivery.Attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", delivery.Attempts)
+	}
+	if want := now.Add(GetWebhookRetryDelay(1)); !delivery.NextAttemptAt.Equal(want) {
+		t.Fatalf("next attempt at = %s, want %s", delivery.NextAttemptAt, want)
+	}
+	if !delivery.ResponseStatus.Valid || delivery.ResponseStatus.V != http.StatusInternalServerError {
+		t.Fatalf("response status = %v, want %d", delivery.ResponseStatus, http.StatusInternalServerError)
+	}
+	if !delivery.Error.Valid {
+		t.Fatal("error not recorded")
+	}
+}
+
+func TestSetWebhookDeliveryAttemptFailed(t *testing.T) {
+	t.Setenv("WEBHOOK_RETRY_BACKOFF", "10s")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+
+	server, _ := newWebhookReceiver(t, "secret", http.StatusBadGateway)
+	delivery := newWebhookDelivery(server.URL, "secret")
+
+	for attempt := uint(1); attempt <= GetWebhookMaxAttempts(); attempt++ {
+		if delivery.Status != enums.WEBHOOK_PENDING {
+			t.Fatalf("status before attempt %d = %s, want %s", attempt, delivery.Status, enums.WEBHOOK_PENDING)
+		}
+
+		statusCode, err := sendWebhookDelivery(delivery)
+		setWebhookDeliveryAttempt(delivery, statusCode, err, time.Now())
+	}
+
+	if delivery.Status != enums.WEBHOOK_FAILED {
+		t.Fatalf("status = %s, want %s", delivery.Status, enums.WEBHOOK_FAILED)
+	}
+	if delivery.Attempts != GetWebhookMaxAttempts() {
+		t.Fatalf("attempts = %d, want %d", delivery.Attempts, GetWebhookMaxAttempts())
+	}
+}
+
+func TestGetWebhookRetryDelay(t *testing.T) {
+	t.Setenv("WEBHOOK_RETRY_BACKOFF", "10s")
+
+	for attempts, want := range map[uint]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 100: maxWebhookRetryDelay} {
+		if got := GetWebhookRetryDelay(attempts); got != want {
+			t.Errorf("GetWebhookRetryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"api-page/main/src/enums"
 	"encoding/json"
+	"reflect"
+	"regexp"
 
 	util "github.com/ArnoldPMolenaar/api-utils/utils"
 	"github.com/go-playground/validator/v10"
@@ -9,6 +12,9 @@ import (
 
 var Validate = util.NewValidator()
 
+// slugPattern matches lowercase URL path segments such as "about-us".
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
 func init() {
 	_ = Validate.RegisterValidation("validjson", func(fl validator.FieldLevel) bool {
 		raw, ok := fl.Field().Interface().(json.RawMessage)
@@ -18,4 +24,45 @@ func init() {
 		var v any
 		return json.Unmarshal(raw, &v) == nil
 	})
+
+	_ = Validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+
+	_ = Validate.RegisterValidation("webhookevent", func(fl validator.FieldLevel) bool {
+		return enums.WebhookEvent(fl.Field().String()).IsValid()
+	})
+
+	_ = Validate.RegisterValidation("modulesettingstransform", func(fl validator.FieldLevel) bool {
+		return enums.ModuleSettingsTransformOp(fl.Field().String()).IsValid()
+	})
+
+	_ = Validate.RegisterValidation("modulebehavior", func(fl validator.FieldLevel) bool {
+		return enums.ModuleBehavior(fl.Field().String()).IsValid()
+	})
+
+	_ = Validate.RegisterValidation("indexing", func(fl validator.FieldLevel) bool {
+		return enums.Indexing(fl.Field().String()).IsValid()
+	})
+
+	// indexingvalue validates a value against the indexing option in the Option field of the same struct.
+	_ = Validate.RegisterValidation("indexingvalue", func(fl validator.FieldLevel) bool {
+		option := reflect.Indirect(fl.Parent()).FieldByName("Option")
+		if !option.IsValid() || option.Kind() != reflect.String {
+			return false
+		}
+
+		var value *string
+		if field := fl.Field(); field.Kind() == reflect.Ptr {
+			if !field.IsNil() {
+				v := field.Elem().String()
+				value = &v
+			}
+		} else if field.Kind() == reflect.String {
+			v := field.String()
+			value = &v
+		}
+
+		return enums.Indexing(option.String()).IsValidValue(value)
+	}, true)
 }